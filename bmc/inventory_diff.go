@@ -0,0 +1,266 @@
+package bmc
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// ChangeType is the type of a change between two device inventory snapshots
+type ChangeType string
+
+const (
+	// ComponentAdded is a component present only in the new inventory
+	ComponentAdded ChangeType = "added"
+	// ComponentRemoved is a component present only in the old inventory
+	ComponentRemoved ChangeType = "removed"
+	// AttributeChanged is a component attribute with a different value in the new inventory
+	AttributeChanged ChangeType = "changed"
+)
+
+// Change is a difference between two device inventory snapshots
+//
+// The Path identifies the component by its kind and identifier - drives[PHYF001303ED480BGN], memory[DDR4_A1],
+// suffixed by the attribute for attribute changes - bios.firmware.installed, drives[SATA_4].serial.
+// For added and removed components the Old and New values summarize the component vendor, model and serial.
+type Change struct {
+	Type ChangeType `json:"type"`
+	Path string     `json:"path"`
+	Old  string     `json:"old,omitempty"`
+	New  string     `json:"new,omitempty"`
+}
+
+// DiffDevices returns the components added, removed and the component attributes changed between
+// the old and new device inventory snapshots, sorted by the component path.
+//
+// Components are matched by their serial, slot or identifier depending on the component kind, memory modules and CPUs
+// by the slot so a swapped module is reported as a changed serial, drives by the serial so a replaced drive is
+// reported as a removed and an added drive. Components without an identifier are matched by their position.
+func DiffDevices(oldDevice, newDevice *common.Device) []Change {
+	oldComponents := deviceComponents(oldDevice)
+	newComponents := deviceComponents(newDevice)
+
+	changes := []Change{}
+
+	for path, oldAttributes := range oldComponents {
+		newAttributes, exists := newComponents[path]
+		if !exists {
+			changes = append(changes, Change{Type: ComponentRemoved, Path: path, Old: componentSummary(oldAttributes)})
+			continue
+		}
+
+		for _, attribute := range attributeNames(oldAttributes, newAttributes) {
+			if oldAttributes[attribute] == newAttributes[attribute] {
+				continue
+			}
+
+			changes = append(changes, Change{
+				Type: AttributeChanged,
+				Path: joinPath(path, attribute),
+				Old:  oldAttributes[attribute],
+				New:  newAttributes[attribute],
+			})
+		}
+	}
+
+	for path, newAttributes := range newComponents {
+		if _, exists := oldComponents[path]; !exists {
+			changes = append(changes, Change{Type: ComponentAdded, Path: path, New: componentSummary(newAttributes)})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Path == changes[j].Path {
+			return changes[i].Type < changes[j].Type
+		}
+
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
+
+// componentAttributes are the attributes compared for a component, keyed by the attribute name
+type componentAttributes map[string]string
+
+// deviceComponents returns the attributes of the device and its components, keyed by the component path
+func deviceComponents(device *common.Device) map[string]componentAttributes {
+	components := map[string]componentAttributes{}
+
+	// a nil device is compared as a device without attributes and components
+	if device == nil {
+		device = &common.Device{}
+	}
+
+	add := func(kind string, idx int, ids []string, c *common.Common, extra componentAttributes) {
+		path := kind
+		if idx >= 0 {
+			path = kind + "[" + componentID(idx, ids...) + "]"
+		}
+
+		attributes := commonAttributes(c)
+		for k, v := range extra {
+			if v != "" && v != "0" {
+				attributes[k] = v
+			}
+		}
+
+		components[path] = attributes
+	}
+
+	// the device attributes are compared at the root path
+	add("", -1, nil, &device.Common, componentAttributes{"hardware_type": device.HardwareType, "chassis": device.Chassis})
+
+	if device.BIOS != nil {
+		add("bios", -1, nil, &device.BIOS.Common, nil)
+	}
+
+	if device.BMC != nil {
+		add("bmc", -1, nil, &device.BMC.Common, nil)
+	}
+
+	if device.Mainboard != nil {
+		add("mainboard", -1, nil, &device.Mainboard.Common, nil)
+	}
+
+	for idx, cpld := range device.CPLDs {
+		add("cplds", idx, []string{cpld.Description}, &cpld.Common, nil)
+	}
+
+	for idx, tpm := range device.TPMs {
+		add("tpms", idx, []string{tpm.Serial}, &tpm.Common, nil)
+	}
+
+	for idx, gpu := range device.GPUs {
+		add("gpus", idx, []string{gpu.Serial}, &gpu.Common, nil)
+	}
+
+	for idx, cpu := range device.CPUs {
+		add("cpus", idx, []string{cpu.Slot, cpu.ID}, &cpu.Common, componentAttributes{
+			"cores":          strconv.Itoa(cpu.Cores),
+			"threads":        strconv.Itoa(cpu.Threads),
+			"clock_speed_hz": strconv.FormatInt(cpu.ClockSpeedHz, 10),
+		})
+	}
+
+	for idx, memory := range device.Memory {
+		add("memory", idx, []string{memory.Slot, memory.ID}, &memory.Common, componentAttributes{
+			"part_number":    memory.PartNumber,
+			"size_bytes":     strconv.FormatInt(memory.SizeBytes, 10),
+			"clock_speed_hz": strconv.FormatInt(memory.ClockSpeedHz, 10),
+		})
+	}
+
+	for idx, nic := range device.NICs {
+		macs := componentAttributes{}
+		for portIdx, port := range nic.NICPorts {
+			macs["ports["+componentID(portIdx, port.ID)+"].mac_address"] = port.MacAddress
+		}
+
+		add("nics", idx, []string{nic.ID, nic.Serial}, &nic.Common, macs)
+	}
+
+	for idx, drive := range device.Drives {
+		add("drives", idx, []string{drive.Serial, drive.ID}, &drive.Common, componentAttributes{
+			"type":           drive.Type,
+			"capacity_bytes": strconv.FormatInt(drive.CapacityBytes, 10),
+		})
+	}
+
+	for idx, controller := range device.StorageControllers {
+		add("storage_controllers", idx, []string{controller.ID, controller.Serial}, &controller.Common, nil)
+	}
+
+	for idx, psu := range device.PSUs {
+		add("psus", idx, []string{psu.ID, psu.Serial}, &psu.Common, componentAttributes{
+			"power_capacity_watts": strconv.FormatInt(psu.PowerCapacityWatts, 10),
+		})
+	}
+
+	for idx, enclosure := range device.Enclosures {
+		extra := componentAttributes{}
+		if enclosure.Firmware != nil {
+			extra["firmware.installed"] = enclosure.Firmware.Installed
+		}
+
+		add("enclosures", idx, []string{enclosure.ID, enclosure.Serial}, &enclosure.Common, extra)
+	}
+
+	return components
+}
+
+// commonAttributes returns the compared attributes common to all components
+func commonAttributes(c *common.Common) componentAttributes {
+	attributes := componentAttributes{}
+
+	for k, v := range map[string]string{
+		"vendor":       c.Vendor,
+		"model":        c.Model,
+		"serial":       c.Serial,
+		"product_name": c.ProductName,
+	} {
+		if v != "" {
+			attributes[k] = v
+		}
+	}
+
+	if c.Firmware != nil && c.Firmware.Installed != "" {
+		attributes["firmware.installed"] = c.Firmware.Installed
+	}
+
+	return attributes
+}
+
+// componentID returns the first non empty identifier, or the component position when none is set
+func componentID(idx int, ids ...string) string {
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" {
+			return id
+		}
+	}
+
+	return strconv.Itoa(idx)
+}
+
+// componentSummary returns the component vendor, model and serial separated by spaces
+func componentSummary(attributes componentAttributes) string {
+	fields := []string{}
+
+	for _, k := range []string{"vendor", "model", "serial"} {
+		if v := attributes[k]; v != "" {
+			fields = append(fields, v)
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// attributeNames returns the sorted attribute names set in any of the given attributes
+func attributeNames(attributes ...componentAttributes) []string {
+	names := []string{}
+	seen := map[string]bool{}
+
+	for _, a := range attributes {
+		for name := range a {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// joinPath returns the attribute path for the component path - bios.firmware.installed, vendor
+func joinPath(path, attribute string) string {
+	if path == "" {
+		return attribute
+	}
+
+	return path + "." + attribute
+}
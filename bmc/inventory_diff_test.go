@@ -0,0 +1,80 @@
+package bmc
+
+import (
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDevice() *common.Device {
+	return &common.Device{
+		Common: common.Common{Vendor: "ASRockRack", Model: "E3C246D4I-NL", Serial: "M80-D1001000123"},
+		BIOS:   &common.BIOS{Common: common.Common{Firmware: &common.Firmware{Installed: "L2.07B"}}},
+		BMC:    &common.BMC{Common: common.Common{Firmware: &common.Firmware{Installed: "0.01.00"}}},
+		Memory: []*common.Memory{
+			{Slot: "DDR4_A1", Common: common.Common{Vendor: "Micron", Serial: "2724B52D"}, SizeBytes: 17179869184},
+			{Slot: "DDR4_B1", Common: common.Common{Vendor: "Micron", Serial: "2724B58A"}, SizeBytes: 17179869184},
+		},
+		Drives: []*common.Drive{
+			{ID: "SATA_4", Common: common.Common{Vendor: "Intel", Model: "SSDSC2KB480G8", Serial: "PHYF001303ED480BGN"}},
+			{ID: "SATA_5", Common: common.Common{Vendor: "Intel", Model: "SSDSC2KB480G8", Serial: "BTYF01940L38480BGN"}},
+		},
+	}
+}
+
+func TestDiffDevicesUnchanged(t *testing.T) {
+	assert.Equal(t, []Change{}, DiffDevices(testDevice(), testDevice()))
+}
+
+func TestDiffDevicesDriveRemoved(t *testing.T) {
+	current := testDevice()
+	current.Drives = current.Drives[:1]
+
+	expected := []Change{
+		{Type: ComponentRemoved, Path: "drives[BTYF01940L38480BGN]", Old: "Intel SSDSC2KB480G8 BTYF01940L38480BGN"},
+	}
+
+	assert.Equal(t, expected, DiffDevices(testDevice(), current))
+}
+
+func TestDiffDevicesFirmwareUpgraded(t *testing.T) {
+	current := testDevice()
+	current.BIOS.Firmware.Installed = "L2.21A"
+	current.BMC.Firmware.Installed = "1.80.00"
+
+	expected := []Change{
+		{Type: AttributeChanged, Path: "bios.firmware.installed", Old: "L2.07B", New: "L2.21A"},
+		{Type: AttributeChanged, Path: "bmc.firmware.installed", Old: "0.01.00", New: "1.80.00"},
+	}
+
+	assert.Equal(t, expected, DiffDevices(testDevice(), current))
+}
+
+func TestDiffDevicesComponentsSwapped(t *testing.T) {
+	current := testDevice()
+
+	// memory module swapped in the same slot
+	current.Memory[1].Serial = "3A11C0FF"
+
+	// drive replaced
+	current.Drives[1] = &common.Drive{
+		ID:     "SATA_5",
+		Common: common.Common{Vendor: "Samsung", Model: "MZ7LH480", Serial: "S4EVNF0M123456"},
+	}
+
+	expected := []Change{
+		{Type: ComponentRemoved, Path: "drives[BTYF01940L38480BGN]", Old: "Intel SSDSC2KB480G8 BTYF01940L38480BGN"},
+		{Type: ComponentAdded, Path: "drives[S4EVNF0M123456]", New: "Samsung MZ7LH480 S4EVNF0M123456"},
+		{Type: AttributeChanged, Path: "memory[DDR4_B1].serial", Old: "2724B58A", New: "3A11C0FF"},
+	}
+
+	assert.Equal(t, expected, DiffDevices(testDevice(), current))
+}
+
+func TestDiffDevicesNil(t *testing.T) {
+	changes := DiffDevices(nil, testDevice())
+
+	assert.Contains(t, changes, Change{Type: AttributeChanged, Path: "model", New: "E3C246D4I-NL"})
+	assert.Contains(t, changes, Change{Type: ComponentAdded, Path: "bios"})
+}
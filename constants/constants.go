@@ -1,6 +1,9 @@
 package constants
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
 const (
 	// Unknown is the constant that defines unknown things
@@ -24,6 +27,8 @@ const (
 	Quanta = "Quanta"
 	// Quanta is the contant to identify Intel hardware
 	Intel = "Intel"
+	// ASRockRack is the constant to identify ASRockRack hardware
+	ASRockRack = "ASRockRack"
 
 	// Redfish firmware apply at constants
 	// FirmwareApplyImmediate sets the firmware to be installed immediately after upload
@@ -41,6 +46,14 @@ const (
 	// no action is required from the callers part in this state
 	FirmwareInstallInitializing = "initializing"
 
+	// FirmwareInstallUploading indicates the firmware image is being uploaded to the device
+	// no action is required from the callers part in this state
+	FirmwareInstallUploading = "uploading"
+
+	// FirmwareInstallVerifying indicates the device is verifying the uploaded firmware image
+	// no action is required from the callers part in this state
+	FirmwareInstallVerifying = "verifying"
+
 	// FirmwareInstallQueued indicates the device has queued the update, but has not started the update task yet
 	// this covers the redfish states - 'pending', 'new'
 	// no action is required from the callers part in this state
@@ -72,6 +85,43 @@ const (
 	POSTStateUEFI     = "uefi"
 	POSTStateOS       = "grub/os"
 	POSTCodeUnknown   = "unknown"
+
+	// Drive protocol identifiers
+	DriveProtocolSATA = "SATA"
+	DriveProtocolSAS  = "SAS"
+	DriveProtocolNVMe = "NVMe"
+
+	// Drive media identifiers
+	DriveMediaSSD = "SSD"
+	DriveMediaHDD = "HDD"
+)
+
+// BootDevice is a boot device identifier
+type BootDevice string
+
+// Boot device identifiers
+const (
+	BootDevicePXE     BootDevice = "pxe"
+	BootDeviceDisk    BootDevice = "disk"
+	BootDeviceCDROM   BootDevice = "cdrom"
+	BootDeviceUSB     BootDevice = "usb"
+	BootDeviceBIOS    BootDevice = "bios"
+	BootDeviceUnknown BootDevice = "unknown"
+)
+
+// BMCRole is the role of a BMC in a multi-BMC chassis
+type BMCRole string
+
+// BMC role identifiers
+const (
+	// BMCRolePrimary is the BMC managing the shared chassis components - fans, PSUs
+	BMCRolePrimary BMCRole = "primary"
+	// BMCRoleSecondary is a node BMC not managing the shared chassis components
+	BMCRoleSecondary BMCRole = "secondary"
+	// BMCRoleStandalone is the BMC of a single node chassis
+	BMCRoleStandalone BMCRole = "standalone"
+	// BMCRoleUnknown is a BMC role not reported by the firmware
+	BMCRoleUnknown BMCRole = "unknown"
 )
 
 // ListSupportedVendors  returns a list of supported vendors
@@ -97,3 +147,92 @@ func VendorFromProductName(productName string) string {
 		return productName
 	}
 }
+
+// NormalizeVendor returns the canonical vendor name for the given manufacturer string,
+// as reported by the FRU or hardware inventory across firmware versions.
+//
+// the manufacturer string with surrounding whitespace trimmed is returned when the vendor is not known.
+func NormalizeVendor(manufacturer string) string {
+	n := strings.ToLower(strings.Join(strings.Fields(manufacturer), ""))
+	switch {
+	case strings.HasPrefix(n, "asrock"):
+		return ASRockRack
+	case strings.HasPrefix(n, "supermicro"):
+		return Supermicro
+	case strings.HasPrefix(n, "dell"):
+		return Dell
+	case strings.HasPrefix(n, "intel"):
+		return Intel
+	case strings.HasPrefix(n, "quanta"):
+		return Quanta
+	case n == "hp", strings.HasPrefix(n, "hewlett"), strings.HasPrefix(n, "hpe"):
+		return HP
+	default:
+		return strings.TrimSpace(manufacturer)
+	}
+}
+
+// NormalizeModel returns the given model string with surrounding whitespace trimmed
+// and repeated whitespace collapsed.
+func NormalizeModel(model string) string {
+	return strings.Join(strings.Fields(model), " ")
+}
+
+// seagateHDDRegexp matches the Seagate HDD model numbers - ST<capacity><family>, the Nytro SSD families (FN, FM, FP) are not matched.
+var seagateHDDRegexp = regexp.MustCompile(`^st\d+(dm|lm|nm|ne|nt|vn|vx)`)
+
+// DriveProtocolFromProductName attempts to identify the drive protocol - SATA, SAS, NVMe from the given drive productname
+//
+// an empty string is returned when the protocol could not be identified,
+// this includes the drive families sold in both SATA and SAS variants - Seagate Exos, Toshiba MG.
+func DriveProtocolFromProductName(productName string) string {
+	n := strings.ToLower(productName)
+	switch {
+	case strings.Contains(n, "nvme"),
+		// Intel DC P series
+		strings.HasPrefix(n, "intel ssdpe"), strings.HasPrefix(n, "ssdpe"),
+		// Samsung PM9A3, PM983, PM1733
+		strings.HasPrefix(n, "samsung mzql"), strings.HasPrefix(n, "mzql"),
+		strings.HasPrefix(n, "samsung mz1l"), strings.HasPrefix(n, "mz1l"),
+		strings.HasPrefix(n, "samsung mzwl"), strings.HasPrefix(n, "mzwl"),
+		strings.HasPrefix(n, "samsung mzvl"), strings.HasPrefix(n, "mzvl"),
+		// Micron 7300, 7450
+		strings.HasPrefix(n, "micron_7"), strings.HasPrefix(n, "mtfdhb"), strings.HasPrefix(n, "mtfdkc"):
+		return DriveProtocolNVMe
+	case strings.Contains(n, "sas"),
+		// HGST Ultrastar SAS
+		strings.HasPrefix(n, "huc"):
+		return DriveProtocolSAS
+	case strings.Contains(n, "sata"),
+		// Intel DC S series
+		strings.HasPrefix(n, "intel ssdsc"), strings.HasPrefix(n, "ssdsc"),
+		// Samsung PM883, PM893
+		strings.HasPrefix(n, "samsung mz7"), strings.HasPrefix(n, "mz7"),
+		// Micron 5200, 5300
+		strings.HasPrefix(n, "micron_5"), strings.HasPrefix(n, "mtfdda"),
+		// Western Digital SATA HDDs
+		strings.HasPrefix(n, "wdc wd"):
+		return DriveProtocolSATA
+	default:
+		return ""
+	}
+}
+
+// DriveMediaFromProductName attempts to identify the drive media - SSD, HDD from the given drive productname
+//
+// an empty string is returned when the media could not be identified.
+func DriveMediaFromProductName(productName string) string {
+	n := strings.ToLower(productName)
+	switch {
+	case strings.Contains(n, "ssd"), strings.Contains(n, "nvme"),
+		strings.HasPrefix(n, "samsung mz"), strings.HasPrefix(n, "mz"),
+		strings.HasPrefix(n, "micron_"), strings.HasPrefix(n, "mtfd"):
+		return DriveMediaSSD
+	case seagateHDDRegexp.MatchString(n), strings.HasPrefix(n, "wdc wd"), strings.HasPrefix(n, "hgst"),
+		strings.HasPrefix(n, "huh"), strings.HasPrefix(n, "hus"), strings.HasPrefix(n, "huc"),
+		strings.HasPrefix(n, "toshiba mg"), strings.HasPrefix(n, "mg0"):
+		return DriveMediaHDD
+	default:
+		return ""
+	}
+}
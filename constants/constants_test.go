@@ -0,0 +1,109 @@
+package constants
+
+import "testing"
+
+func TestNormalizeVendor(t *testing.T) {
+	testCases := []struct {
+		manufacturer string
+		expected     string
+	}{
+		{"ASRockRack", ASRockRack},
+		{"ASRock", ASRockRack},
+		{"ASROCK RACK", ASRockRack},
+		{"ASRock Rack Incorporation", ASRockRack},
+		{" asrockrack\n", ASRockRack},
+		{"Supermicro", Supermicro},
+		{"Super Micro Computer, Inc.", Supermicro},
+		{"Dell Inc.", Dell},
+		{"Intel Corporation", Intel},
+		{"HPE", HP},
+		{"Hewlett Packard Enterprise", HP},
+		{"Quanta Cloud Technology Inc.", Quanta},
+		{" Packet ", "Packet"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.manufacturer, func(t *testing.T) {
+			if got := NormalizeVendor(tc.manufacturer); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeModel(t *testing.T) {
+	testCases := []struct {
+		model    string
+		expected string
+	}{
+		{"E3C246D4I-NL", "E3C246D4I-NL"},
+		{" E3C246D4I-NL\n", "E3C246D4I-NL"},
+		{"ROMED8HM3  ", "ROMED8HM3"},
+		{"X11SCH-F  Rev 1.01", "X11SCH-F Rev 1.01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.model, func(t *testing.T) {
+			if got := NormalizeModel(tc.model); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDriveProtocolFromProductName(t *testing.T) {
+	testCases := []struct {
+		productName string
+		expected    string
+	}{
+		{"INTEL SSDPE2KX019T8", DriveProtocolNVMe},
+		{"SAMSUNG MZ1LB960HAJQ-00007", DriveProtocolNVMe},
+		{"INTEL SSDSC2KB480G8", DriveProtocolSATA},
+		{"Micron_5300_MTFDDAK480TDS", DriveProtocolSATA},
+		{"WDC WD4003FRYZ-01F0DB0", DriveProtocolSATA},
+		{"HUC101212CSS600", DriveProtocolSAS},
+		{"KPM5XVUG960G SAS SSD", DriveProtocolSAS},
+		// sold in both SATA and SAS variants
+		{"ST4000NM0035-1V4107", ""},
+		{"ST4000NM0025", ""},
+		{"TOSHIBA MG04ACA400E", ""},
+		{"MG04SCA400E", ""},
+		{"ACME DRIVE", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.productName, func(t *testing.T) {
+			if got := DriveProtocolFromProductName(tc.productName); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestDriveMediaFromProductName(t *testing.T) {
+	testCases := []struct {
+		productName string
+		expected    string
+	}{
+		{"INTEL SSDSC2KB480G8", DriveMediaSSD},
+		{"SAMSUNG MZ7LH480HAHQ-00005", DriveMediaSSD},
+		{"Micron_5300_MTFDDAK480TDS", DriveMediaSSD},
+		{"ST4000NM0025", DriveMediaHDD},
+		{"ST2000DM008-2FR102", DriveMediaHDD},
+		{"ST8000VN004-2M2101", DriveMediaHDD},
+		{"MG04SCA400E", DriveMediaHDD},
+		{"HUC101212CSS600", DriveMediaHDD},
+		// Seagate Nytro SSD
+		{"ST480FN0021", ""},
+		{"ACME DRIVE", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.productName, func(t *testing.T) {
+			if got := DriveMediaFromProductName(tc.productName); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package asrockrack
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// bmcBaseURL returns the base URL for the BMC requests - https://<host>[:port]/, from the BMC address and port,
+// or the base URL when set, which may include a path prefix.
+func bmcBaseURL(address, port, baseURL string) (string, error) {
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return "", errors.Wrap(ErrInvalidBMCAddress, err.Error())
+		}
+
+		if u.Scheme != "https" && u.Scheme != "http" {
+			return "", errors.Wrap(ErrInvalidBMCAddress, "base URL scheme not one of https, http: "+baseURL)
+		}
+
+		if u.Hostname() == "" {
+			return "", errors.Wrap(ErrInvalidBMCAddress, "base URL without a host: "+baseURL)
+		}
+
+		if u.Port() != "" {
+			if err := validatePort(u.Port()); err != nil {
+				return "", err
+			}
+		}
+
+		return strings.TrimSuffix(baseURL, "/") + "/", nil
+	}
+
+	host := urlHost(address)
+	if host == "" {
+		return "", errors.Wrap(ErrInvalidBMCAddress, "empty BMC address")
+	}
+
+	if port != "" {
+		if err := validatePort(port); err != nil {
+			return "", err
+		}
+
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		host = net.JoinHostPort(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"), port)
+	}
+
+	return "https://" + host + "/", nil
+}
+
+// validatePort returns an error when the port is not a number within 1 - 65535
+func validatePort(port string) error {
+	p, err := strconv.Atoi(port)
+	if err != nil || p < 1 || p > 65535 {
+		return errors.Wrap(ErrInvalidBMCAddress, fmt.Sprintf("port %q not within 1 - 65535", port))
+	}
+
+	return nil
+}
+
+// urlHost returns the BMC address formatted as a URL host,
+// IPv6 literals are bracketed and the zone ID percent-encoded - fe80::1%eth0 -> [fe80::1%25eth0].
+//
+// Host names and IPv4 addresses, optionally with a port, and bracketed IPv6 literals are accepted.
+func urlHost(address string) string {
+	address = strings.TrimSpace(address)
+
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		// JoinHostPort brackets IPv6 literals
+		return net.JoinHostPort(escapeZone(host), port)
+	}
+
+	host := strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+	if strings.Contains(host, ":") {
+		return "[" + escapeZone(host) + "]"
+	}
+
+	return address
+}
+
+// escapeZone percent-encodes the IPv6 zone ID separator for use in a URL
+func escapeZone(host string) string {
+	if strings.Contains(host, "%25") {
+		return host
+	}
+
+	return strings.Replace(host, "%", "%25", 1)
+}
+
+// validHost returns true if the host is an IP address or a valid DNS host name -
+// labels of up to 63 letters, digits and hyphens, not starting or ending with a hyphen.
+func validHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || len(host) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,120 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_urlHost(t *testing.T) {
+	testcases := []struct {
+		address  string
+		expected string
+	}{
+		{"10.0.0.1", "10.0.0.1"},
+		{"10.0.0.1:8443", "10.0.0.1:8443"},
+		{"bmc.example.com", "bmc.example.com"},
+		{"bmc.example.com:443", "bmc.example.com:443"},
+		{"2001:db8::1", "[2001:db8::1]"},
+		{"[2001:db8::1]", "[2001:db8::1]"},
+		{"[2001:db8::1]:8443", "[2001:db8::1]:8443"},
+		{"fe80::1%eth0", "[fe80::1%25eth0]"},
+		{"[fe80::1%eth0]:443", "[fe80::1%25eth0]:443"},
+		{"[fe80::1%25eth0]", "[fe80::1%25eth0]"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.address, func(t *testing.T) {
+			assert.Equal(t, tc.expected, urlHost(tc.address))
+		})
+	}
+}
+
+func Test_bmcBaseURL(t *testing.T) {
+	testcases := []struct {
+		name     string
+		address  string
+		port     string
+		baseURL  string
+		expected string
+		err      bool
+	}{
+		{"address", "10.0.0.1", "", "", "https://10.0.0.1/", false},
+		{"address with port", "10.0.0.1:8443", "", "", "https://10.0.0.1:8443/", false},
+		{"port", "10.0.0.1", "8443", "", "https://10.0.0.1:8443/", false},
+		{"port overrides the address port", "10.0.0.1:443", "8443", "", "https://10.0.0.1:8443/", false},
+		{"IPv6 with port", "fe80::1%eth0", "8443", "", "https://[fe80::1%25eth0]:8443/", false},
+		{"bracketed IPv6 with port", "[2001:db8::1]:443", "8443", "", "https://[2001:db8::1]:8443/", false},
+		{"base URL", "10.0.0.1", "", "https://proxy.example.com:8443/bmc/r1n3", "https://proxy.example.com:8443/bmc/r1n3/", false},
+		{"port out of range", "10.0.0.1", "70000", "", "", true},
+		{"port zero", "10.0.0.1", "0", "", "", true},
+		{"port not a number", "10.0.0.1", "https", "", "", true},
+		{"base URL scheme", "10.0.0.1", "", "ftp://proxy.example.com/", "", true},
+		{"base URL port out of range", "10.0.0.1", "", "https://proxy.example.com:99999/", "", true},
+		{"base URL without host", "10.0.0.1", "", "https:///bmc", "", true},
+		{"empty address", "", "", "", "", true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			baseURL, err := bmcBaseURL(tc.address, tc.port, tc.baseURL)
+			if tc.err {
+				assert.ErrorIs(t, err, ErrInvalidBMCAddress)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, baseURL)
+		})
+	}
+}
+
+func Test_WithPort(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{ "power_status": 1, "led_status": 0 }`))
+	})
+	handler.HandleFunc("/bmc/r1n3/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{ "power_status": 0, "led_status": 0 }`))
+	})
+
+	// the test server listens on a random nonstandard port
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	client, err := NewValidated(serverURL.Hostname(), "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithPort(serverURL.Port()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := client.PowerStateGet(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "On", state)
+
+	// reverse proxy base URL with a path prefix
+	client, err = NewValidated("r1n3", "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithBaseURL(server.URL+"/bmc/r1n3/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = client.PowerStateGet(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "Off", state)
+
+	// invalid port
+	_, err = NewValidated(serverURL.Hostname(), "foo", "bar", logr.Discard(), WithPort("70000"))
+	assert.ErrorIs(t, err, ErrInvalidBMCAddress)
+
+	// requests fail with the configuration error when constructed without validation
+	client = NewWithOptions(serverURL.Hostname(), "foo", "bar", logr.Discard(), WithPort("70000"))
+
+	_, err = client.Sensors(context.TODO())
+	assert.ErrorIs(t, err, ErrInvalidBMCAddress)
+}
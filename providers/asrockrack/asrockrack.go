@@ -3,9 +3,13 @@ package asrockrack
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"crypto/x509"
 	"net/http"
+	"sync"
+	"time"
 
+	"github.com/bmc-toolbox/bmclib/v2/bmc"
 	"github.com/bmc-toolbox/bmclib/v2/constants"
 	"github.com/bmc-toolbox/bmclib/v2/internal/httpclient"
 	"github.com/bmc-toolbox/bmclib/v2/providers"
@@ -18,6 +22,19 @@ const (
 	ProviderName = "asrockrack"
 	// ProviderProtocol for the provider implementation
 	ProviderProtocol = "vendorapi"
+
+	defaultSoftPowerOffTimeout = 5 * time.Minute
+	defaultPowerStateTimeout   = 2 * time.Minute
+	defaultPowerPollInterval   = 5 * time.Second
+
+	defaultBMCResetPollInterval = 10 * time.Second
+
+	// the in-flight BMC requests of an inventory collection, the BMC web server handles few requests at once
+	defaultInventoryConcurrency = 4
+
+	// the inventory sections are collected concurrently over up to this many connections
+	defaultMaxIdleConns    = 8
+	defaultIdleConnTimeout = 30 * time.Second
 )
 
 var (
@@ -28,22 +45,88 @@ var (
 		providers.FeatureFirmwareInstallStatus,
 		providers.FeaturePostCodeRead,
 		providers.FeatureBmcReset,
+		providers.FeaturePowerState,
+		providers.FeaturePowerSet,
 		providers.FeatureUserCreate,
 		providers.FeatureUserUpdate,
+		providers.FeatureUserDelete,
+		providers.FeatureUserRead,
+		providers.FeatureVirtualMedia,
+		providers.FeatureScreenshot,
+		providers.FeatureBootDeviceSet,
 	}
 )
 
+// The bmclib interfaces implemented by the provider, a feature is included in Features for each interface implemented.
+var (
+	_ bmc.Provider                = (*ASRockRack)(nil)
+	_ bmc.Opener                  = (*ASRockRack)(nil)
+	_ bmc.Closer                  = (*ASRockRack)(nil)
+	_ bmc.InventoryGetter         = (*ASRockRack)(nil)
+	_ bmc.FirmwareInstaller       = (*ASRockRack)(nil)
+	_ bmc.FirmwareInstallVerifier = (*ASRockRack)(nil)
+	_ bmc.PostCodeGetter          = (*ASRockRack)(nil)
+	_ bmc.BMCResetter             = (*ASRockRack)(nil)
+	_ bmc.PowerStateGetter        = (*ASRockRack)(nil)
+	_ bmc.PowerSetter             = (*ASRockRack)(nil)
+	_ bmc.UserCreator             = (*ASRockRack)(nil)
+	_ bmc.UserUpdater             = (*ASRockRack)(nil)
+	_ bmc.UserDeleter             = (*ASRockRack)(nil)
+	_ bmc.UserReader              = (*ASRockRack)(nil)
+	_ bmc.VirtualMediaSetter      = (*ASRockRack)(nil)
+	_ bmc.ScreenshotGetter        = (*ASRockRack)(nil)
+	_ bmc.BootDeviceSetter        = (*ASRockRack)(nil)
+	_ bmc.BiosConfigurationGetter = (*ASRockRack)(nil)
+)
+
 // ASRockRack holds the status and properties of a connection to a asrockrack bmc
+//
+// An ASRockRack is safe for concurrent use, the BMC session and the HTTP client connections are shared
+// by all the requests made through it.
 type ASRockRack struct {
 	ip                   string
+	port                 string // BMC port set with WithPort
+	baseURLOverride      string // BMC base URL set with WithBaseURL
+	baseURL              string // https://<host>/ - the BMC request URLs are the base URL suffixed by the endpoint
+	configErr            error  // invalid BMC address, port or base URL, returned by NewValidated and each request
 	username             string
 	password             string
 	loginSession         *loginSession
+	sessionMu            sync.RWMutex // guards loginSession
+	sessionRefreshMu     sync.Mutex   // serializes session refreshes
 	httpClient           *http.Client
 	resetRequired        bool // Indicates if the BMC requires a reset
 	skipLogout           bool // A Close() / httpsLogout() request is ignored if the BMC was just flashed - since the sessions are terminated either way
 	log                  logr.Logger
 	httpClientSetupFuncs []func(*http.Client)
+	inventoryBestEffort  bool // Continue inventory collection when an inventory section fails
+	inventorySkip        map[InventorySection]bool
+	inventoryConcurrency int      // in-flight BMC requests limit of an inventory collection, unlimited when 0 or less
+	cpuMEVersionMetadata bool     // Include the Intel ME version in each CPU firmware metadata
+	sensorHealthIgnore   []string // Sensor names and name prefixes excluded from the health rollup
+	unknownSensorsInfo   bool     // Sensors in an unrecognized state are informational instead of CRITICAL
+	intrusionWarning     bool     // An asserted chassis intrusion sets the device health to WARNING
+	bmcUptimeMetadata    bool     // Include the BMC uptime and last boot time in the inventory metadata
+	dryRun               bool     // Requests changing the BMC or host state are logged and not sent
+	credentialCheck      bool     // NewWithContext logs in to validate the BMC connectivity and credentials
+	userAgent            string   // User-Agent header of the BMC requests
+	requestIDHeader      string   // header set to the request ID carried by the request context
+	metrics              MetricsRecorder
+	tracer               Tracer
+	softPowerOffTimeout  time.Duration
+	powerStateTimeout    time.Duration // PowerCycle waits for the power off and on to be confirmed
+	powerPollInterval    time.Duration
+	requestTimeout       time.Duration // each BMC request is cancelled after the timeout when set
+	retryPolicy          RetryPolicy
+	bmcResetWait         time.Duration // BmcReset waits for the BMC to be reachable when set
+	bmcResetPollInterval time.Duration
+	firmwareBankWarning  bool              // mismatched firmware bank versions set the device health to WARNING
+	firmwareDesired      map[string]string // desired BMC, BIOS firmware versions the firmware banks are compared to
+	allowDowngrade       bool              // firmware images older than the installed version are installed
+	firmwareTasks        map[string]string // firmware install task ID to install state
+	firmwareTasksMu      sync.Mutex
+	capabilities         []string // capabilities identified for the current session
+	capabilitiesMu       sync.Mutex
 }
 
 type Config struct {
@@ -62,6 +145,61 @@ func WithSecureTLS(rootCAs *x509.CertPool) ASRockOption {
 	}
 }
 
+// WithTLSConfig sets the TLS configuration of the HTTP client, for BMCs with certificates issued by an internal CA
+// or pinned certificates. The configuration is cloned.
+func WithTLSConfig(config *tls.Config) ASRockOption {
+	return func(r *ASRockRack) {
+		r.httpClientSetupFuncs = append(r.httpClientSetupFuncs, func(c *http.Client) {
+			transport(c).TLSClientConfig = config.Clone()
+		})
+	}
+}
+
+// WithInsecureSkipVerify disables the BMC TLS certificate verification, for BMCs with self-signed certificates.
+func WithInsecureSkipVerify() ASRockOption {
+	return func(r *ASRockRack) {
+		r.httpClientSetupFuncs = append(r.httpClientSetupFuncs, func(c *http.Client) {
+			tp := transport(c)
+			if tp.TLSClientConfig == nil {
+				tp.TLSClientConfig = &tls.Config{}
+			}
+
+			tp.TLSClientConfig.InsecureSkipVerify = true
+		})
+	}
+}
+
+// transport returns the HTTP client transport, a default transport is set on the client
+// when the client transport is not an *http.Transport.
+func transport(c *http.Client) *http.Transport {
+	tp, ok := c.Transport.(*http.Transport)
+	if !ok || tp == nil {
+		tp = httpclient.DefaultTransport()
+		c.Transport = tp
+	}
+
+	return tp
+}
+
+// WithConnectionPool configures the HTTP client to keep up to maxIdleConns idle connections to the BMC for reuse,
+// idle connections are closed after idleConnTimeout. A maxIdleConns value of 0 disables connection reuse.
+func WithConnectionPool(maxIdleConns int, idleConnTimeout time.Duration) ASRockOption {
+	return func(r *ASRockRack) {
+		r.httpClientSetupFuncs = append(r.httpClientSetupFuncs, connectionPool(maxIdleConns, idleConnTimeout))
+	}
+}
+
+// connectionPool returns an HTTP client setup func configuring the idle connections kept for reuse
+func connectionPool(maxIdleConns int, idleConnTimeout time.Duration) func(*http.Client) {
+	return func(c *http.Client) {
+		tp := transport(c)
+		tp.DisableKeepAlives = maxIdleConns == 0
+		tp.MaxIdleConns = maxIdleConns
+		tp.MaxIdleConnsPerHost = maxIdleConns
+		tp.IdleConnTimeout = idleConnTimeout
+	}
+}
+
 // WithHTTPClient sets an HTTP client on the ASRockRack
 func WithHTTPClient(c *http.Client) ASRockOption {
 	return func(ar *ASRockRack) {
@@ -69,12 +207,188 @@ func WithHTTPClient(c *http.Client) ASRockOption {
 	}
 }
 
+// WithCPUMEVersionMetadata includes the Intel ME version in each CPU firmware metadata as "Intel_ME_version",
+// in addition to the device metadata "intel_me.version" key.
+func WithCPUMEVersionMetadata() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.cpuMEVersionMetadata = true
+	}
+}
+
+// WithSoftPowerOffTimeout sets the duration a graceful shutdown is awaited for the host to power off, defaults to 5 minutes.
+func WithSoftPowerOffTimeout(d time.Duration) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.softPowerOffTimeout = d
+	}
+}
+
+// WithPowerStateTimeout sets the duration PowerCycle waits for the power off and power on to be confirmed, defaults to 2 minutes.
+func WithPowerStateTimeout(d time.Duration) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.powerStateTimeout = d
+	}
+}
+
+// WithBMCResetWait configures BmcReset to wait up to the given duration for the BMC to be reachable after the reset.
+func WithBMCResetWait(d time.Duration) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.bmcResetWait = d
+	}
+}
+
+// WithRequestTimeout sets the duration after which each BMC request is cancelled, independent of the operation context.
+//
+// A timed out request fails with ErrRequestTimeout, in best effort inventory mode the remaining sections are collected.
+// Firmware image uploads are not subject to the request timeout.
+func WithRequestTimeout(d time.Duration) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.requestTimeout = d
+	}
+}
+
+// WithRetryPolicy sets the retry policy for the BMC inventory queries - FRU, sensors, firmware and inventory info,
+// the DefaultRetryPolicy applies when not set.
+func WithRetryPolicy(p RetryPolicy) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.retryPolicy = p
+	}
+}
+
+// WithLogger sets the logger the provider emits debug logs on, for swallowed errors, retries and fallbacks.
+func WithLogger(log logr.Logger) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.log = log
+	}
+}
+
+// WithBestEffortInventory configures Inventory() to continue collection when an inventory section fails,
+// the populated device is returned along with an error listing the sections that failed.
+func WithBestEffortInventory() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.inventoryBestEffort = true
+	}
+}
+
+// WithInventorySkip configures Inventory() to skip collecting the given inventory sections,
+// the attributes of skipped sections are left empty and the BMC endpoints are not queried.
+func WithInventorySkip(sections ...InventorySection) ASRockOption {
+	return func(ar *ASRockRack) {
+		if ar.inventorySkip == nil {
+			ar.inventorySkip = map[InventorySection]bool{}
+		}
+
+		for _, section := range sections {
+			ar.inventorySkip[section] = true
+		}
+	}
+}
+
+// WithInventoryConcurrency limits the BMC requests in flight at once during an Inventory() collection,
+// the inventory sections wait for a request to complete once the limit is reached. By default up to 4 requests
+// are in flight, a limit of 0 or less removes the limit.
+func WithInventoryConcurrency(limit int) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.inventoryConcurrency = limit
+	}
+}
+
+// WithBMCUptimeMetadata includes the BMC uptime in seconds and the BMC last boot time in the inventory
+// bmc.uptime and bmc.last_boot metadata, the uptime is read with an additional request and changes between inventories.
+func WithBMCUptimeMetadata() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.bmcUptimeMetadata = true
+	}
+}
+
+// WithSensorHealthIgnore excludes the given sensors from the device and component health,
+// sensor names are matched case insensitively and a name ending with * matches the name prefix - "PSU2_*".
+func WithSensorHealthIgnore(names ...string) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.sensorHealthIgnore = append(ar.sensorHealthIgnore, names...)
+	}
+}
+
+// WithUnknownSensorsInformational treats sensors reporting none of the known threshold states as informational,
+// by default such sensors are considered CRITICAL.
+func WithUnknownSensorsInformational() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.unknownSensorsInfo = true
+	}
+}
+
+// WithChassisIntrusionWarning considers an asserted chassis intrusion sensor a WARNING in the health rollup,
+// by default the chassis intrusion is only reported in the chassis.intrusion inventory metadata.
+func WithChassisIntrusionWarning() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.intrusionWarning = true
+	}
+}
+
+// WithFirmwareBankWarning considers mismatched BMC or BIOS firmware bank versions a WARNING in the health rollup,
+// by default the mismatch is only reported in the bmc.firmware.bank_mismatch and bios.firmware.bank_mismatch inventory metadata.
+//
+// The firmware banks are considered mismatched when the primary and backup bank versions differ, or when the
+// desired versions are given, keyed by common.SlugBMC and common.SlugBIOS, when either bank version differs from the desired version.
+func WithFirmwareBankWarning(desired map[string]string) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.firmwareBankWarning = true
+		ar.firmwareDesired = desired
+	}
+}
+
+// WithAllowDowngrade allows the install of BMC firmware images older than the installed version,
+// by default the install is refused with ErrDowngradeBlocked.
+func WithAllowDowngrade() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.allowDowngrade = true
+	}
+}
+
+// WithDryRun enables the dry run mode, in which the methods changing the BMC or host state - power, boot device,
+// firmware install, BMC and BIOS configuration build and validate their requests, log the requests at
+// log level 1 instead of sending them and return the result as if the requests succeeded.
+// Read methods are not affected.
+func WithDryRun() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.dryRun = true
+	}
+}
+
+// WithCredentialCheck validates the BMC connectivity and credentials in NewWithContext by logging in,
+// the session established is kept open for reuse.
+func WithCredentialCheck() ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.credentialCheck = true
+	}
+}
+
+// WithPort sets the BMC HTTPS port, overriding any port included in the BMC address
+func WithPort(port string) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.port = port
+	}
+}
+
+// WithBaseURL sets the base URL of the BMC API, to reach a BMC behind a reverse proxy -
+// "https://proxy.example.com:8443/bmc/r1n3/", the BMC address is then only used to identify the BMC in logs.
+func WithBaseURL(baseURL string) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.baseURLOverride = baseURL
+	}
+}
+
 // New returns a new ASRockRack instance ready to be used
 func New(ip string, username string, password string, log logr.Logger) *ASRockRack {
 	return NewWithOptions(ip, username, password, log)
 }
 
 // NewWithOptions returns a new ASRockRack instance with options ready to be used
+//
+// A zero value logr.Logger discards all logs, same as logr.Discard().
+//
+// The BMC TLS certificate is verified against the system CAs, unless configured with the
+// WithInsecureSkipVerify, WithTLSConfig or WithSecureTLS options, and connections to the BMC are reused.
+// An HTTP client set with WithHTTPClient is used with its TLS and transport configuration as is.
 func NewWithOptions(ip string, username string, password string, log logr.Logger, opts ...ASRockOption) *ASRockRack {
 	r := &ASRockRack{
 		ip:           ip,
@@ -82,12 +396,32 @@ func NewWithOptions(ip string, username string, password string, log logr.Logger
 		password:     password,
 		log:          log,
 		loginSession: &loginSession{},
+
+		softPowerOffTimeout: defaultSoftPowerOffTimeout,
+		powerStateTimeout:   defaultPowerStateTimeout,
+		powerPollInterval:   defaultPowerPollInterval,
+		retryPolicy:         DefaultRetryPolicy,
+		metrics:             noopMetrics{},
+		userAgent:           defaultUserAgent(),
+		requestIDHeader:     defaultRequestIDHeader,
+
+		bmcResetPollInterval: defaultBMCResetPollInterval,
+		inventoryConcurrency: defaultInventoryConcurrency,
 	}
 	for _, opt := range opts {
 		opt(r)
 	}
+	r.baseURL, r.configErr = bmcBaseURL(ip, r.port, r.baseURLOverride)
 	if r.httpClient == nil {
-		r.httpClient = httpclient.Build(r.httpClientSetupFuncs...)
+		// the BMC certificate is verified and connections are reused unless configured otherwise
+		setupFuncs := append(
+			[]func(*http.Client){
+				httpclient.SecureTLSOption(nil),
+				connectionPool(defaultMaxIdleConns, defaultIdleConnTimeout),
+			},
+			r.httpClientSetupFuncs...,
+		)
+		r.httpClient = httpclient.Build(setupFuncs...)
 	} else {
 		for _, setupFunc := range r.httpClientSetupFuncs {
 			setupFunc(r.httpClient)
@@ -96,6 +430,40 @@ func NewWithOptions(ip string, username string, password string, log logr.Logger
 	return r
 }
 
+// NewValidated returns a new ASRockRack instance with options ready to be used, same as NewWithOptions,
+// an ErrInvalidBMCAddress error is returned when the BMC address, port or base URL is not valid.
+func NewValidated(ip string, username string, password string, log logr.Logger, opts ...ASRockOption) (*ASRockRack, error) {
+	r := NewWithOptions(ip, username, password, log, opts...)
+	if r.configErr != nil {
+		return nil, r.configErr
+	}
+
+	return r, nil
+}
+
+// NewWithContext returns a new ASRockRack instance with options ready to be used, same as NewValidated,
+// with the WithCredentialCheck option a session is opened and the login error returned when the BMC is not reachable
+// or the credentials are rejected - errors.ErrLoginFailed, so invalid credentials are identified before the first call.
+//
+// The session opened is reused by the other methods until Close is called.
+func NewWithContext(ctx context.Context, host, username, password string, log logr.Logger, opts ...ASRockOption) (*ASRockRack, error) {
+	r, err := NewValidated(host, username, password, log, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.credentialCheck {
+		return r, nil
+	}
+
+	if err := r.Open(ctx); err != nil {
+		r.httpClient.CloseIdleConnections()
+		return nil, err
+	}
+
+	return r, nil
+}
+
 func (a *ASRockRack) Name() string {
 	return ProviderName
 }
@@ -116,12 +484,34 @@ func (a *ASRockRack) Compatible(ctx context.Context) bool {
 }
 
 // Open a connection to a BMC, implements the Opener interface
+//
+// The session is reused by all other methods until Close is called, Open returns without logging in again
+// when the session is already open. An expired session is re-established on the next request.
 func (a *ASRockRack) Open(ctx context.Context) (err error) {
+	if a.sessionOpen() {
+		return nil
+	}
+
+	a.resetCapabilities()
+
 	return a.httpsLogin(ctx)
 }
 
 // Close a connection to a BMC, implements the Closer interface
+//
+// The idle connections to the BMC are closed once the session ends,
+// Close returns without logging out when no session is open.
 func (a *ASRockRack) Close(ctx context.Context) (err error) {
+	a.resetCapabilities()
+	defer a.httpClient.CloseIdleConnections()
+
+	if !a.sessionOpen() {
+		return nil
+	}
+
+	// the session is discarded even if the logout fails, a later Open establishes a new session
+	defer a.clearSession()
+
 	if a.skipLogout {
 		return nil
 	}
@@ -2,9 +2,21 @@ package asrockrack
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
+	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
 	"gopkg.in/go-playground/assert.v1"
 )
 
@@ -54,8 +66,240 @@ func Test_FirwmwareUpdateBMC(t *testing.T) {
 	}
 
 	defer fh.Close()
-	err = aClient.firmwareInstallBMC(context.TODO(), fh, 0)
+	err = aClient.firmwareInstallBMC(context.TODO(), "", fh, &firmwareUpload{}, false)
 	if err != nil {
 		t.Errorf(err.Error())
 	}
 }
+
+func Test_NewWithOptionsLogger(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/sensors", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+	handler.HandleFunc("/api/chassis-status", chassisStatusInfo)
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	logged := []string{}
+	logger := funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{Verbosity: 2})
+
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithLogger(logger))
+
+	device := common.NewDevice()
+	device.Status = &common.Status{}
+	device.Metadata = map[string]string{}
+
+	// the POST code collection error is logged and not returned
+	err := client.systemHealth(context.TODO(), &device)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(logged))
+	assert.Equal(t, true, strings.Contains(logged[0], "unable to collect POST code"))
+}
+
+func Test_NewWithOptionsZeroLogger(t *testing.T) {
+	// a zero value logger discards logs
+	client := New("127.0.0.1", "foo", "bar", logr.Logger{})
+	client.log.V(2).Error(errors.New("foo"), "bar")
+	client.log.Info("baz")
+}
+
+func Test_NewWithOptionsTLS(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", index)
+
+	// the httptest server certificate is issued by a CA unknown to the system
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	testCases := []struct {
+		name       string
+		opts       []ASRockOption
+		compatible bool
+	}{
+		{"certificate verified by default", nil, false},
+		{"custom CA", []ASRockOption{WithTLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})}, true},
+		{"custom CA pool", []ASRockOption{WithSecureTLS(pool)}, true},
+		{"insecure skip verify", []ASRockOption{WithInsecureSkipVerify()}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), tc.opts...)
+			assert.Equal(t, tc.compatible, client.Compatible(context.TODO()))
+		})
+	}
+}
+
+func Test_OpenSessionReuse(t *testing.T) {
+	mock := mockASRockBMC()
+	defer mock.Close()
+
+	var mu sync.Mutex
+	requested := map[string]int{}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	if err := client.Open(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the session is reused across operations and repeated Open calls
+	if _, err := client.PowerStateGet(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.UserRead(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Sensors(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Open(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, requested["POST /api/session"])
+
+	if err := client.Close(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, requested["DELETE /api/session"])
+	assert.Equal(t, false, client.sessionOpen())
+
+	// closing a closed session does not log out again
+	if err := client.Close(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, requested["DELETE /api/session"])
+
+	// a new session is established once closed
+	if err := client.Open(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 2, requested["POST /api/session"])
+}
+
+func Test_NewWithContext(t *testing.T) {
+	var mu sync.Mutex
+	var logins, requests int
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/session", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		logins++
+
+		_ = r.ParseForm()
+		if r.PostForm.Get("username") != "foo" || r.PostForm.Get("password") != "bar" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write(loginResponse)
+	})
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests++
+
+		_, _ = w.Write(chassisStatusResponse)
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	testCases := []struct {
+		name     string
+		password string
+		opts     []ASRockOption
+		err      error
+		logins   int
+	}{
+		{"valid credentials", "bar", []ASRockOption{WithCredentialCheck()}, nil, 1},
+		{"invalid credentials", "baz", []ASRockOption{WithCredentialCheck()}, bmclibErrs.ErrLoginFailed, 1},
+		{"credentials not checked", "baz", nil, nil, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			logins = 0
+			mu.Unlock()
+
+			opts := append([]ASRockOption{WithInsecureSkipVerify()}, tc.opts...)
+
+			client, err := NewWithContext(context.TODO(), serverURL.Host, "foo", tc.password, logr.Discard(), opts...)
+
+			mu.Lock()
+			assert.Equal(t, tc.logins, logins)
+			mu.Unlock()
+
+			if tc.err != nil {
+				assert.Equal(t, true, errors.Is(err, tc.err))
+				assert.Equal(t, true, client == nil)
+				return
+			}
+
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tc.logins == 1, client.sessionOpen())
+		})
+	}
+
+	// the session opened is reused
+	client, err := NewWithContext(context.TODO(), serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithCredentialCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	logins = 0
+	mu.Unlock()
+
+	if _, err := client.PowerStateGet(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Open(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, 0, logins)
+	assert.Equal(t, 1, requests)
+
+	// an invalid address is returned before connecting
+	_, err = NewWithContext(context.TODO(), "", "foo", "bar", logr.Discard(), WithCredentialCheck())
+	assert.Equal(t, true, errors.Is(err, ErrInvalidBMCAddress))
+}
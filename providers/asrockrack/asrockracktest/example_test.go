@@ -0,0 +1,102 @@
+package asrockracktest_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack"
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/go-logr/logr"
+)
+
+func ExampleNewServer() {
+	server := asrockracktest.NewServer()
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	client := asrockrack.NewWithOptions(u.Host, "foo", "bar", logr.Discard(), asrockrack.WithInsecureSkipVerify())
+
+	ctx := context.Background()
+	if err := client.Open(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer client.Close(ctx)
+
+	device, err := client.Inventory(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(device.Vendor, device.Model)
+
+	if _, err := client.PowerSet(ctx, "off"); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(server.PowerState())
+	// Output:
+	// ASRockRack E3C246D4I-NL
+	// Off
+}
+
+func ExampleWithResponse() {
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse("api/asrr/fw-info", 500, nil),
+	)
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+	client := asrockrack.NewWithOptions(
+		u.Host,
+		"foo",
+		"bar",
+		logr.Discard(),
+		asrockrack.WithInsecureSkipVerify(),
+		asrockrack.WithRetryPolicy(asrockrack.RetryPolicy{MaxAttempts: 1}),
+	)
+
+	_, err := client.Inventory(context.Background())
+	fmt.Println(err != nil)
+	// Output:
+	// true
+}
+
+func ExampleNewReplayer() {
+	// the recording was captured with a Recorder, see recording.md
+	recording, err := asrockracktest.LoadRecording("testdata/recordings/E3C246D4I-NL-inventory.json")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	replayer := asrockracktest.NewReplayer(recording)
+	client := asrockrack.NewWithOptions(
+		"bmc.example.com",
+		"foo",
+		"bar",
+		logr.Discard(),
+		asrockrack.WithHTTPClient(replayer.Client()),
+		asrockrack.WithInventorySkip(asrockrack.InventorySectionStorage),
+	)
+
+	ctx := context.Background()
+	if err := client.Open(ctx); err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer client.Close(ctx)
+
+	device, err := client.Inventory(ctx)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(device.Vendor, device.Model, device.BMC.Firmware.Installed)
+	// Output:
+	// ASRockRack E3C246D4I-NL 0.01.00
+}
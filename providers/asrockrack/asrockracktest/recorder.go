@@ -0,0 +1,325 @@
+package asrockracktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces the string values of the redacted response fields in a recording, numbers are replaced with zero.
+const Redacted = "REDACTED"
+
+// defaultRedactedFields are the response fields redacted from every recording, the session token and addresses
+// and the credentials and keys the BMC lists in its settings.
+var defaultRedactedFields = []string{
+	"csrftoken",
+	"racsession_id",
+	"remote_addr",
+	"server_addr",
+	"server_name",
+	"password",
+	"ssh_key",
+	"private_key",
+	"community",
+	"auth_password",
+	"priv_password",
+}
+
+// Interaction is a BMC request and its recorded response.
+//
+// JSON response bodies are recorded in Body, any other response body in Text.
+// Request bodies are not recorded as they may carry the BMC credentials.
+type Interaction struct {
+	Method      string          `json:"method"`
+	Endpoint    string          `json:"endpoint"`
+	StatusCode  int             `json:"status_code"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Text        string          `json:"text,omitempty"`
+}
+
+// Recording is the BMC traffic captured by a Recorder, in the order of the requests.
+type Recording struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper capturing the BMC responses, to be saved as a Recording and replayed by a Replayer.
+//
+// The values of the redacted fields are replaced in the recorded JSON responses, the session cookies are not recorded.
+type Recorder struct {
+	transport http.RoundTripper
+	redacted  map[string]bool
+
+	mu        sync.Mutex
+	recording Recording
+}
+
+// RecorderOption configures the Recorder.
+type RecorderOption func(*Recorder)
+
+// WithRedactedFields redacts the given JSON response fields in addition to the default fields -
+// the session token and addresses, passwords, SSH keys and SNMP communities. Field names are matched case insensitively.
+func WithRedactedFields(names ...string) RecorderOption {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.redacted[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// NewRecorder returns a Recorder sending the requests with the given transport,
+// http.DefaultTransport is used when the transport is nil.
+func NewRecorder(transport http.RoundTripper, opts ...RecorderOption) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	r := &Recorder{transport: transport, redacted: map[string]bool{}}
+	for _, name := range defaultRedactedFields {
+		r.redacted[name] = true
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Client returns an HTTP client recording through the Recorder, for use with the asrockrack.WithHTTPClient option.
+func (r *Recorder) Client() *http.Client {
+	return recordingClient(r)
+}
+
+// RoundTrip sends the request, records and returns the response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the response is requested uncompressed to record the body as is
+	req = req.Clone(req.Context())
+	req.Header.Del("Accept-Encoding")
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := &Interaction{
+		Method:      req.Method,
+		Endpoint:    endpoint(req),
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+
+	if json.Valid(body) {
+		interaction.Body = r.redact(body)
+	} else {
+		interaction.Text = string(body)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recording.Interactions = append(r.recording.Interactions, interaction)
+
+	return resp, nil
+}
+
+// Recording returns the interactions recorded so far.
+func (r *Recorder) Recording() *Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Recording{Interactions: append([]*Interaction{}, r.recording.Interactions...)}
+}
+
+// Save writes the recording to the file in indented JSON.
+func (r *Recorder) Save(path string) error {
+	b, err := json.MarshalIndent(r.Recording(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// redact returns the JSON body with the values of the redacted fields replaced
+func (r *Recorder) redact(body []byte) []byte {
+	var value interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+
+	b, err := json.Marshal(r.redactValue(value))
+	if err != nil {
+		return body
+	}
+
+	return b
+}
+
+func (r *Recorder) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if r.redacted[strings.ToLower(key)] {
+				v[key] = redactedValue(field)
+				continue
+			}
+
+			v[key] = r.redactValue(field)
+		}
+	case []interface{}:
+		for idx, item := range v {
+			v[idx] = r.redactValue(item)
+		}
+	}
+
+	return value
+}
+
+// redactedValue returns the redacted value of the same JSON type, for the recording to decode as the original -
+// strings are replaced with Redacted, numbers with zero.
+func redactedValue(value interface{}) interface{} {
+	switch value.(type) {
+	case json.Number:
+		return json.Number("0")
+	case bool, nil:
+		return value
+	}
+
+	return Redacted
+}
+
+// Replayer is an http.RoundTripper serving the responses of a Recording, without connecting to a BMC.
+//
+// Requests are matched by the method and endpoint, repeated requests are served the recorded responses in order
+// and the last recorded response once all were served. Requests not recorded are responded with a 404.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]*Interaction
+	served       map[string]int
+}
+
+// NewReplayer returns a Replayer serving the recorded interactions.
+func NewReplayer(recording *Recording) *Replayer {
+	r := &Replayer{interactions: map[string][]*Interaction{}, served: map[string]int{}}
+
+	for _, interaction := range recording.Interactions {
+		key := interaction.Method + " " + normalize(interaction.Endpoint)
+		r.interactions[key] = append(r.interactions[key], interaction)
+	}
+
+	return r
+}
+
+// LoadRecording reads a recording saved by the Recorder.
+func LoadRecording(path string) (*Recording, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recording := &Recording{}
+	if err := json.Unmarshal(b, recording); err != nil {
+		return nil, fmt.Errorf("asrockracktest: decoding recording %s: %w", path, err)
+	}
+
+	return recording, nil
+}
+
+// Client returns an HTTP client replaying through the Replayer, for use with the asrockrack.WithHTTPClient option.
+func (r *Replayer) Client() *http.Client {
+	return recordingClient(r)
+}
+
+// RoundTrip returns the recorded response to the request.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	key := req.Method + " " + endpoint(req)
+
+	r.mu.Lock()
+
+	var interaction *Interaction
+	if recorded := r.interactions[key]; len(recorded) > 0 {
+		idx := r.served[key]
+		if idx >= len(recorded) {
+			idx = len(recorded) - 1
+		}
+
+		interaction = recorded[idx]
+		r.served[key]++
+	}
+
+	r.mu.Unlock()
+
+	resp := &http.Response{
+		Status:     http.StatusText(http.StatusNotFound),
+		StatusCode: http.StatusNotFound,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+
+	if interaction == nil {
+		return resp, nil
+	}
+
+	body := []byte(interaction.Text)
+	if len(interaction.Body) > 0 {
+		body = interaction.Body
+	}
+
+	resp.Status = http.StatusText(interaction.StatusCode)
+	resp.StatusCode = interaction.StatusCode
+	resp.ContentLength = int64(len(body))
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if interaction.ContentType != "" {
+		resp.Header.Set("Content-Type", interaction.ContentType)
+	}
+
+	return resp, nil
+}
+
+// recordingClient returns an HTTP client with a cookie jar for the BMC session cookie, sending requests with the transport
+func recordingClient(transport http.RoundTripper) *http.Client {
+	// cookiejar.New always returns a nil error
+	jar, _ := cookiejar.New(nil)
+
+	return &http.Client{Transport: transport, Jar: jar}
+}
+
+// endpoint returns the request path and query without the leading slash - api/fru, api/asrr/sel?page=2
+func endpoint(req *http.Request) string {
+	e := normalize(req.URL.Path)
+	if req.URL.RawQuery != "" {
+		e += "?" + req.URL.RawQuery
+	}
+
+	return e
+}
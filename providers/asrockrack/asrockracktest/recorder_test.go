@@ -0,0 +1,84 @@
+package asrockracktest_test
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack"
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	server := asrockracktest.NewServer()
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL)
+
+	recorder := asrockracktest.NewRecorder(
+		&http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		asrockracktest.WithRedactedFields("mac_address"),
+	)
+
+	ctx := context.Background()
+	client := asrockrack.NewWithOptions(u.Host, "foo", "bar", logr.Discard(), asrockrack.WithHTTPClient(recorder.Client()))
+
+	if err := client.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := client.Inventory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "recording.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	recording, err := asrockracktest.LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the session token and the additional redacted fields are not recorded, nor the request bodies with the credentials
+	for _, interaction := range recording.Interactions {
+		assert.NotContains(t, string(interaction.Body), "l5L29IP7")
+		assert.NotContains(t, string(interaction.Body), "D0:50:99")
+
+		if interaction.Endpoint == "api/session" {
+			assert.True(t, strings.Contains(string(interaction.Body), asrockracktest.Redacted))
+		}
+	}
+
+	// the replayed inventory matches the recorded inventory
+	replayer := asrockracktest.NewReplayer(recording)
+	client = asrockrack.NewWithOptions("bmc.example.com", "foo", "bar", logr.Discard(), asrockrack.WithHTTPClient(replayer.Client()))
+
+	if err := client.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, err := client.Inventory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, recorded.Model, replayed.Model)
+	assert.Equal(t, recorded.Serial, replayed.Serial)
+	assert.Equal(t, len(recorded.Drives), len(replayed.Drives))
+	assert.Equal(t, recorded.BMC.Firmware.Installed, replayed.BMC.Firmware.Installed)
+	assert.Equal(t, recorded.Status.Health, replayed.Status.Health)
+
+	// endpoints not recorded are responded with a 404
+	resp, err := replayer.RoundTrip(&http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/api/unknown"}})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,193 @@
+// Package asrockracktest provides a fake ASRockRack BMC for use in tests.
+//
+// The fake serves canned responses captured from an E3C246D4I-NL BMC for the
+// session, FRU, sensor, firmware and inventory endpoints queried by the
+// asrockrack provider, any of which can be overridden per endpoint.
+//
+// The Recorder captures the responses of a BMC to be replayed by the Replayer,
+// to add fixtures for other boards and firmware revisions - see recording.md.
+package asrockracktest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+//go:embed testdata/*.json
+var testdata embed.FS
+
+// endpoints maps the BMC API endpoints to the canned response served by default.
+var endpoints = map[string]string{
+	"api/session":               "session.json",
+	"api/fru":                   "fru.json",
+	"api/sensors":               "sensors.json",
+	"api/asrr/fw-info":          "fw-info.json",
+	"api/asrr/inventory_info":   "inventory_info.json",
+	"api/asrr/getbioscode":      "getbioscode.json",
+	"api/settings/network":      "network.json",
+	"api/settings/network-link": "network-link.json",
+}
+
+// Response is a canned response served for an endpoint.
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Server is a fake ASRockRack BMC backed by an httptest TLS server.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]Response
+	powerOn   bool
+}
+
+// Option configures the fake BMC.
+type Option func(*Server)
+
+// WithResponse overrides the response served for the given endpoint,
+// the endpoint is given without the leading slash, e.g "api/fru".
+func WithResponse(endpoint string, statusCode int, body []byte) Option {
+	return func(s *Server) {
+		s.responses[normalize(endpoint)] = Response{StatusCode: statusCode, Body: body}
+	}
+}
+
+// WithPowerState sets the initial host power state, the host is powered on by default.
+func WithPowerState(on bool) Option {
+	return func(s *Server) {
+		s.powerOn = on
+	}
+}
+
+// NewServer starts and returns a fake ASRockRack BMC,
+// the caller should call Close when finished to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		responses: map[string]Response{},
+		powerOn:   true,
+	}
+
+	for endpoint, file := range endpoints {
+		body, err := testdata.ReadFile("testdata/" + file)
+		if err != nil {
+			panic(fmt.Sprintf("asrockracktest: reading %s: %s", file, err))
+		}
+
+		s.responses[endpoint] = Response{StatusCode: http.StatusOK, Body: body}
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", s.serve)
+
+	s.Server = httptest.NewTLSServer(handler)
+
+	return s
+}
+
+// SetResponse overrides the response served for the given endpoint on a running server.
+func (s *Server) SetResponse(endpoint string, statusCode int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[normalize(endpoint)] = Response{StatusCode: statusCode, Body: body}
+}
+
+// PowerState returns the current host power state - On or Off.
+func (s *Server) PowerState() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.powerOn {
+		return "On"
+	}
+
+	return "Off"
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	endpoint := normalize(r.URL.Path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch endpoint {
+	case "":
+		_, _ = w.Write([]byte("ASRockRack"))
+		return
+	case "api/chassis-status":
+		if _, exists := s.responses[endpoint]; !exists {
+			s.chassisStatus(w)
+			return
+		}
+	case "api/actions/power":
+		if _, exists := s.responses[endpoint]; !exists {
+			s.powerAction(w, r)
+			return
+		}
+	case "api/session":
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: "QSESSIONID", Value: "94ee2db3949bd7a2d4b0ad23af"})
+		}
+	}
+
+	resp, exists := s.responses[endpoint]
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body)
+}
+
+func (s *Server) chassisStatus(w http.ResponseWriter) {
+	status := 0
+	if s.powerOn {
+		status = 1
+	}
+
+	_, _ = fmt.Fprintf(w, `{ "power_status": %d, "led_status": 0 }`, status)
+}
+
+func (s *Server) powerAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	p := struct {
+		Command int `json:"power_command"`
+	}{}
+
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch p.Command {
+	case 0, 5:
+		s.powerOn = false
+	case 1, 2, 3:
+		s.powerOn = true
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, `{ "power_command": %d }`, p.Command)
+}
+
+func normalize(endpoint string) string {
+	return strings.Trim(endpoint, "/")
+}
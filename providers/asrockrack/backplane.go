@@ -0,0 +1,189 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/pkg/errors"
+)
+
+// backplane is a drive backplane as listed by the BMC, along with its drive bays
+type backplane struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	Manufacturer    string          `json:"manufacturer"`
+	Model           string          `json:"model"`
+	SerialNumber    string          `json:"serial_number"`
+	FirmwareVersion string          `json:"firmware_version"`
+	Bays            []*backplaneBay `json:"bays"`
+}
+
+// backplaneBay is a drive bay on a backplane, the drive in the bay is identified by its serial number
+type backplaneBay struct {
+	Bay         int    `json:"bay"`
+	Port        string `json:"port"`
+	Present     bool   `json:"present"`
+	DriveSerial string `json:"drive_serial"`
+}
+
+// enclosureDescriptionBackplane is the enclosure description of drive backplanes
+const enclosureDescriptionBackplane = "Backplane"
+
+// backplanes returns the drive backplanes,
+// no backplanes are returned when the board has no backplane or the firmware does not list them.
+func (a *ASRockRack) backplanes(ctx context.Context) (_ []*backplane, err error) {
+	defer func() { err = a.endpointError("backplanes", "api/asrr/storage/backplanes", err) }()
+
+	resp, statusCode, err := a.getWithRetry(ctx, "api/asrr/storage/backplanes")
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		err = statusCodeError(statusCode)
+		if errors.Is(err, ErrUnsupported) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	backplanes := []*backplane{}
+	if len(strings.TrimSpace(string(resp))) == 0 {
+		return backplanes, nil
+	}
+
+	if err := unmarshalTolerant(resp, &backplanes); err != nil {
+		return nil, err
+	}
+
+	return backplanes, nil
+}
+
+// backplaneAttributes collects the drive backplanes as device enclosures and links the device drives to their bay,
+// the backplanes are returned to link drives collected separately.
+func (a *ASRockRack) backplaneAttributes(ctx context.Context, device *common.Device) ([]*backplane, error) {
+	backplanes, err := a.backplanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range backplanes {
+		device.Enclosures = append(device.Enclosures, enclosureFromBackplane(b))
+	}
+
+	linkBackplaneDrives(device, backplanes)
+
+	return backplanes, nil
+}
+
+// enclosureFromBackplane returns the enclosure attributes of the backplane
+//
+// bays = <count>, bays.populated = <count>
+func enclosureFromBackplane(b *backplane) *common.Enclosure {
+	enclosure := &common.Enclosure{
+		Common: common.Common{
+			Description: enclosureDescriptionBackplane,
+			Vendor:      normalizeNA(b.Manufacturer),
+			Model:       normalizeNA(b.Model),
+			Serial:      normalizeNA(b.SerialNumber),
+			Metadata:    map[string]string{},
+		},
+		ID: backplaneID(b),
+	}
+
+	if !versionPlaceholder(b.FirmwareVersion) {
+		enclosure.Firmware = &common.Firmware{Installed: strings.TrimSpace(b.FirmwareVersion)}
+	}
+
+	var populated int
+	for _, bay := range b.Bays {
+		if bay.Present {
+			populated++
+		}
+	}
+
+	enclosure.Metadata["bays"] = strconv.Itoa(len(b.Bays))
+	enclosure.Metadata["bays.populated"] = strconv.Itoa(populated)
+
+	return enclosure
+}
+
+// backplaneID returns the backplane name - BPB1, or the backplane ID when the name is not reported
+func backplaneID(b *backplane) string {
+	if name := normalizeNA(b.Name); name != "" {
+		return name
+	}
+
+	return strconv.Itoa(b.ID)
+}
+
+// linkBackplaneDrives sets the backplane and bay the device drives occupy,
+// the drives are matched with the bays by serial number.
+//
+// storage.enclosure = <backplane>, storage.bay = <bay number>, storage.bay_port = <port>
+func linkBackplaneDrives(device *common.Device, backplanes []*backplane) {
+	for _, b := range backplanes {
+		for _, bay := range b.Bays {
+			if !bay.Present || normalizeNA(bay.DriveSerial) == "" {
+				continue
+			}
+
+			for _, drive := range device.Drives {
+				if drive.Serial == "" || !strings.EqualFold(drive.Serial, strings.TrimSpace(bay.DriveSerial)) {
+					continue
+				}
+
+				if drive.Metadata == nil {
+					drive.Metadata = map[string]string{}
+				}
+
+				drive.Metadata["storage.enclosure"] = backplaneID(b)
+				drive.Metadata["storage.bay"] = strconv.Itoa(bay.Bay)
+
+				if port := normalizeNA(bay.Port); port != "" {
+					drive.Metadata["storage.bay_port"] = port
+				}
+			}
+		}
+	}
+}
+
+// backplaneTemperatures sets the backplane temperature on the backplane enclosures, from the temperature sensors
+// prefixed with the backplane name - BPB1_TEMP. With a single backplane the BPB and Backplane prefixed sensors are considered.
+//
+// temperature = <reading>, temperature.unit = <unit>, temperature.sensor = <sensor name>
+func backplaneTemperatures(device *common.Device, sensors []*Sensor) {
+	backplanes := []*common.Enclosure{}
+	for _, enclosure := range device.Enclosures {
+		if enclosure.Description == enclosureDescriptionBackplane {
+			backplanes = append(backplanes, enclosure)
+		}
+	}
+
+	for _, enclosure := range backplanes {
+		ids := []string{enclosure.ID}
+		if len(backplanes) == 1 {
+			ids = append(ids, "BPB", "Backplane")
+		}
+
+		for _, sensor := range sensors {
+			if sensor.Type != "temperature" || sensor.Accessible != 0 || !sensorMatches(sensor, ids) {
+				continue
+			}
+
+			if enclosure.Metadata == nil {
+				enclosure.Metadata = map[string]string{}
+			}
+
+			enclosure.Metadata["temperature"] = strconv.FormatFloat(sensor.Reading, 'f', -1, 64)
+			enclosure.Metadata["temperature.unit"] = sensor.Unit
+			enclosure.Metadata["temperature.sensor"] = sensor.Name
+
+			break
+		}
+	}
+}
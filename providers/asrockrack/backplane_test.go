@@ -0,0 +1,104 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InventoryBackplanes(t *testing.T) {
+	backplanes, err := os.ReadFile("./fixtures/ROMED8-2T/backplanes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sensors := []byte(`[
+		{ "id": 1, "name": "BPB1_TEMP", "type": "temperature", "type_number": 1, "reading": 31.000000, "sensor_state": 1, "unit": "°C" },
+		{ "id": 2, "name": "BPB2_TEMP", "type": "temperature", "type_number": 1, "reading": 0.000000, "sensor_state": 0, "accessible": 213, "unit": "°C" }
+	]`)
+
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse("api/asrr/storage/backplanes", http.StatusOK, backplanes),
+		asrockracktest.WithResponse("api/sensors", http.StatusOK, sensors),
+	)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the FRU chassis is listed along with the backplanes
+	enclosures := map[string]*common.Enclosure{}
+	for _, enclosure := range device.Enclosures {
+		if enclosure.Description == enclosureDescriptionBackplane {
+			enclosures[enclosure.ID] = enclosure
+		}
+	}
+
+	assert.Equal(t, 2, len(enclosures))
+
+	assert.Equal(t, &common.Enclosure{
+		Common: common.Common{
+			Description: "Backplane",
+			Vendor:      "ASRockRack",
+			Model:       "BPB-8SAS",
+			Serial:      "BP21A0001234",
+			Metadata: map[string]string{
+				"bays":               "8",
+				"bays.populated":     "2",
+				"temperature":        "31",
+				"temperature.unit":   "°C",
+				"temperature.sensor": "BPB1_TEMP",
+			},
+		},
+		ID:       "BPB1",
+		Firmware: &common.Firmware{Installed: "0.0.002.0"},
+	}, enclosures["BPB1"])
+
+	// the backplane temperature sensor is not readable
+	assert.Equal(t, map[string]string{"bays": "4", "bays.populated": "1"}, enclosures["BPB2"].Metadata)
+	assert.Equal(t, "", enclosures["BPB2"].Serial)
+	assert.Nil(t, enclosures["BPB2"].Firmware)
+
+	// the drives are linked to their backplane bay
+	bays := map[string][3]string{}
+	for _, drive := range device.Drives {
+		bays[drive.Serial] = [3]string{drive.Metadata["storage.enclosure"], drive.Metadata["storage.bay"], drive.Metadata["storage.bay_port"]}
+	}
+
+	assert.Equal(t, map[string][3]string{
+		"PHYF001303ED480BGN": {"BPB1", "6", "SATA_4"},
+		"BTYF01940L38480BGN": {"BPB1", "7", "SATA_5"},
+	}, bays)
+}
+
+func Test_backplaneTemperaturesSingleBackplane(t *testing.T) {
+	device := &common.Device{
+		Enclosures: []*common.Enclosure{
+			{Common: common.Common{Description: "Main Server Chassis"}},
+			{Common: common.Common{Description: enclosureDescriptionBackplane}, ID: "1"},
+		},
+	}
+
+	sensors := []*Sensor{
+		{Name: "CPU_TEMP", Type: "temperature", Reading: 45, Unit: "°C"},
+		{Name: "BPB_TEMP", Type: "temperature", Reading: 29.5, Unit: "°C"},
+	}
+
+	backplaneTemperatures(device, sensors)
+
+	assert.Nil(t, device.Enclosures[0].Metadata)
+	assert.Equal(t, "29.5", device.Enclosures[1].Metadata["temperature"])
+	assert.Equal(t, "BPB_TEMP", device.Enclosures[1].Metadata["temperature.sensor"])
+}
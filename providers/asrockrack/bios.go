@@ -0,0 +1,350 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	biosAttributesEndpoint = "api/asrr/bios/attributes"
+	biosDefaultsEndpoint   = "api/asrr/bios/restore-defaults"
+)
+
+// biosAttributes is the BIOS attribute registry payload,
+// firmware revisions listing the BIOS settings pending include whether a reboot is required to apply them.
+type biosAttributes struct {
+	Attributes     []*biosAttribute `json:"attributes"`
+	RebootRequired *bool            `json:"reboot_required"`
+}
+
+// biosAttribute is a BIOS setting as listed in the BIOS attribute registry
+type biosAttribute struct {
+	Name          string   `json:"name"`
+	CurrentValue  string   `json:"current_value"`
+	PendingValue  string   `json:"pending_value"`
+	Type          string   `json:"type"`
+	AllowedValues []string `json:"allowed_values"`
+	Min           *int64   `json:"min,omitempty"`
+	Max           *int64   `json:"max,omitempty"`
+	ReadOnly      bool     `json:"read_only"`
+}
+
+// biosAttributeValue is a BIOS setting value staged for the next boot
+type biosAttributeValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// biosAttributesUpdate is the BIOS settings update request payload
+type biosAttributesUpdate struct {
+	Attributes []*biosAttributeValue `json:"attributes"`
+}
+
+// biosAttributesUpdateResponse is the BIOS settings update response payload,
+// rejected attributes are listed along with the reason.
+type biosAttributesUpdateResponse struct {
+	RebootRequired *bool `json:"reboot_required"`
+	Errors         []struct {
+		Name   string `json:"name"`
+		Reason string `json:"reason"`
+	} `json:"errors"`
+}
+
+// BIOSConfiguration is the BIOS settings, the values in effect and the values staged to be applied on the next boot
+type BIOSConfiguration struct {
+	// Attributes are the BIOS settings keyed by the attribute name
+	Attributes map[string]*BIOSSetting
+	// RebootRequired is true when settings are pending and the host has to be rebooted to apply them
+	RebootRequired bool
+}
+
+// BIOSSetting is the value of a BIOS setting in effect and the value pending, empty when none is pending
+type BIOSSetting struct {
+	Current string
+	Pending string
+}
+
+// Pending returns the BIOS settings pending, keyed by the attribute name
+func (c *BIOSConfiguration) Pending() map[string]string {
+	pending := map[string]string{}
+
+	for name, setting := range c.Attributes {
+		if setting.Pending != "" {
+			pending[name] = setting.Pending
+		}
+	}
+
+	return pending
+}
+
+// GetBiosConfiguration returns the current BIOS settings, keyed by the attribute name,
+// the settings pending to be applied on the next boot are returned by GetBiosConfigurationState.
+//
+// ErrBIOSConfigUnsupported is returned when the BMC firmware does not expose the BIOS settings.
+func (a *ASRockRack) GetBiosConfiguration(ctx context.Context) (biosConfig map[string]string, err error) {
+	attributes, err := a.biosAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	biosConfig = make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		biosConfig[attribute.Name] = attribute.CurrentValue
+	}
+
+	return biosConfig, nil
+}
+
+// GetBiosConfigurationState returns the BIOS settings with the values in effect and the values pending,
+// a pending value matching the value in effect is not considered pending.
+//
+// When the BMC firmware does not report whether a reboot is required, a reboot is considered required
+// when settings are pending. ErrBIOSConfigUnsupported is returned when the BMC firmware does not expose the BIOS settings.
+func (a *ASRockRack) GetBiosConfigurationState(ctx context.Context) (*BIOSConfiguration, error) {
+	registry, err := a.biosRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &BIOSConfiguration{Attributes: make(map[string]*BIOSSetting, len(registry.Attributes))}
+
+	for _, attribute := range registry.Attributes {
+		setting := &BIOSSetting{Current: attribute.CurrentValue}
+		if attribute.PendingValue != attribute.CurrentValue {
+			setting.Pending = attribute.PendingValue
+		}
+
+		config.Attributes[attribute.Name] = setting
+	}
+
+	config.RebootRequired = len(config.Pending()) > 0
+	if registry.RebootRequired != nil {
+		config.RebootRequired = *registry.RebootRequired
+	}
+
+	return config, nil
+}
+
+// biosAttributes returns the BIOS attribute registry along with the current values
+func (a *ASRockRack) biosAttributes(ctx context.Context) ([]*biosAttribute, error) {
+	registry, err := a.biosRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return registry.Attributes, nil
+}
+
+// biosRegistry returns the BIOS attribute registry payload
+func (a *ASRockRack) biosRegistry(ctx context.Context) (_ *biosAttributes, err error) {
+	defer func() { err = a.endpointError("BIOS attributes", biosAttributesEndpoint, err) }()
+
+	resp, statusCode, err := a.getWithRetry(ctx, biosAttributesEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(ErrBIOSConfigRead, err.Error())
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, errors.Wrap(ErrBIOSConfigUnsupported, fmt.Sprintf("response: %d", statusCode))
+	default:
+		return nil, wrapStatusCodeError(ErrBIOSConfigRead, statusCode)
+	}
+
+	registry := &biosAttributes{}
+	if err := json.Unmarshal(resp, registry); err != nil {
+		return nil, errors.Wrap(ErrBIOSConfigRead, err.Error())
+	}
+
+	return registry, nil
+}
+
+// SetBiosConfiguration stages the given BIOS settings, keyed by the attribute name, to be applied on the next boot.
+//
+// The settings are validated against the BIOS attribute registry and no setting is submitted
+// when any of them is invalid. Settings matching the pending value, or the current value when none is pending,
+// are not submitted. rebootRequired is true when settings are pending and the host has to be rebooted to apply them,
+// as reported by the BMC - firmware not reporting it is considered to require a reboot.
+func (a *ASRockRack) SetBiosConfiguration(ctx context.Context, settings map[string]string) (rebootRequired bool, err error) {
+	attributes, err := a.biosAttributes(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return a.applyBiosSettings(ctx, attributes, settings)
+}
+
+// applyBiosSettings validates the BIOS settings against the BIOS attribute registry and stages the valid settings
+// not matching the value in effect after the next boot - the pending value when one is staged, the current value otherwise.
+// No setting is submitted when any of them is invalid.
+func (a *ASRockRack) applyBiosSettings(ctx context.Context, attributes []*biosAttribute, settings map[string]string) (rebootRequired bool, err error) {
+	registry := make(map[string]*biosAttribute, len(attributes))
+	for _, attribute := range attributes {
+		registry[attribute.Name] = attribute
+	}
+
+	update := &biosAttributesUpdate{}
+	invalid := []string{}
+	staged := false
+
+	for name, value := range settings {
+		attribute, exists := registry[name]
+		if !exists {
+			invalid = append(invalid, name+": unknown attribute")
+			continue
+		}
+
+		if err := attribute.validate(value); err != nil {
+			invalid = append(invalid, name+": "+err.Error())
+			continue
+		}
+
+		// a setting already staged is not submitted again, it is applied on the next boot
+		if attribute.effectiveValue() == value {
+			staged = staged || attribute.effectiveValue() != attribute.CurrentValue
+			continue
+		}
+
+		update.Attributes = append(update.Attributes, &biosAttributeValue{Name: name, Value: value})
+	}
+
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return false, errors.Wrap(ErrBIOSAttributeInvalid, strings.Join(invalid, "; "))
+	}
+
+	if len(update.Attributes) == 0 {
+		return staged, nil
+	}
+
+	sort.Slice(update.Attributes, func(i, j int) bool { return update.Attributes[i].Name < update.Attributes[j].Name })
+
+	payload, err := json.Marshal(update)
+	if err != nil {
+		return false, errors.Wrap(ErrBIOSConfigWrite, err.Error())
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	resp, statusCode, err := a.queryHTTPS(ctx, biosAttributesEndpoint, "PUT", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return false, errors.Wrap(ErrBIOSConfigWrite, err.Error())
+	}
+
+	result := &biosAttributesUpdateResponse{}
+	if len(bytes.TrimSpace(resp)) > 0 {
+		if err := json.Unmarshal(resp, result); err != nil && statusCode == http.StatusOK {
+			return false, errors.Wrap(ErrBIOSConfigWrite, err.Error())
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		rejected := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			rejected = append(rejected, e.Name+": "+e.Reason)
+		}
+
+		return false, errors.Wrap(ErrBIOSConfigWrite, "rejected by the BMC - "+strings.Join(rejected, "; "))
+	}
+
+	if statusCode != http.StatusOK {
+		return false, wrapStatusCodeError(ErrBIOSConfigWrite, statusCode)
+	}
+
+	// firmware not reporting whether a reboot is required is considered to require a reboot to apply the settings
+	if result.RebootRequired == nil {
+		return true, nil
+	}
+
+	return *result.RebootRequired, nil
+}
+
+// biosDefaultsResponse is the BIOS restore defaults response payload
+type biosDefaultsResponse struct {
+	RebootRequired *bool `json:"reboot_required"`
+}
+
+// ResetBIOSConfiguration restores the BIOS settings to the factory defaults, the defaults are applied on the next boot.
+//
+// The reset is only requested when confirm is true, ErrBIOSResetNotConfirmed is returned otherwise.
+// rebootRequired is true when the host has to be rebooted to apply the defaults, firmware not reporting it
+// is considered to require a reboot. ErrBIOSConfigUnsupported is returned when the BMC firmware does not support
+// restoring the BIOS defaults.
+func (a *ASRockRack) ResetBIOSConfiguration(ctx context.Context, confirm bool) (rebootRequired bool, err error) {
+	if !confirm {
+		return false, ErrBIOSResetNotConfirmed
+	}
+
+	resp, statusCode, err := a.queryHTTPS(ctx, biosDefaultsEndpoint, "POST", nil, nil, 0)
+	if err != nil {
+		return false, errors.Wrap(ErrBIOSConfigWrite, err.Error())
+	}
+
+	switch statusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNoContent:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return false, wrapStatusCodeError(ErrBIOSConfigUnsupported, statusCode)
+	default:
+		return false, wrapStatusCodeError(ErrBIOSConfigWrite, statusCode)
+	}
+
+	result := &biosDefaultsResponse{}
+	if len(bytes.TrimSpace(resp)) > 0 {
+		if err := json.Unmarshal(resp, result); err != nil {
+			return false, errors.Wrap(ErrBIOSConfigWrite, err.Error())
+		}
+	}
+
+	if result.RebootRequired == nil {
+		return true, nil
+	}
+
+	return *result.RebootRequired, nil
+}
+
+// effectiveValue returns the value in effect after the next boot, the pending value when one is staged
+func (b *biosAttribute) effectiveValue() string {
+	if b.PendingValue != "" {
+		return b.PendingValue
+	}
+
+	return b.CurrentValue
+}
+
+// validate returns an error when the value is not valid for the BIOS attribute
+func (b *biosAttribute) validate(value string) error {
+	if b.ReadOnly {
+		return errors.New("attribute is read only")
+	}
+
+	switch b.Type {
+	case "enumeration":
+		for _, allowed := range b.AllowedValues {
+			if value == allowed {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("value %q not one of %s", value, strings.Join(b.AllowedValues, ", "))
+	case "integer":
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("value %q is not an integer", value)
+		}
+
+		if (b.Min != nil && v < *b.Min) || (b.Max != nil && v > *b.Max) {
+			return fmt.Errorf("value %d out of range", v)
+		}
+	}
+
+	return nil
+}
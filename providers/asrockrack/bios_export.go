@@ -0,0 +1,112 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// biosExportFormat identifies a BIOS configuration exported by ExportBIOSConfiguration
+	biosExportFormat = "asrockrack.bios-configuration"
+
+	// biosExportVersion is the version of the BIOS configuration export format
+	biosExportVersion = 1
+)
+
+// biosExport is the BIOS configuration export document
+//
+//	{
+//	  "format": "asrockrack.bios-configuration",
+//	  "version": 1,
+//	  "attributes": { "Boot Mode": "UEFI", "Hyper-Threading": "Enabled" }
+//	}
+//
+// the attributes are the BIOS attribute names and values as listed in the BIOS attribute registry.
+type biosExport struct {
+	Format     string            `json:"format"`
+	Version    int               `json:"version"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// ExportBIOSConfiguration returns the BIOS settings as a JSON document to be imported with ImportBIOSConfiguration,
+// the document lists the current value of each BIOS attribute which is not read only, keyed by the attribute name.
+//
+// ErrBIOSConfigUnsupported is returned when the BMC firmware does not expose the BIOS settings.
+func (a *ASRockRack) ExportBIOSConfiguration(ctx context.Context) ([]byte, error) {
+	attributes, err := a.biosAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &biosExport{
+		Format:     biosExportFormat,
+		Version:    biosExportVersion,
+		Attributes: make(map[string]string, len(attributes)),
+	}
+
+	for _, attribute := range attributes {
+		if attribute.ReadOnly {
+			continue
+		}
+
+		export.Attributes[attribute.Name] = attribute.CurrentValue
+	}
+
+	b, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(ErrBIOSConfigRead, err.Error())
+	}
+
+	return b, nil
+}
+
+// ImportBIOSConfiguration stages the BIOS settings exported by ExportBIOSConfiguration to be applied on the next boot,
+// rebootRequired is true when settings are pending and the host has to be rebooted to apply them.
+//
+// The settings are validated against the BIOS attribute registry of the BMC before any setting is submitted,
+// ErrBIOSAttributeUnsupported is returned listing the attributes the BIOS does not support
+// and ErrBIOSAttributeInvalid listing the values not valid for the BIOS. ErrBIOSConfigImport is returned
+// when the document is not a BIOS configuration export.
+func (a *ASRockRack) ImportBIOSConfiguration(ctx context.Context, blob []byte) (rebootRequired bool, err error) {
+	export := &biosExport{}
+	if err := json.Unmarshal(blob, export); err != nil {
+		return false, errors.Wrap(ErrBIOSConfigImport, err.Error())
+	}
+
+	if export.Format != biosExportFormat {
+		return false, errors.Wrap(ErrBIOSConfigImport, fmt.Sprintf("format %q not %s", export.Format, biosExportFormat))
+	}
+
+	if export.Version != biosExportVersion {
+		return false, errors.Wrap(ErrBIOSConfigImport, fmt.Sprintf("format version %d unsupported", export.Version))
+	}
+
+	attributes, err := a.biosAttributes(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	registry := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		registry[attribute.Name] = true
+	}
+
+	unsupported := []string{}
+	for name := range export.Attributes {
+		if !registry[name] {
+			unsupported = append(unsupported, name)
+		}
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return false, errors.Wrap(ErrBIOSAttributeUnsupported, strings.Join(unsupported, ", "))
+	}
+
+	return a.applyBiosSettings(ctx, attributes, export.Attributes)
+}
@@ -0,0 +1,129 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BIOSConfigurationExportImport(t *testing.T) {
+	var submitted *biosAttributesUpdate
+
+	server := mockBIOSBMC(t, false, func(w http.ResponseWriter, r *http.Request) {
+		submitted = &biosAttributesUpdate{}
+		if err := json.NewDecoder(r.Body).Decode(submitted); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{ "reboot_required": true }`))
+	})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	blob, err := client.ExportBIOSConfiguration(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	export := &biosExport{}
+	if err := json.Unmarshal(blob, export); err != nil {
+		t.Fatal(err)
+	}
+
+	// read only attributes are not exported
+	assert.Equal(t, "asrockrack.bios-configuration", export.Format)
+	assert.Equal(t, 1, export.Version)
+	assert.Equal(t, 7, len(export.Attributes))
+	assert.Equal(t, "UEFI", export.Attributes["Boot Mode"])
+	assert.NotContains(t, export.Attributes, "BIOS Version")
+
+	// the exported configuration matches the current settings
+	rebootRequired, err := client.ImportBIOSConfiguration(context.TODO(), blob)
+	assert.Nil(t, err)
+	assert.False(t, rebootRequired)
+	assert.Nil(t, submitted)
+
+	// the changed settings are staged
+	export.Attributes["Hyper-Threading"] = "Disabled"
+	export.Attributes["Boot Timeout"] = "10"
+
+	changed, _ := json.Marshal(export)
+
+	rebootRequired, err = client.ImportBIOSConfiguration(context.TODO(), changed)
+	assert.Nil(t, err)
+	assert.True(t, rebootRequired)
+	assert.Equal(t, []*biosAttributeValue{{Name: "Boot Timeout", Value: "10"}, {Name: "Hyper-Threading", Value: "Disabled"}}, submitted.Attributes)
+}
+
+func Test_ImportBIOSConfigurationInvalid(t *testing.T) {
+	var submitted bool
+
+	server := mockBIOSBMC(t, false, func(w http.ResponseWriter, r *http.Request) {
+		submitted = true
+	})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	testCases := []struct {
+		name   string
+		blob   string
+		err    error
+		errMsg string
+	}{
+		{
+			"unsupported attributes",
+			`{ "format": "asrockrack.bios-configuration", "version": 1, "attributes": { "SMT Control": "Auto", "Hyper-Threading": "Disabled", "NUMA Nodes Per Socket": "NPS1" } }`,
+			ErrBIOSAttributeUnsupported,
+			"NUMA Nodes Per Socket, SMT Control: BIOS attributes not supported by the BIOS",
+		},
+		{
+			"invalid value",
+			`{ "format": "asrockrack.bios-configuration", "version": 1, "attributes": { "Power Profile": "Turbo" } }`,
+			ErrBIOSAttributeInvalid,
+			`Power Profile: value "Turbo" not one of Performance, Balanced, Power Saving: invalid BIOS attribute`,
+		},
+		{
+			"not an export",
+			`{ "Hyper-Threading": "Disabled" }`,
+			ErrBIOSConfigImport,
+			`format "" not asrockrack.bios-configuration: invalid BIOS configuration export`,
+		},
+		{
+			"unsupported version",
+			`{ "format": "asrockrack.bios-configuration", "version": 2, "attributes": {} }`,
+			ErrBIOSConfigImport,
+			"format version 2 unsupported: invalid BIOS configuration export",
+		},
+		{
+			"not JSON",
+			`Hyper-Threading=Disabled`,
+			ErrBIOSConfigImport,
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			submitted = false
+
+			_, err := client.ImportBIOSConfiguration(context.TODO(), []byte(tc.blob))
+			assert.True(t, errors.Is(err, tc.err))
+			assert.False(t, submitted)
+
+			if tc.errMsg != "" {
+				assert.Equal(t, tc.errMsg, err.Error())
+			}
+		})
+	}
+}
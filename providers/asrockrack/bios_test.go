@@ -0,0 +1,396 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBIOSBMC returns a BMC mock serving the BIOS attribute registry fixture,
+// BIOS settings updates are passed to the update handler.
+// The BIOS attributes endpoint is not served when unsupported is set.
+func mockBIOSBMC(t *testing.T, unsupported bool, update http.HandlerFunc) *httptest.Server {
+	b, err := os.ReadFile("./fixtures/E3C246D4I-NL/bios-attributes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := http.NewServeMux()
+	if !unsupported {
+		handler.HandleFunc("/api/asrr/bios/attributes", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPut && update != nil {
+				update(w, r)
+				return
+			}
+
+			_, _ = w.Write(b)
+		})
+	}
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_GetBiosConfiguration(t *testing.T) {
+	server := mockBIOSBMC(t, false, nil)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	biosConfig, err := client.GetBiosConfiguration(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Hyper-Threading":                 "Enabled",
+		"SR-IOV Support":                  "Disabled",
+		"VT-d":                            "Enabled",
+		"Power Profile":                   "Performance",
+		"Boot Mode":                       "UEFI",
+		"Boot Timeout":                    "5",
+		"Serial Port Console Redirection": "Enabled",
+		"BIOS Version":                    "L2.07B",
+	}
+
+	assert.Equal(t, expected, biosConfig)
+}
+
+func Test_GetBiosConfigurationUnsupported(t *testing.T) {
+	server := mockBIOSBMC(t, true, nil)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err := client.GetBiosConfiguration(context.TODO())
+	assert.True(t, errors.Is(err, ErrBIOSConfigUnsupported))
+}
+
+func Test_GetBiosConfigurationState(t *testing.T) {
+	pending, err := os.ReadFile("./fixtures/E3C246D4I-NL/bios-attributes-pending.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/bios/attributes", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pending)
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	config, err := client.GetBiosConfigurationState(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, config.RebootRequired)
+	assert.Equal(t, 8, len(config.Attributes))
+
+	// the pending value matching the current value is not pending
+	assert.Equal(t, map[string]string{"Hyper-Threading": "Disabled", "Boot Timeout": "10"}, config.Pending())
+	assert.Equal(t, &BIOSSetting{Current: "Enabled", Pending: "Disabled"}, config.Attributes["Hyper-Threading"])
+	assert.Equal(t, &BIOSSetting{Current: "Enabled"}, config.Attributes["VT-d"])
+	assert.Equal(t, &BIOSSetting{Current: "UEFI"}, config.Attributes["Boot Mode"])
+
+	// the current values are returned as in effect
+	biosConfig, err := client.GetBiosConfiguration(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "Enabled", biosConfig["Hyper-Threading"])
+	assert.Equal(t, "5", biosConfig["Boot Timeout"])
+}
+
+func Test_GetBiosConfigurationStateNonePending(t *testing.T) {
+	server := mockBIOSBMC(t, false, nil)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	config, err := client.GetBiosConfigurationState(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the registry does not report whether a reboot is required, none is without settings pending
+	assert.False(t, config.RebootRequired)
+	assert.Empty(t, config.Pending())
+	assert.Equal(t, "UEFI", config.Attributes["Boot Mode"].Current)
+}
+
+func Test_SetBiosConfiguration(t *testing.T) {
+	var submitted *biosAttributesUpdate
+
+	server := mockBIOSBMC(t, false, func(w http.ResponseWriter, r *http.Request) {
+		submitted = &biosAttributesUpdate{}
+		if err := json.NewDecoder(r.Body).Decode(submitted); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{ "reboot_required": true }`))
+	})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	testCases := []struct {
+		name           string
+		settings       map[string]string
+		rebootRequired bool
+		submitted      []*biosAttributeValue
+		err            error
+		errMsg         string
+	}{
+		{
+			"valid settings are staged",
+			map[string]string{"SR-IOV Support": "Enabled", "Boot Timeout": "10", "Hyper-Threading": "Enabled"},
+			true,
+			[]*biosAttributeValue{{Name: "Boot Timeout", Value: "10"}, {Name: "SR-IOV Support", Value: "Enabled"}},
+			nil,
+			"",
+		},
+		{
+			"current values are not submitted",
+			map[string]string{"Hyper-Threading": "Enabled"},
+			false,
+			nil,
+			nil,
+			"",
+		},
+		{
+			"nothing is submitted with an invalid setting",
+			map[string]string{"SR-IOV Support": "Enabled", "Power Profile": "Turbo"},
+			false,
+			nil,
+			ErrBIOSAttributeInvalid,
+			`Power Profile: value "Turbo" not one of Performance, Balanced, Power Saving: invalid BIOS attribute`,
+		},
+		{
+			"unknown, read only and out of range settings",
+			map[string]string{"Foo": "Bar", "BIOS Version": "L2.08", "Boot Timeout": "70000"},
+			false,
+			nil,
+			ErrBIOSAttributeInvalid,
+			"BIOS Version: attribute is read only; Boot Timeout: value 70000 out of range; Foo: unknown attribute: invalid BIOS attribute",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			submitted = nil
+
+			rebootRequired, err := client.SetBiosConfiguration(context.TODO(), tc.settings)
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err))
+				assert.Equal(t, tc.errMsg, err.Error())
+				assert.Nil(t, submitted)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.rebootRequired, rebootRequired)
+
+			if tc.submitted == nil {
+				assert.Nil(t, submitted)
+				return
+			}
+
+			assert.Equal(t, tc.submitted, submitted.Attributes)
+		})
+	}
+}
+
+func Test_SetBiosConfigurationPending(t *testing.T) {
+	b, err := os.ReadFile("./fixtures/E3C246D4I-NL/bios-attributes-pending.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var submitted *biosAttributesUpdate
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/bios/attributes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			submitted = &biosAttributesUpdate{}
+			if err := json.NewDecoder(r.Body).Decode(submitted); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			_, _ = w.Write([]byte(`{ "reboot_required": true }`))
+			return
+		}
+
+		_, _ = w.Write(b)
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	testCases := []struct {
+		name           string
+		settings       map[string]string
+		rebootRequired bool
+		submitted      []*biosAttributeValue
+	}{
+		{
+			// Hyper-Threading is Enabled, Disabled is pending
+			"current value undoes the pending value",
+			map[string]string{"Hyper-Threading": "Enabled", "Boot Timeout": "5"},
+			true,
+			[]*biosAttributeValue{{Name: "Boot Timeout", Value: "5"}, {Name: "Hyper-Threading", Value: "Enabled"}},
+		},
+		{
+			"pending value is not submitted again",
+			map[string]string{"Hyper-Threading": "Disabled"},
+			true,
+			nil,
+		},
+		{
+			// the VT-d pending value is the current value
+			"current value with no change pending",
+			map[string]string{"VT-d": "Enabled"},
+			false,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			submitted = nil
+
+			rebootRequired, err := client.SetBiosConfiguration(context.TODO(), tc.settings)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.rebootRequired, rebootRequired)
+
+			if tc.submitted == nil {
+				assert.Nil(t, submitted)
+				return
+			}
+
+			assert.Equal(t, tc.submitted, submitted.Attributes)
+		})
+	}
+}
+
+func Test_SetBiosConfigurationRebootRequired(t *testing.T) {
+	testCases := []struct {
+		name           string
+		response       string
+		rebootRequired bool
+	}{
+		{"reboot required", `{ "reboot_required": true }`, true},
+		{"reboot not required", `{ "reboot_required": false }`, false},
+		// firmware not reporting it is considered to require a reboot
+		{"not reported", `{ "errors": [] }`, true},
+		{"empty response", ``, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := mockBIOSBMC(t, false, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.response))
+			})
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			rebootRequired, err := client.SetBiosConfiguration(context.TODO(), map[string]string{"SR-IOV Support": "Enabled"})
+			assert.Nil(t, err)
+			assert.Equal(t, tc.rebootRequired, rebootRequired)
+		})
+	}
+}
+
+func Test_SetBiosConfigurationRejected(t *testing.T) {
+	server := mockBIOSBMC(t, false, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{ "reboot_required": false, "errors": [ { "name": "SR-IOV Support", "reason": "requires VT-d to be enabled" } ] }`))
+	})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	rebootRequired, err := client.SetBiosConfiguration(context.TODO(), map[string]string{"SR-IOV Support": "Enabled"})
+	assert.False(t, rebootRequired)
+	assert.True(t, errors.Is(err, ErrBIOSConfigWrite))
+	assert.Contains(t, err.Error(), "SR-IOV Support: requires VT-d to be enabled")
+}
+
+func Test_ResetBIOSConfiguration(t *testing.T) {
+	testCases := []struct {
+		name           string
+		confirm        bool
+		statusCode     int
+		response       string
+		rebootRequired bool
+		requests       int
+		err            error
+	}{
+		{"reboot required", true, http.StatusOK, `{ "reboot_required": true }`, true, 1, nil},
+		{"reboot not required", true, http.StatusOK, `{ "reboot_required": false }`, false, 1, nil},
+		{"reboot not reported", true, http.StatusOK, ``, true, 1, nil},
+		{"not confirmed", false, http.StatusOK, `{ "reboot_required": true }`, false, 0, ErrBIOSResetNotConfirmed},
+		{"unsupported", true, http.StatusNotFound, ``, false, 1, ErrBIOSConfigUnsupported},
+		{"BMC error", true, http.StatusInternalServerError, ``, false, 1, ErrBIOSConfigWrite},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/asrr/bios/restore-defaults", func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				if r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+
+				w.WriteHeader(tc.statusCode)
+				_, _ = w.Write([]byte(tc.response))
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+			rebootRequired, err := client.ResetBIOSConfiguration(context.TODO(), tc.confirm)
+			assert.Equal(t, tc.requests, requests)
+
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err))
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.rebootRequired, rebootRequired)
+		})
+	}
+}
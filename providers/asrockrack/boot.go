@@ -0,0 +1,181 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bmc-toolbox/bmclib/v2/constants"
+	"github.com/pkg/errors"
+)
+
+var (
+	// bootDevices maps the boot device to the BMC boot device identifier
+	bootDevices = map[string]string{
+		"pxe":   "pxe",
+		"disk":  "hdd",
+		"bios":  "bios_setup",
+		"cdrom": "cdrom",
+	}
+)
+
+// bootOrderEntry is a boot option in the BIOS boot order
+// { "priority": 0, "name": "UEFI: PXE IP4 Intel(R) Ethernet Controller X710 for 10GbE SFP+", "enabled": 1 }
+type bootOrderEntry struct {
+	Priority int    `json:"priority"`
+	Name     string `json:"name"`
+	Enabled  int    `json:"enabled"`
+}
+
+// bootOptions is the boot device override payload
+type bootOptions struct {
+	Device string `json:"boot_device"`
+	// Persistent is 1 when the boot order is to be changed, 0 for a one time override on the next boot
+	Persistent int `json:"persistent"`
+	// BootMode is either uefi or legacy
+	BootMode string `json:"boot_mode"`
+}
+
+// BootDeviceSet sets the next boot device - pxe, disk, bios, cdrom.
+//
+// When setPersistent is true, the boot order is changed, else the device is booted once on the next boot,
+// efiBoot selects UEFI boot over legacy.
+func (a *ASRockRack) BootDeviceSet(ctx context.Context, bootDevice string, setPersistent, efiBoot bool) (ok bool, err error) {
+	device, exists := bootDevices[strings.ToLower(bootDevice)]
+	if !exists {
+		return false, errors.New("requested boot device unknown: " + bootDevice)
+	}
+
+	options := &bootOptions{Device: device, BootMode: "legacy"}
+	if setPersistent {
+		options.Persistent = 1
+	}
+
+	if efiBoot {
+		options.BootMode = "uefi"
+	}
+
+	if err := a.setBootOptions(ctx, options); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (a *ASRockRack) setBootOptions(ctx context.Context, options *bootOptions) error {
+	payload, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	_, statusCode, err := a.queryHTTPS(ctx, "api/settings/boot-options", "PUT", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return statusCodeError(statusCode)
+	}
+
+	return nil
+}
+
+// GetBootOrder returns the enabled boot devices in the BIOS boot order, the first device is booted first.
+//
+// Firmware which does not expose the BIOS boot order returns only the next boot device override,
+// boot options not identified as a PXE, disk, CD-ROM, USB or BIOS setup boot are returned as BootDeviceUnknown.
+func (a *ASRockRack) GetBootOrder(ctx context.Context) ([]constants.BootDevice, error) {
+	entries, err := a.bootOrder(ctx)
+	if err == nil {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Priority < entries[j].Priority })
+
+		devices := []constants.BootDevice{}
+		for _, entry := range entries {
+			if entry.Enabled == 1 {
+				devices = append(devices, bootDeviceFromName(entry.Name))
+			}
+		}
+
+		return devices, nil
+	}
+
+	if !errors.Is(err, ErrUnsupported) {
+		return nil, errors.Wrap(ErrBootOrderRead, err.Error())
+	}
+
+	a.log.V(2).Info("BIOS boot order not supported by the BMC firmware, reading the boot device override")
+
+	options, err := a.bootOptions(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ErrBootOrderRead, err.Error())
+	}
+
+	return []constants.BootDevice{bootDeviceFromName(options.Device)}, nil
+}
+
+// bootDeviceFromName returns the boot device for the BMC boot device identifier or the BIOS boot option name
+func bootDeviceFromName(name string) constants.BootDevice {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	for device, id := range bootDevices {
+		if name == id {
+			return constants.BootDevice(device)
+		}
+	}
+
+	switch {
+	case strings.Contains(name, "pxe"), strings.Contains(name, "network"):
+		return constants.BootDevicePXE
+	case strings.Contains(name, "cdrom"), strings.Contains(name, "dvd"):
+		return constants.BootDeviceCDROM
+	case strings.Contains(name, "usb"):
+		return constants.BootDeviceUSB
+	case strings.Contains(name, "setup"):
+		return constants.BootDeviceBIOS
+	case strings.Contains(name, "hdd"), strings.Contains(name, "ssd"), strings.Contains(name, "nvme"),
+		strings.Contains(name, "sata"), strings.Contains(name, "disk"), strings.Contains(name, "uefi os"):
+		return constants.BootDeviceDisk
+	default:
+		return constants.BootDeviceUnknown
+	}
+}
+
+func (a *ASRockRack) bootOrder(ctx context.Context) ([]*bootOrderEntry, error) {
+	resp, statusCode, err := a.getWithRetry(ctx, "api/asrr/boot-order")
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCodeError(statusCode)
+	}
+
+	entries := []*bootOrderEntry{}
+	if err := json.Unmarshal(resp, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (a *ASRockRack) bootOptions(ctx context.Context) (*bootOptions, error) {
+	resp, statusCode, err := a.getWithRetry(ctx, "api/settings/boot-options")
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCodeError(statusCode)
+	}
+
+	options := &bootOptions{}
+	if err := json.Unmarshal(resp, options); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
@@ -0,0 +1,101 @@
+package asrockrack
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	// metadataBootMode is the BIOS boot mode - uefi, legacy or dual when both UEFI and legacy boot options are enabled
+	metadataBootMode = "bios.boot_mode"
+	// metadataUEFISupported is true when the BIOS supports the UEFI boot mode
+	metadataUEFISupported = "bios.uefi_supported"
+)
+
+// bootModeAttributes are the BIOS attribute names of the boot mode selection, by BIOS revision
+var bootModeAttributes = []string{"Boot Mode", "Boot Mode Select", "Boot Option Filter"}
+
+// biosBootModeAttributes sets the BIOS boot mode metadata read from the BIOS settings,
+// the metadata is omitted when the BMC firmware does not expose the BIOS settings or the BIOS has no boot mode selection.
+func (a *ASRockRack) biosBootModeAttributes(ctx context.Context, device *common.Device) error {
+	attributes, err := a.biosAttributes(ctx)
+	if err != nil {
+		if errors.Is(err, ErrBIOSConfigUnsupported) {
+			return nil
+		}
+
+		return err
+	}
+
+	bootModeMetadata(device, findBootModeAttribute(attributes))
+
+	return nil
+}
+
+// findBootModeAttribute returns the boot mode BIOS attribute, nil when the BIOS has no boot mode selection
+func findBootModeAttribute(attributes []*biosAttribute) *biosAttribute {
+	for _, name := range bootModeAttributes {
+		for _, attribute := range attributes {
+			if strings.EqualFold(attribute.Name, name) {
+				return attribute
+			}
+		}
+	}
+
+	return nil
+}
+
+// bootModeMetadata sets the boot mode and UEFI support metadata from the boot mode BIOS attribute
+//
+// The values differ by BIOS revision - "UEFI", "Legacy", "UEFI only", "Legacy only", "UEFI and Legacy", "DUAL".
+// UEFI is supported when the current or any allowed value selects UEFI, the support is not reported
+// when the boot mode is legacy and the BIOS does not list the allowed values.
+func bootModeMetadata(device *common.Device, attribute *biosAttribute) {
+	if attribute == nil {
+		return
+	}
+
+	mode := bootMode(attribute.CurrentValue)
+	if mode == "" {
+		return
+	}
+
+	device.Metadata[metadataBootMode] = mode
+
+	supported := mode != "legacy"
+	for _, value := range attribute.AllowedValues {
+		if allowed := bootMode(value); allowed != "" && allowed != "legacy" {
+			supported = true
+		}
+	}
+
+	if !supported && len(attribute.AllowedValues) == 0 {
+		return
+	}
+
+	device.Metadata[metadataUEFISupported] = strconv.FormatBool(supported)
+}
+
+// bootMode returns the boot mode for the boot mode BIOS attribute value - uefi, legacy, dual,
+// an empty string when the value is not a boot mode.
+func bootMode(value string) string {
+	value = strings.ToLower(value)
+
+	uefi := strings.Contains(value, "uefi")
+	legacy := strings.Contains(value, "legacy")
+
+	switch {
+	case strings.Contains(value, "dual"), uefi && legacy:
+		return "dual"
+	case uefi:
+		return "uefi"
+	case legacy:
+		return "legacy"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,129 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InventoryBootMode(t *testing.T) {
+	uefi, err := os.ReadFile("./fixtures/E3C246D4I-NL/bios-attributes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy := []byte(`{
+		"attributes": [
+			{ "name": "Boot Mode Select", "current_value": "Legacy", "pending_value": "", "type": "enumeration", "allowed_values": ["Legacy", "UEFI", "DUAL"], "read_only": false }
+		]
+	}`)
+
+	testCases := []struct {
+		name     string
+		options  []asrockracktest.Option
+		expected map[string]string
+	}{
+		{
+			"uefi",
+			[]asrockracktest.Option{
+				asrockracktest.WithResponse("api/asrr/bios/attributes", http.StatusOK, uefi),
+			},
+			map[string]string{metadataBootMode: "uefi", metadataUEFISupported: "true"},
+		},
+		{
+			"legacy",
+			[]asrockracktest.Option{
+				asrockracktest.WithResponse("api/asrr/bios/attributes", http.StatusOK, legacy),
+			},
+			map[string]string{metadataBootMode: "legacy", metadataUEFISupported: "true"},
+		},
+		{
+			// the BIOS settings endpoint is not available
+			"unsupported",
+			nil,
+			map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := asrockracktest.NewServer(tc.options...)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			device, err := client.Inventory(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]string{}
+			for _, key := range []string{metadataBootMode, metadataUEFISupported} {
+				if value, exists := device.Metadata[key]; exists {
+					got[key] = value
+				}
+			}
+
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_bootModeMetadata(t *testing.T) {
+	testCases := []struct {
+		name      string
+		attribute *biosAttribute
+		expected  map[string]string
+	}{
+		{
+			"uefi only",
+			&biosAttribute{Name: "Boot Option Filter", CurrentValue: "UEFI only", AllowedValues: []string{"UEFI and Legacy", "Legacy only", "UEFI only"}},
+			map[string]string{metadataBootMode: "uefi", metadataUEFISupported: "true"},
+		},
+		{
+			"dual",
+			&biosAttribute{Name: "Boot Option Filter", CurrentValue: "UEFI and Legacy"},
+			map[string]string{metadataBootMode: "dual", metadataUEFISupported: "true"},
+		},
+		{
+			"legacy without UEFI",
+			&biosAttribute{Name: "Boot Mode", CurrentValue: "Legacy", AllowedValues: []string{"Legacy"}},
+			map[string]string{metadataBootMode: "legacy", metadataUEFISupported: "false"},
+		},
+		{
+			// the UEFI support is not known without the allowed values
+			"legacy without allowed values",
+			&biosAttribute{Name: "Boot Mode", CurrentValue: "Legacy"},
+			map[string]string{metadataBootMode: "legacy"},
+		},
+		{
+			"unknown value",
+			&biosAttribute{Name: "Boot Mode", CurrentValue: "Auto"},
+			map[string]string{},
+		},
+		{
+			"no boot mode attribute",
+			nil,
+			map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := common.NewDevice()
+			device.Metadata = map[string]string{}
+
+			bootModeMetadata(&device, tc.attribute)
+
+			assert.Equal(t, tc.expected, device.Metadata)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/constants"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BootDeviceSet(t *testing.T) {
+	testCases := []struct {
+		name       string
+		device     string
+		persistent bool
+		efiBoot    bool
+		expected   *bootOptions
+		err        bool
+	}{
+		{"pxe once legacy", "pxe", false, false, &bootOptions{Device: "pxe", Persistent: 0, BootMode: "legacy"}, false},
+		{"pxe once uefi", "PXE", false, true, &bootOptions{Device: "pxe", Persistent: 0, BootMode: "uefi"}, false},
+		{"pxe persistent legacy", "pxe", true, false, &bootOptions{Device: "pxe", Persistent: 1, BootMode: "legacy"}, false},
+		{"pxe persistent uefi", "pxe", true, true, &bootOptions{Device: "pxe", Persistent: 1, BootMode: "uefi"}, false},
+		{"disk once uefi", "disk", false, true, &bootOptions{Device: "hdd", Persistent: 0, BootMode: "uefi"}, false},
+		{"disk persistent legacy", "disk", true, false, &bootOptions{Device: "hdd", Persistent: 1, BootMode: "legacy"}, false},
+		{"bios once", "bios", false, true, &bootOptions{Device: "bios_setup", Persistent: 0, BootMode: "uefi"}, false},
+		{"cdrom persistent uefi", "cdrom", true, true, &bootOptions{Device: "cdrom", Persistent: 1, BootMode: "uefi"}, false},
+		{"unknown device", "floppy", false, false, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *bootOptions
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/settings/boot-options", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "PUT" || r.Header.Get("Content-Type") != "application/json" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				got = &bootOptions{}
+				if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			ok, err := client.BootDeviceSet(context.TODO(), tc.device, tc.persistent, tc.efiBoot)
+			if tc.err {
+				assert.NotNil(t, err)
+				assert.False(t, ok)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_GetBootOrder(t *testing.T) {
+	bootOrder, err := os.ReadFile("./fixtures/E3C246D4I-NL/boot-order.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name      string
+		bootOrder []byte
+		expected  []constants.BootDevice
+	}{
+		{
+			"boot order",
+			bootOrder,
+			[]constants.BootDevice{
+				constants.BootDevicePXE,
+				constants.BootDeviceDisk,
+				constants.BootDeviceUnknown,
+				constants.BootDeviceCDROM,
+			},
+		},
+		{
+			"boot override only",
+			nil,
+			[]constants.BootDevice{constants.BootDeviceDisk},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.NewServeMux()
+			if tc.bootOrder != nil {
+				handler.HandleFunc("/api/asrr/boot-order", func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write(tc.bootOrder)
+				})
+			}
+
+			handler.HandleFunc("/api/settings/boot-options", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"boot_device": "hdd", "persistent": 0, "boot_mode": "uefi"}`))
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			devices, err := client.GetBootOrder(context.TODO())
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, devices)
+		})
+	}
+}
+
+func Test_GetBootOrderUnsupported(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err := client.GetBootOrder(context.TODO())
+	assert.ErrorIs(t, err, ErrBootOrderRead)
+}
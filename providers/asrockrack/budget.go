@@ -0,0 +1,67 @@
+package asrockrack
+
+import (
+	"context"
+	"time"
+)
+
+// Budget bounds the total time spent on BMC operations, to keep a few hung BMCs from consuming
+// the time available to a fleet scan. A Budget is safe for concurrent use by the scan workers.
+//
+// Each operation is run with a context whose deadline is the per operation timeout, capped by the remaining budget.
+// Queries retried by the retry policy are not retried when the retry delay exceeds the context deadline,
+// so retries do not extend an operation past its budget.
+type Budget struct {
+	deadline     time.Time
+	perOperation time.Duration
+}
+
+// NewBudget returns a Budget of the total duration starting now,
+// each operation is bounded by the per operation timeout - a zero value bounds operations by the remaining budget only.
+func NewBudget(total, perOperation time.Duration) *Budget {
+	return &Budget{deadline: time.Now().Add(total), perOperation: perOperation}
+}
+
+// Remaining returns the time remaining in the budget
+func (b *Budget) Remaining() time.Duration {
+	remaining := time.Until(b.deadline)
+	if remaining < 0 {
+		return 0
+	}
+
+	return remaining
+}
+
+// Context returns a context for a single operation, derived from the parent context
+// and bounded by the per operation timeout and the remaining budget.
+//
+// ErrBudgetExhausted is returned when no time remains in the budget, the caller must call the cancel func otherwise.
+func (b *Budget) Context(parent context.Context) (context.Context, context.CancelFunc, error) {
+	if b.Remaining() <= 0 {
+		return nil, nil, ErrBudgetExhausted
+	}
+
+	deadline := b.deadline
+	if b.perOperation > 0 {
+		if operationDeadline := time.Now().Add(b.perOperation); operationDeadline.Before(deadline) {
+			deadline = operationDeadline
+		}
+	}
+
+	ctx, cancel := context.WithDeadline(parent, deadline)
+
+	return ctx, cancel, nil
+}
+
+// Run runs the operation with a context from Context, returning ErrBudgetExhausted
+// without running the operation when no time remains in the budget.
+func (b *Budget) Run(ctx context.Context, operation func(ctx context.Context) error) error {
+	ctx, cancel, err := b.Context(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer cancel()
+
+	return operation(ctx)
+}
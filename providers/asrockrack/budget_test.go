@@ -0,0 +1,139 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BudgetSlowHosts(t *testing.T) {
+	// hung BMCs respond once the request is canceled or after a long while
+	hung := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer hung.Close()
+
+	hungURL, _ := url.Parse(hung.URL)
+
+	budget := NewBudget(300*time.Millisecond, 100*time.Millisecond)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		exhausted int
+	)
+
+	start := time.Now()
+
+	// 2 workers scanning 10 hung hosts
+	hosts := make(chan string, 10)
+	for i := 0; i < 10; i++ {
+		hosts <- hungURL.Host
+	}
+	close(hosts)
+
+	for w := 0; w < 2; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for host := range hosts {
+				client := NewWithOptions(host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+				err := budget.Run(context.TODO(), func(ctx context.Context) error {
+					_, err := client.Sensors(ctx)
+					return err
+				})
+
+				if err == ErrBudgetExhausted {
+					mu.Lock()
+					exhausted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// the scan is bounded by the total budget, the remaining hosts are not queried
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Greater(t, exhausted, 0)
+	assert.Equal(t, time.Duration(0), budget.Remaining())
+}
+
+func Test_BudgetRetries(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+
+	busy := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer busy.Close()
+
+	busyURL, _ := url.Parse(busy.URL)
+	client := NewWithOptions(busyURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	// the default retry policy waits at least 250ms before retrying, past the operation deadline
+	budget := NewBudget(time.Second, 100*time.Millisecond)
+
+	start := time.Now()
+	err := budget.Run(context.TODO(), func(ctx context.Context) error {
+		_, err := client.Sensors(ctx)
+		return err
+	})
+
+	assert.ErrorIs(t, err, ErrBMCBusy)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, 1, requests)
+}
+
+func Test_BudgetContext(t *testing.T) {
+	budget := NewBudget(time.Hour, time.Minute)
+
+	ctx, cancel, err := budget.Context(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer cancel()
+
+	// bounded by the per operation timeout
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	// bounded by the remaining budget
+	budget = NewBudget(time.Second, time.Minute)
+
+	ctx, cancel, err = budget.Context(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer cancel()
+
+	deadline, _ = ctx.Deadline()
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+
+	// exhausted
+	budget = NewBudget(0, time.Minute)
+
+	_, _, err = budget.Context(context.TODO())
+	assert.ErrorIs(t, err, ErrBudgetExhausted)
+}
@@ -0,0 +1,133 @@
+package asrockrack
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// CanonicalizeDevice sorts the component slices of the device in place, for equal hardware to be listed
+// in the same order regardless of the order returned by the BMC, e.g to compare or hash inventories.
+//
+// The components are ordered by the serial number, the ID or slot and the description, components with equal keys
+// by their JSON encoding. The metadata maps are not ordered, they are encoded in the key order by encoding/json.
+//
+// The inventory is returned in the order returned by the BMC, CanonicalizeDevice is applied by the caller as needed.
+func CanonicalizeDevice(device *common.Device) {
+	if device == nil {
+		return
+	}
+
+	sortComponents(len(device.CPLDs), func(i int) []string {
+		return componentKey(&device.CPLDs[i].Common, device.CPLDs[i])
+	}, func(i, j int) { device.CPLDs[i], device.CPLDs[j] = device.CPLDs[j], device.CPLDs[i] })
+
+	sortComponents(len(device.TPMs), func(i int) []string {
+		return componentKey(&device.TPMs[i].Common, device.TPMs[i])
+	}, func(i, j int) { device.TPMs[i], device.TPMs[j] = device.TPMs[j], device.TPMs[i] })
+
+	sortComponents(len(device.GPUs), func(i int) []string {
+		return componentKey(&device.GPUs[i].Common, device.GPUs[i])
+	}, func(i, j int) { device.GPUs[i], device.GPUs[j] = device.GPUs[j], device.GPUs[i] })
+
+	sortComponents(len(device.CPUs), func(i int) []string {
+		return componentKey(&device.CPUs[i].Common, device.CPUs[i], device.CPUs[i].Slot, device.CPUs[i].ID)
+	}, func(i, j int) { device.CPUs[i], device.CPUs[j] = device.CPUs[j], device.CPUs[i] })
+
+	sortComponents(len(device.Memory), func(i int) []string {
+		return componentKey(&device.Memory[i].Common, device.Memory[i], device.Memory[i].Slot, device.Memory[i].ID)
+	}, func(i, j int) { device.Memory[i], device.Memory[j] = device.Memory[j], device.Memory[i] })
+
+	for _, nic := range device.NICs {
+		canonicalizeNICPorts(nic)
+	}
+
+	if device.BMC != nil {
+		canonicalizeNICPorts(device.BMC.NIC)
+	}
+
+	sortComponents(len(device.NICs), func(i int) []string {
+		return componentKey(&device.NICs[i].Common, device.NICs[i], device.NICs[i].ID)
+	}, func(i, j int) { device.NICs[i], device.NICs[j] = device.NICs[j], device.NICs[i] })
+
+	sortComponents(len(device.Drives), func(i int) []string {
+		return componentKey(&device.Drives[i].Common, device.Drives[i], device.Drives[i].ID)
+	}, func(i, j int) { device.Drives[i], device.Drives[j] = device.Drives[j], device.Drives[i] })
+
+	sortComponents(len(device.StorageControllers), func(i int) []string {
+		return componentKey(&device.StorageControllers[i].Common, device.StorageControllers[i], device.StorageControllers[i].ID)
+	}, func(i, j int) {
+		device.StorageControllers[i], device.StorageControllers[j] = device.StorageControllers[j], device.StorageControllers[i]
+	})
+
+	sortComponents(len(device.PSUs), func(i int) []string {
+		return componentKey(&device.PSUs[i].Common, device.PSUs[i], device.PSUs[i].ID)
+	}, func(i, j int) { device.PSUs[i], device.PSUs[j] = device.PSUs[j], device.PSUs[i] })
+
+	sortComponents(len(device.Enclosures), func(i int) []string {
+		return componentKey(&device.Enclosures[i].Common, device.Enclosures[i], device.Enclosures[i].ID)
+	}, func(i, j int) {
+		device.Enclosures[i], device.Enclosures[j] = device.Enclosures[j], device.Enclosures[i]
+	})
+}
+
+// canonicalizeNICPorts sorts the NIC ports by the MAC address and the port ID
+func canonicalizeNICPorts(nic *common.NIC) {
+	if nic == nil {
+		return
+	}
+
+	sortComponents(len(nic.NICPorts), func(i int) []string {
+		return componentKey(&nic.NICPorts[i].Common, nic.NICPorts[i], nic.NICPorts[i].MacAddress, nic.NICPorts[i].ID)
+	}, func(i, j int) { nic.NICPorts[i], nic.NICPorts[j] = nic.NICPorts[j], nic.NICPorts[i] })
+}
+
+// componentKey returns the sort key of a component - the serial number, the IDs or slot, the description
+// and the JSON encoding of the component
+func componentKey(c *common.Common, component interface{}, ids ...string) []string {
+	encoded, _ := json.Marshal(component)
+
+	key := append([]string{c.Serial}, ids...)
+
+	return append(key, c.Description, string(encoded))
+}
+
+// sortComponents sorts the n components by their keys, the keys are computed once for each component
+func sortComponents(n int, key func(i int) []string, swap func(i, j int)) {
+	if n < 2 {
+		return
+	}
+
+	sorter := &componentSorter{keys: make([][]string, n), swap: swap}
+	for i := 0; i < n; i++ {
+		sorter.keys[i] = key(i)
+	}
+
+	sort.Sort(sorter)
+}
+
+// componentSorter sorts the components by their keys, swapping the keys along with the components
+type componentSorter struct {
+	keys [][]string
+	swap func(i, j int)
+}
+
+func (s *componentSorter) Len() int {
+	return len(s.keys)
+}
+
+func (s *componentSorter) Less(i, j int) bool {
+	for idx := range s.keys[i] {
+		if s.keys[i][idx] != s.keys[j][idx] {
+			return s.keys[i][idx] < s.keys[j][idx]
+		}
+	}
+
+	return false
+}
+
+func (s *componentSorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.swap(i, j)
+}
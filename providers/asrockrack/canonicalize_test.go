@@ -0,0 +1,78 @@
+package asrockrack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CanonicalizeDevice(t *testing.T) {
+	device, err := aClient.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same inventory with the components listed in the reverse order
+	b, err := json.Marshal(device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reversed := &common.Device{}
+	if err := json.Unmarshal(b, reversed); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, j := 0, len(reversed.Memory)-1; i < j; i, j = i+1, j-1 {
+		reversed.Memory[i], reversed.Memory[j] = reversed.Memory[j], reversed.Memory[i]
+	}
+
+	for i, j := 0, len(reversed.Drives)-1; i < j; i, j = i+1, j-1 {
+		reversed.Drives[i], reversed.Drives[j] = reversed.Drives[j], reversed.Drives[i]
+	}
+
+	for i, j := 0, len(reversed.NICs)-1; i < j; i, j = i+1, j-1 {
+		reversed.NICs[i], reversed.NICs[j] = reversed.NICs[j], reversed.NICs[i]
+	}
+
+	hash := func(device *common.Device) [32]byte {
+		b, err := json.Marshal(device)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return sha256.Sum256(b)
+	}
+
+	assert.NotEqual(t, hash(device), hash(reversed))
+
+	CanonicalizeDevice(device)
+	CanonicalizeDevice(reversed)
+
+	assert.Equal(t, hash(device), hash(reversed))
+
+	// the components are ordered by the serial number
+	assert.Equal(t, "2724B52D", device.Memory[0].Serial)
+	assert.Equal(t, "2724B58A", device.Memory[1].Serial)
+}
+
+func Test_CanonicalizeDeviceEqualKeys(t *testing.T) {
+	// components without serial, ID or description are ordered by their attributes
+	device := &common.Device{
+		PSUs: []*common.PSU{
+			{Common: common.Common{Model: "PSU-B"}},
+			{Common: common.Common{Model: "PSU-A"}},
+		},
+	}
+
+	CanonicalizeDevice(device)
+
+	assert.Equal(t, "PSU-A", device.PSUs[0].Model)
+	assert.Equal(t, "PSU-B", device.PSUs[1].Model)
+
+	CanonicalizeDevice(nil)
+}
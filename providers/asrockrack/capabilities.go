@@ -0,0 +1,79 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Capabilities identifiers, as returned by Capabilities()
+const (
+	CapabilityVirtualMedia    = "virtualmedia"
+	CapabilitySOL             = "sol"
+	CapabilityScreenshot      = "screenshot"
+	CapabilityBIOSConfig      = "biosconfig"
+	CapabilityFirmwareInstall = "firmwareinstall"
+)
+
+// capabilityProbes lists the read only endpoints probed to identify the supported capabilities
+var capabilityProbes = []struct {
+	capability string
+	endpoint   string
+}{
+	{CapabilityVirtualMedia, "api/settings/media/remote/images"},
+	{CapabilitySOL, "api/settings/sol"},
+	{CapabilityScreenshot, "api/kvm/screenshot"},
+	{CapabilityBIOSConfig, biosAttributesEndpoint},
+	{CapabilityFirmwareInstall, "api/maintenance/firmware/flash-progress"},
+}
+
+// Capabilities returns the capabilities supported by the BMC firmware.
+//
+// Each capability is identified by probing a read only endpoint, a capability is not supported
+// when the BMC responds with a 404, 405 or 501 status code. The capabilities are cached
+// for the lifetime of the session and discarded on Open() and Close().
+func (a *ASRockRack) Capabilities(ctx context.Context) ([]string, error) {
+	a.capabilitiesMu.Lock()
+	defer a.capabilitiesMu.Unlock()
+
+	if a.capabilities != nil {
+		return append([]string{}, a.capabilities...), nil
+	}
+
+	capabilities := []string{}
+	for _, probe := range capabilityProbes {
+		_, statusCode, err := a.queryHTTPS(ctx, probe.endpoint, "GET", nil, nil, 0)
+		if err != nil {
+			return nil, errors.Wrap(err, "probing "+probe.capability)
+		}
+
+		if statusCode == http.StatusOK {
+			capabilities = append(capabilities, probe.capability)
+			continue
+		}
+
+		err = statusCodeError(statusCode)
+		switch {
+		case errors.Is(err, ErrUnsupported):
+			continue
+		case errors.Is(err, ErrUnauthorized):
+			return nil, errors.Wrap(err, "probing "+probe.capability)
+		}
+
+		// the endpoint is available, the request was not accepted as is
+		capabilities = append(capabilities, probe.capability)
+	}
+
+	a.capabilities = capabilities
+
+	return append([]string{}, capabilities...), nil
+}
+
+// resetCapabilities discards the cached capabilities
+func (a *ASRockRack) resetCapabilities() {
+	a.capabilitiesMu.Lock()
+	defer a.capabilitiesMu.Unlock()
+
+	a.capabilities = nil
+}
@@ -0,0 +1,91 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Capabilities(t *testing.T) {
+	var probes int32
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/session", session)
+	handler.HandleFunc("/api/settings/media/remote/images", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&probes, 1)
+		_, _ = w.Write([]byte(`[]`))
+	})
+	handler.HandleFunc("/api/settings/sol", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&probes, 1)
+		_, _ = w.Write([]byte(`{ "sol_enable": 1 }`))
+	})
+	handler.HandleFunc("/api/kvm/screenshot", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&probes, 1)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
+	handler.HandleFunc("/api/maintenance/firmware/flash-progress", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&probes, 1)
+		// not in flash mode
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	expected := []string{CapabilityVirtualMedia, CapabilitySOL, CapabilityFirmwareInstall}
+
+	capabilities, err := client.Capabilities(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, expected, capabilities)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&probes))
+
+	// cached for the session
+	capabilities, err = client.Capabilities(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, expected, capabilities)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&probes))
+
+	// a new session probes again
+	if err := client.Open(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Capabilities(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int32(8), atomic.LoadInt32(&probes))
+}
+
+func Test_CapabilitiesUnauthorized(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err := client.Capabilities(context.TODO())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.Nil(t, client.capabilities)
+}
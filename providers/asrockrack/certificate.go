@@ -0,0 +1,236 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BMCCertificate is the BMC HTTPS certificate
+type BMCCertificate struct {
+	Subject      string
+	Issuer       string
+	SerialNumber string
+	DNSNames     []string
+	NotBefore    time.Time
+	NotAfter     time.Time
+	KeyAlgorithm string
+	KeySize      int // the key size in bits
+	SelfSigned   bool
+
+	// Certificate is the parsed certificate
+	Certificate *x509.Certificate
+}
+
+// GetBMCCertificate returns the certificate presented by the BMC HTTPS server.
+//
+// The certificate is read from the TLS handshake without being verified, self-signed and expired certificates
+// are returned for auditing, ErrBMCCertificateRead is returned when the handshake fails or the BMC is reached over HTTP.
+func (a *ASRockRack) GetBMCCertificate(ctx context.Context) (*BMCCertificate, error) {
+	if a.configErr != nil {
+		return nil, a.configErr
+	}
+
+	u, err := url.Parse(a.baseURL)
+	if err != nil {
+		return nil, errors.Wrap(ErrBMCCertificateRead, err.Error())
+	}
+
+	if u.Scheme != "https" {
+		return nil, errors.Wrap(ErrBMCCertificateRead, "BMC not reached over HTTPS: "+a.baseURL)
+	}
+
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	// the TLS configuration of the provider HTTP client, the certificate is read without verification
+	config := &tls.Config{}
+	if transport, ok := a.httpClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		config = transport.TLSClientConfig.Clone()
+	}
+
+	// nolint:gosec // the certificate is inspected, not trusted
+	config.InsecureSkipVerify = true
+
+	// a resumed session reports the certificate of the session resumed, not the certificate now presented
+	config.ClientSessionCache = nil
+	if config.ServerName == "" {
+		config.ServerName = u.Hostname()
+	}
+
+	dialer := &tls.Dialer{Config: config}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, errors.Wrap(ErrBMCCertificateRead, err.Error())
+	}
+
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, errors.Wrap(ErrBMCCertificateRead, "no certificate presented by the BMC")
+	}
+
+	return bmcCertificate(state.PeerCertificates[0]), nil
+}
+
+// bmcCertificate returns the certificate attributes
+func bmcCertificate(cert *x509.Certificate) *BMCCertificate {
+	c := &BMCCertificate{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		DNSNames:     cert.DNSNames,
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		KeyAlgorithm: cert.PublicKeyAlgorithm.String(),
+		KeySize:      publicKeySize(cert.PublicKey),
+		Certificate:  cert,
+	}
+
+	// a self-signed certificate is signed by its own key, BMC certificates are generally not marked as a CA
+	// so the signature is checked without the CA constraints CheckSignatureFrom enforces.
+	c.SelfSigned = cert.Subject.String() == cert.Issuer.String() &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+
+	return c
+}
+
+// publicKeySize returns the public key size in bits, zero for an unsupported key type
+func publicKeySize(key interface{}) int {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return 256
+	}
+
+	return 0
+}
+
+// certificateUploadError is the error payload returned by the certificate endpoint when the upload is rejected
+type certificateUploadError struct {
+	Code    int    `json:"code"`
+	Error   string `json:"error"`
+	Message string `json:"msg"`
+}
+
+// UploadBMCCertificate installs the PEM encoded certificate and private key as the BMC HTTPS certificate,
+// returning true when the BMC is to be reset with BmcReset for the certificate to take effect.
+//
+// The certificate and key are validated to be a matching pair and the certificate to be within its validity period
+// before the upload, ErrBMCCertificateInvalid is returned otherwise. When the BMC rejects the upload, the reason
+// reported by the BMC is included in the ErrBMCCertificateUpload error.
+//
+// The BMC web server applies the certificate by restarting, once uploaded the certificate presented
+// by the BMC is compared with the uploaded certificate, a reset is required when it is not presented.
+func (a *ASRockRack) UploadBMCCertificate(ctx context.Context, certPEM, keyPEM []byte) (resetRequired bool, err error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, errors.Wrap(ErrBMCCertificateInvalid, err.Error())
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false, errors.Wrap(ErrBMCCertificateInvalid, err.Error())
+	}
+
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return false, errors.Wrap(
+			ErrBMCCertificateInvalid,
+			fmt.Sprintf("certificate not valid at the current time, valid from %s until %s", cert.NotBefore, cert.NotAfter),
+		)
+	}
+
+	payload := &bytes.Buffer{}
+	form := multipart.NewWriter(payload)
+
+	for _, file := range []struct {
+		field, name string
+		content     []byte
+	}{
+		{"new_certificate", "certificate.pem", certPEM},
+		{"new_private_key", "private_key.pem", keyPEM},
+	} {
+		part, err := form.CreateFormFile(file.field, file.name)
+		if err != nil {
+			return false, errors.Wrap(ErrBMCCertificateUpload, err.Error())
+		}
+
+		if _, err := part.Write(file.content); err != nil {
+			return false, errors.Wrap(ErrBMCCertificateUpload, err.Error())
+		}
+	}
+
+	if err := form.Close(); err != nil {
+		return false, errors.Wrap(ErrBMCCertificateUpload, err.Error())
+	}
+
+	headers := map[string]string{"Content-Type": form.FormDataContentType()}
+
+	resp, statusCode, err := a.queryHTTPS(ctx, "api/settings/ssl/certificate", "POST", payload, headers, int64(payload.Len()))
+	if err != nil {
+		return false, errors.Wrap(ErrBMCCertificateUpload, err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		err := wrapStatusCodeError(ErrBMCCertificateUpload, statusCode)
+		if reason := certificateRejection(resp); reason != "" {
+			return false, errors.Wrap(err, "certificate rejected by the BMC: "+reason)
+		}
+
+		return false, err
+	}
+
+	if a.dryRun {
+		return false, nil
+	}
+
+	// connections established before the upload are presented the previous certificate
+	a.httpClient.CloseIdleConnections()
+
+	presented, err := a.GetBMCCertificate(ctx)
+	if err != nil {
+		a.log.V(1).Info("unable to read the BMC certificate after the upload, a BMC reset is required", "ip", a.ip, "error", err.Error())
+		return true, nil
+	}
+
+	return !bytes.Equal(presented.Certificate.Raw, cert.Raw), nil
+}
+
+// certificateRejection returns the reason reported by the BMC for rejecting the certificate upload
+func certificateRejection(resp []byte) string {
+	rejection := &certificateUploadError{}
+	if err := json.Unmarshal(resp, rejection); err == nil {
+		for _, reason := range []string{rejection.Error, rejection.Message} {
+			if reason != "" {
+				return reason
+			}
+		}
+
+		if rejection.Code != 0 {
+			return fmt.Sprintf("error code %d", rejection.Code)
+		}
+	}
+
+	return strings.TrimSpace(string(resp))
+}
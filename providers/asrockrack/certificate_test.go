@@ -0,0 +1,268 @@
+package asrockrack
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// selfSignedCertificate returns a self-signed ECDSA P-256 certificate for the common name
+func selfSignedCertificate(t *testing.T, commonName string, notAfter time.Time) tls.Certificate {
+	t.Helper()
+
+	certPEM, keyPEM := selfSignedCertificatePEM(t, commonName, notAfter)
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+// selfSignedCertificatePEM returns the PEM encoded self-signed ECDSA P-256 certificate and key for the common name
+func selfSignedCertificatePEM(t *testing.T, commonName string, notAfter time.Time) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"ASRockRack"}},
+		DNSNames:     []string{commonName},
+		NotBefore:    notAfter.AddDate(-1, 0, 0),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func Test_GetBMCCertificate(t *testing.T) {
+	notAfter := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{selfSignedCertificate(t, "bmc.example.com", notAfter)}}
+	// the connection is closed once the handshake completes
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	// the certificate is read without the certificate verification disabled
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard())
+
+	cert, err := client.GetBMCCertificate(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "CN=bmc.example.com,O=ASRockRack", cert.Subject)
+	assert.Equal(t, cert.Subject, cert.Issuer)
+	assert.Equal(t, "1234", cert.SerialNumber)
+	assert.Equal(t, []string{"bmc.example.com"}, cert.DNSNames)
+	assert.Equal(t, notAfter, cert.NotAfter)
+	assert.Equal(t, "ECDSA", cert.KeyAlgorithm)
+	assert.Equal(t, 256, cert.KeySize)
+	assert.True(t, cert.SelfSigned)
+	assert.NotNil(t, cert.Certificate)
+}
+
+func Test_GetBMCCertificateDefault(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	cert, err := client.GetBMCCertificate(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := server.Certificate()
+	assert.Equal(t, expected.Subject.String(), cert.Subject)
+	assert.Equal(t, expected.NotAfter, cert.NotAfter)
+	assert.Equal(t, "RSA", cert.KeyAlgorithm)
+	assert.Equal(t, expected.PublicKey.(interface{ Size() int }).Size()*8, cert.KeySize)
+}
+
+func Test_GetBMCCertificateHTTP(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	client := NewWithOptions("", "foo", "bar", logr.Discard(), WithBaseURL(server.URL))
+
+	_, err := client.GetBMCCertificate(context.TODO())
+	assert.ErrorIs(t, err, ErrBMCCertificateRead)
+}
+
+// mockCertificateBMC returns a BMC mock presenting the initial certificate, the uploaded certificate is presented
+// once uploaded when applied is set, the upload is rejected with the given response when set.
+func mockCertificateBMC(t *testing.T, initial tls.Certificate, applied bool, rejection string) (server *httptest.Server, uploads func() int) {
+	var mu sync.Mutex
+	var uploaded int
+
+	presented := initial
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/settings/ssl/certificate", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		uploaded++
+
+		if rejection != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(rejection))
+			return
+		}
+
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		read := func(files []*multipart.FileHeader) []byte {
+			if len(files) != 1 {
+				return nil
+			}
+
+			f, _ := files[0].Open()
+			defer f.Close()
+
+			b, _ := io.ReadAll(f)
+			return b
+		}
+
+		cert, err := tls.X509KeyPair(read(r.MultipartForm.File["new_certificate"]), read(r.MultipartForm.File["new_private_key"]))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if applied {
+			presented = cert
+		}
+
+		_, _ = w.Write([]byte(`{ "cc": 0 }`))
+	})
+
+	server = httptest.NewUnstartedServer(handler)
+	// GetCertificate is not called for clients connecting by IP address, without a server name
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return &tls.Config{Certificates: []tls.Certificate{presented}}, nil
+		},
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+
+	uploads = func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return uploaded
+	}
+
+	return server, uploads
+}
+
+func Test_UploadBMCCertificate(t *testing.T) {
+	notAfter := time.Now().AddDate(1, 0, 0)
+	initial := selfSignedCertificate(t, "initial.example.com", notAfter)
+
+	certPEM, keyPEM := selfSignedCertificatePEM(t, "bmc.example.com", notAfter)
+	_, otherKeyPEM := selfSignedCertificatePEM(t, "other.example.com", notAfter)
+	expiredPEM, expiredKeyPEM := selfSignedCertificatePEM(t, "bmc.example.com", time.Now().AddDate(0, 0, -1))
+
+	testCases := []struct {
+		name          string
+		certPEM       []byte
+		keyPEM        []byte
+		applied       bool
+		rejection     string
+		resetRequired bool
+		uploads       int
+		err           error
+		errMessage    string
+	}{
+		{"applied", certPEM, keyPEM, true, "", false, 1, nil, ""},
+		{"reset required", certPEM, keyPEM, false, "", true, 1, nil, ""},
+		{"mismatched key", certPEM, otherKeyPEM, true, "", false, 0, ErrBMCCertificateInvalid, "private key does not match public key"},
+		{"expired", expiredPEM, expiredKeyPEM, true, "", false, 0, ErrBMCCertificateInvalid, "not valid at the current time"},
+		{"not PEM", []byte("foo"), keyPEM, true, "", false, 0, ErrBMCCertificateInvalid, ""},
+		{
+			"rejected",
+			certPEM,
+			keyPEM,
+			true,
+			`{ "code": 18214, "error": "certificate key usage not supported" }`,
+			false,
+			1,
+			ErrBMCCertificateUpload,
+			"certificate rejected by the BMC: certificate key usage not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, uploads := mockCertificateBMC(t, initial, tc.applied, tc.rejection)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			resetRequired, err := client.UploadBMCCertificate(context.TODO(), tc.certPEM, tc.keyPEM)
+			assert.Equal(t, tc.uploads, uploads())
+
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Contains(t, err.Error(), tc.errMessage)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.resetRequired, resetRequired)
+		})
+	}
+}
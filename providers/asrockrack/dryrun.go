@@ -0,0 +1,36 @@
+package asrockrack
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// dryRunReadOnlyEndpoints are the endpoints queried with a write method which do not change the BMC or host state,
+// the session endpoint is included as the session is required for the read requests.
+var dryRunReadOnlyEndpoints = map[string]bool{
+	"api/session":        true,
+	"api/kvm/screenshot": true,
+}
+
+// dryRunResponse is the response body returned for requests not sent in dry run mode
+var dryRunResponse = []byte(`{}`)
+
+// dryRunSkip returns true if the request is not to be sent as it changes the BMC or host state in dry run mode,
+// the request is logged instead. The payload is not logged as it may include credentials.
+func (a *ASRockRack) dryRunSkip(endpoint, method string, payload io.Reader) bool {
+	if !a.dryRun || method == http.MethodGet || method == http.MethodHead || dryRunReadOnlyEndpoints[endpoint] {
+		return false
+	}
+
+	log := a.log.V(1).WithValues("method", method, "endpoint", endpoint)
+
+	// firmware images are streamed through a pipe of unknown length
+	if reader, ok := payload.(*bytes.Reader); ok {
+		log = log.WithValues("payload_bytes", reader.Len())
+	}
+
+	log.Info("dry run, request not sent")
+
+	return true
+}
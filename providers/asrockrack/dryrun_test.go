@@ -0,0 +1,135 @@
+package asrockrack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/bmclib/v2/constants"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDryRunBMC returns a BMC mock recording the write requests received, excluding the session requests
+func mockDryRunBMC() (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	writes := []string{}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/chassis-status":
+			_, _ = w.Write([]byte(`{ "power_status": 1, "led_status": 0 }`))
+		case r.URL.Path == "/api/session":
+			session(w, r)
+		case r.Method != http.MethodGet:
+			mu.Lock()
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+		}
+	})
+
+	received := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return writes
+	}
+
+	return httptest.NewTLSServer(handler), received
+}
+
+func Test_DryRun(t *testing.T) {
+	testCases := []struct {
+		name string
+		run  func(client *ASRockRack) error
+	}{
+		{
+			"boot device",
+			func(client *ASRockRack) error {
+				ok, err := client.BootDeviceSet(context.TODO(), "pxe", true, true)
+				assert.True(t, ok)
+				return err
+			},
+		},
+		{
+			"power off",
+			func(client *ASRockRack) error {
+				ok, err := client.PowerSet(context.TODO(), "off")
+				assert.True(t, ok)
+				return err
+			},
+		},
+		{
+			"soft power off",
+			func(client *ASRockRack) error {
+				ok, err := client.PowerSet(context.TODO(), "soft")
+				assert.True(t, ok)
+				return err
+			},
+		},
+		{
+			"power cycle",
+			func(client *ASRockRack) error {
+				return client.PowerCycle(context.TODO(), time.Millisecond)
+			},
+		},
+		{
+			"firmware install",
+			func(client *ASRockRack) error {
+				fh, err := os.Open("/dev/null")
+				if err != nil {
+					return err
+				}
+				defer fh.Close()
+
+				taskID, err := client.FirmwareInstall(context.TODO(), common.SlugBMC, constants.FirmwareApplyImmediate, false, fh)
+				if err != nil {
+					return err
+				}
+
+				status, err := client.FirmwareInstallStatus(context.TODO(), "", common.SlugBMC, taskID)
+				assert.Equal(t, constants.FirmwareInstallComplete, status)
+				return err
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, received := mockDryRunBMC()
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithDryRun(), WithPowerStateTimeout(time.Second))
+
+			assert.Nil(t, client.Open(context.TODO()))
+			assert.Nil(t, tc.run(client))
+			assert.Empty(t, received(), fmt.Sprintf("write requests sent in dry run mode: %v", received()))
+		})
+	}
+}
+
+func Test_DryRunDisabled(t *testing.T) {
+	server, received := mockDryRunBMC()
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	ok, err := client.BootDeviceSet(context.TODO(), "pxe", true, true)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.PowerSet(context.TODO(), "on")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, []string{"PUT /api/settings/boot-options", "POST /api/actions/power"}, received())
+}
@@ -0,0 +1,325 @@
+package asrockrack
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidBMCAddress is returned when the BMC address, port or base URL is not valid
+	ErrInvalidBMCAddress = errors.New("invalid BMC address")
+
+	// ErrBudgetExhausted is returned when no time remains in the Budget to start an operation
+	ErrBudgetExhausted = errors.New("time budget exhausted")
+
+	// ErrInventoryTimeout is returned when the context deadline was exceeded during inventory collection,
+	// the device returned along with this error includes the inventory collected until the deadline.
+	ErrInventoryTimeout = errors.New("inventory collection deadline exceeded")
+
+	// ErrRequestTimeout is returned when a BMC request did not complete within the request timeout
+	ErrRequestTimeout = errors.New("BMC request timeout exceeded")
+
+	// ErrNetworkConfigRead is returned when the BMC network configuration could not be read
+	ErrNetworkConfigRead = errors.New("error reading BMC network configuration")
+
+	// ErrLocatorLEDUnsupported is returned when the BMC firmware does not expose the chassis identify control
+	ErrLocatorLEDUnsupported = errors.New("chassis identify LED control is not supported by the BMC firmware")
+
+	// ErrLocatorLED is returned when the chassis identify LED state could not be read or set
+	ErrLocatorLED = errors.New("error controlling the chassis identify LED")
+
+	// ErrTimeConfigRead is returned when the BMC date and time configuration could not be read
+	ErrTimeConfigRead = errors.New("error reading the BMC time configuration")
+
+	// ErrTimeConfigWrite is returned when the BMC date and time configuration could not be applied
+	ErrTimeConfigWrite = errors.New("error setting the BMC time configuration")
+
+	// ErrNTPServerInvalid is returned when an NTP server is not an IP address or host name
+	ErrNTPServerInvalid = errors.New("invalid NTP server")
+
+	// ErrTimezoneInvalid is returned when the timezone is not an IANA time zone name
+	ErrTimezoneInvalid = errors.New("invalid timezone")
+
+	// ErrSNMPUnsupported is returned when the BMC firmware does not expose the SNMP trap configuration
+	ErrSNMPUnsupported = errors.New("SNMP trap configuration is not supported by the BMC firmware")
+
+	// ErrSNMPConfig is returned when the SNMP trap configuration could not be read or applied
+	ErrSNMPConfig = errors.New("error configuring SNMP traps")
+
+	// ErrSNMPConfigInvalid is returned when the SNMP community or a trap destination is not valid
+	ErrSNMPConfigInvalid = errors.New("invalid SNMP trap configuration")
+
+	// ErrSyslogUnsupported is returned when the BMC firmware does not expose the remote syslog configuration
+	ErrSyslogUnsupported = errors.New("remote syslog configuration is not supported by the BMC firmware")
+
+	// ErrSyslogConfig is returned when the remote syslog configuration could not be read or applied
+	ErrSyslogConfig = errors.New("error configuring remote syslog")
+
+	// ErrSyslogServerInvalid is returned when a remote syslog server host, port or protocol is not valid
+	ErrSyslogServerInvalid = errors.New("invalid syslog server")
+
+	// ErrSOLDisabled is returned when Serial-over-LAN is disabled in the BMC settings
+	ErrSOLDisabled = errors.New("serial over LAN is disabled")
+
+	// ErrSOLSession is returned when the Serial-over-LAN session could not be established
+	ErrSOLSession = errors.New("error establishing serial over LAN session")
+
+	// ErrVirtualMedia is returned when a virtual media operation fails
+	ErrVirtualMedia = errors.New("virtual media error")
+
+	// ErrVirtualMediaSlotOccupied is returned when an image is already mounted for the virtual media type
+	ErrVirtualMediaSlotOccupied = errors.New("virtual media slot occupied")
+
+	// ErrVirtualMediaUnreachable is returned when the BMC was unable to mount the image from the media URL
+	ErrVirtualMediaUnreachable = errors.New("virtual media image unreachable from the BMC")
+
+	// ErrScreenshotNoSignal is returned when the host has no video output to capture
+	ErrScreenshotNoSignal = errors.New("no video signal to capture")
+
+	// ErrUserNamePolicy is returned when the username does not meet the BMC username policy
+	ErrUserNamePolicy = errors.New("username does not meet the BMC policy")
+
+	// ErrPasswordPolicy is returned when the password does not meet the BMC password policy
+	ErrPasswordPolicy = errors.New("password does not meet the BMC policy")
+
+	// ErrUserAccountDelete is returned when the user account could not be removed
+	ErrUserAccountDelete = errors.New("user account could not be removed")
+
+	// ErrSoftPowerOffTimeout is returned when the host did not power off within the soft power off timeout
+	ErrSoftPowerOffTimeout = errors.New("host did not power off within the soft power off timeout")
+
+	// ErrPowerStateTimeout is returned when the host did not reach the requested power state within the power state timeout
+	ErrPowerStateTimeout = errors.New("host did not reach the power state within the power state timeout")
+
+	// ErrFirmwareVerification is returned when the BMC rejects the uploaded firmware image
+	ErrFirmwareVerification = errors.New("uploaded firmware image failed verification")
+
+	// ErrFirmwareChecksumMismatch is returned when the uploaded firmware image size or checksum does not match
+	// the image streamed from the reader, the expected checksum or the image received by the BMC
+	ErrFirmwareChecksumMismatch = errors.New("uploaded firmware image checksum mismatch")
+
+	// ErrFirmwareAlreadyInstalled is returned when the firmware install is skipped as the firmware image version is already installed
+	ErrFirmwareAlreadyInstalled = errors.New("firmware version already installed")
+
+	// ErrDowngradeBlocked is returned when the firmware image version is older than the installed version
+	// and downgrades are not allowed with the WithAllowDowngrade option
+	ErrDowngradeBlocked = errors.New("firmware downgrade blocked")
+
+	// ErrSELRead is returned when the System Event Log could not be read
+	ErrSELRead = errors.New("error reading the system event log")
+
+	// ErrSELClear is returned when the System Event Log could not be cleared
+	ErrSELClear = errors.New("error clearing the system event log")
+
+	// ErrSELClearNotPermitted is returned when the user privilege does not permit clearing the System Event Log
+	ErrSELClearNotPermitted = errors.New("clearing the system event log is not permitted for the user")
+
+	// ErrInsufficientPrivilege is returned when the session user privilege level does not permit the operation
+	ErrInsufficientPrivilege = errors.New("insufficient user privilege for the operation")
+
+	// ErrBMCCertificateRead is returned when the BMC HTTPS certificate could not be read
+	ErrBMCCertificateRead = errors.New("error reading the BMC certificate")
+
+	// ErrBMCCertificateInvalid is returned when the certificate and private key to upload are not a valid pair
+	ErrBMCCertificateInvalid = errors.New("invalid BMC certificate")
+
+	// ErrBMCCertificateUpload is returned when the BMC certificate upload failed
+	ErrBMCCertificateUpload = errors.New("error uploading the BMC certificate")
+
+	// ErrBMCNotReady is returned when the BMC did not become ready before the context was done
+	ErrBMCNotReady = errors.New("BMC not ready")
+
+	// ErrBMCResetTimeout is returned when the BMC did not become reachable within the BMC reset wait duration
+	ErrBMCResetTimeout = errors.New("BMC not reachable within the reset wait duration")
+
+	// ErrBMCUptimeUnsupported is returned when the BMC firmware does not report the BMC uptime
+	ErrBMCUptimeUnsupported = errors.New("BMC uptime is not supported by the BMC firmware")
+
+	// ErrBMCUptimeRead is returned when the BMC uptime could not be read
+	ErrBMCUptimeRead = errors.New("error reading the BMC uptime")
+
+	// ErrBootOrderRead is returned when the boot order could not be read
+	ErrBootOrderRead = errors.New("error reading the boot order")
+
+	// ErrBIOSConfigUnsupported is returned when the BMC firmware does not expose the BIOS settings
+	ErrBIOSConfigUnsupported = errors.New("BIOS configuration is not supported by the BMC firmware")
+
+	// ErrBIOSConfigRead is returned when the BIOS settings could not be read
+	ErrBIOSConfigRead = errors.New("error reading the BIOS configuration")
+
+	// ErrBIOSAttributeInvalid is returned when a BIOS setting is not valid according to the BIOS attribute registry
+	ErrBIOSAttributeInvalid = errors.New("invalid BIOS attribute")
+
+	// ErrBIOSConfigWrite is returned when the BIOS settings could not be applied
+	ErrBIOSConfigWrite = errors.New("error applying the BIOS configuration")
+
+	// ErrBIOSAttributeUnsupported is returned when imported BIOS settings are not listed in the BIOS attribute registry
+	ErrBIOSAttributeUnsupported = errors.New("BIOS attributes not supported by the BIOS")
+
+	// ErrBIOSConfigImport is returned when the BIOS configuration to import is not a BIOS configuration export
+	ErrBIOSConfigImport = errors.New("invalid BIOS configuration export")
+
+	// ErrSecureBootUnsupported is returned when the BIOS settings do not include the UEFI Secure Boot control
+	ErrSecureBootUnsupported = errors.New("secure boot control is not supported by the BIOS")
+
+	// ErrBIOSResetNotConfirmed is returned when the BIOS settings reset to defaults is requested without confirmation
+	ErrBIOSResetNotConfirmed = errors.New("BIOS configuration reset to defaults not confirmed")
+
+	// ErrPowerConsumptionUnsupported is returned when the BMC firmware exposes no power sensors
+	ErrPowerConsumptionUnsupported = errors.New("power consumption reading is not supported by the BMC firmware")
+
+	// ErrPowerConsumptionUnavailable is returned when none of the BMC power sensors has a reading available
+	ErrPowerConsumptionUnavailable = errors.New("power consumption reading not available")
+
+	// ErrPowerCapUnsupported is returned when the BMC firmware does not expose the power limit settings
+	ErrPowerCapUnsupported = errors.New("power capping is not supported by the BMC firmware")
+
+	// ErrPowerCapRead is returned when the power limit settings could not be read
+	ErrPowerCapRead = errors.New("error reading the power cap")
+
+	// ErrPowerCapWrite is returned when the power limit settings could not be applied
+	ErrPowerCapWrite = errors.New("error setting the power cap")
+
+	// ErrPowerCapOutOfRange is returned when the power cap is not within the range supported by the BMC
+	ErrPowerCapOutOfRange = errors.New("power cap out of range")
+
+	// ErrUnauthorized is returned when the BMC responds with a 401 or 403 status code
+	ErrUnauthorized = errors.New("not authorized by the BMC")
+
+	// ErrUnsupported is returned when the BMC responds with a 404, 405 or 501 status code - the endpoint is
+	// not supported by the BMC firmware
+	ErrUnsupported = errors.New("endpoint not supported by the BMC")
+
+	// ErrBMCBusy is returned when the BMC responds with a 429 or 503 status code, the request may be retried later
+	ErrBMCBusy = errors.New("BMC busy")
+)
+
+// statusError is returned for unexpected HTTP response status codes,
+// it matches bmclibErrs.ErrNon200Response, the status code error kind and the wrapped error with errors.Is.
+type statusError struct {
+	statusCode int
+	kind       error
+	err        error
+}
+
+func (e *statusError) Error() string {
+	msg := fmt.Sprintf("non 200 response: %d", e.statusCode)
+	if e.kind != nil {
+		msg += ": " + e.kind.Error()
+	}
+
+	if e.err != nil {
+		msg += ": " + e.err.Error()
+	}
+
+	return msg
+}
+
+func (e *statusError) Is(target error) bool {
+	return target == bmclibErrs.ErrNon200Response || (e.kind != nil && target == e.kind)
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// statusCodeError returns the error for an unexpected HTTP response status code
+func statusCodeError(statusCode int) error {
+	return wrapStatusCodeError(nil, statusCode)
+}
+
+// wrapStatusCodeError returns the error for an unexpected HTTP response status code, wrapping the given error
+func wrapStatusCodeError(err error, statusCode int) error {
+	e := &statusError{statusCode: statusCode, err: err}
+
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		e.kind = ErrUnauthorized
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		e.kind = ErrUnsupported
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		e.kind = ErrBMCBusy
+	}
+
+	return e
+}
+
+// causeError is an error matching the error kind with errors.Is, wrapping the cause
+type causeError struct {
+	kind error
+	err  error
+}
+
+// wrapCause returns the error kind wrapping the cause, formatted as errors.Wrap(kind, err.Error()) -
+// "<cause>: <kind>", the cause remaining in the error chain for errors.Is and errors.As.
+func wrapCause(kind, err error) error {
+	return &causeError{kind: kind, err: err}
+}
+
+func (e *causeError) Error() string {
+	return e.err.Error() + ": " + e.kind.Error()
+}
+
+func (e *causeError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *causeError) Unwrap() error {
+	return e.err
+}
+
+// EndpointError is returned when a BMC request failed or the BMC response could not be used,
+// it identifies the operation and the request URL and wraps the error - matched with errors.As.
+//
+//	var endpointErr *asrockrack.EndpointError
+//	if errors.As(err, &endpointErr) {
+//		log.Printf("%s failed: %s", endpointErr.Op, endpointErr.URL)
+//	}
+type EndpointError struct {
+	// Op is the operation - "firmware info", "sensors"
+	Op string
+	// URL is the request URL, without the user credentials
+	URL string
+	// Err is the request, response status or response decoding error
+	Err error
+}
+
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Op, e.URL, e.Err)
+}
+
+func (e *EndpointError) Unwrap() error {
+	return e.Err
+}
+
+// endpointError returns the error wrapped in an EndpointError for the operation and endpoint,
+// nil when the error is nil and the error as is when it already identifies the endpoint.
+func (a *ASRockRack) endpointError(op, endpoint string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var endpointErr *EndpointError
+	if errors.As(err, &endpointErr) {
+		return err
+	}
+
+	return &EndpointError{Op: op, URL: redactURL(a.baseURL + strings.TrimPrefix(endpoint, "/")), Err: err}
+}
+
+// redactURL returns the URL without the user credentials, the URL as is when it is not valid
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+
+	u.User = nil
+
+	return u.String()
+}
@@ -0,0 +1,48 @@
+package asrockrack
+
+import (
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/bmc"
+	"github.com/bmc-toolbox/bmclib/v2/providers"
+	"github.com/go-logr/logr"
+	"github.com/jacobweinstock/registrar"
+	"github.com/stretchr/testify/assert"
+)
+
+// featureInterfaces maps each bmclib feature to the interface implemented for the feature
+var featureInterfaces = map[registrar.Feature]func(interface{}) bool{
+	providers.FeaturePowerState:            func(p interface{}) bool { _, ok := p.(bmc.PowerStateGetter); return ok },
+	providers.FeaturePowerSet:              func(p interface{}) bool { _, ok := p.(bmc.PowerSetter); return ok },
+	providers.FeatureUserCreate:            func(p interface{}) bool { _, ok := p.(bmc.UserCreator); return ok },
+	providers.FeatureUserDelete:            func(p interface{}) bool { _, ok := p.(bmc.UserDeleter); return ok },
+	providers.FeatureUserUpdate:            func(p interface{}) bool { _, ok := p.(bmc.UserUpdater); return ok },
+	providers.FeatureUserRead:              func(p interface{}) bool { _, ok := p.(bmc.UserReader); return ok },
+	providers.FeatureBmcReset:              func(p interface{}) bool { _, ok := p.(bmc.BMCResetter); return ok },
+	providers.FeatureBootDeviceSet:         func(p interface{}) bool { _, ok := p.(bmc.BootDeviceSetter); return ok },
+	providers.FeatureVirtualMedia:          func(p interface{}) bool { _, ok := p.(bmc.VirtualMediaSetter); return ok },
+	providers.FeatureFirmwareInstall:       func(p interface{}) bool { _, ok := p.(bmc.FirmwareInstaller); return ok },
+	providers.FeatureFirmwareInstallStatus: func(p interface{}) bool { _, ok := p.(bmc.FirmwareInstallVerifier); return ok },
+	providers.FeatureInventoryRead:         func(p interface{}) bool { _, ok := p.(bmc.InventoryGetter); return ok },
+	providers.FeaturePostCodeRead:          func(p interface{}) bool { _, ok := p.(bmc.PostCodeGetter); return ok },
+	providers.FeatureScreenshot:            func(p interface{}) bool { _, ok := p.(bmc.ScreenshotGetter); return ok },
+}
+
+func Test_Features(t *testing.T) {
+	client := NewWithOptions("127.0.0.1", "foo", "bar", logr.Discard())
+
+	registry := registrar.NewRegistry()
+	registry.Register(ProviderName, ProviderProtocol, Features, nil, client)
+
+	// each feature registered is mapped to an interface
+	for _, feature := range Features {
+		_, exists := featureInterfaces[feature]
+		assert.True(t, exists, "feature without an interface mapping: %s", feature)
+	}
+
+	// a feature is registered for each interface implemented
+	for feature, implements := range featureInterfaces {
+		implemented := implements(client)
+		assert.Equal(t, implemented, len(registry.Supports(feature)) == 1, "feature %s, interface implemented: %t", feature, implemented)
+	}
+}
@@ -2,9 +2,12 @@ package asrockrack
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -21,86 +24,233 @@ const (
 	versionStrEmpty    = 2
 )
 
-// FirmwareInstall uploads and initiates firmware update for the component
+// FirmwareInstall uploads and initiates firmware update for the component, returning a task ID
+// which can be passed to FirmwareInstallStatus to track the install.
+//
+// The firmware image is streamed from the reader, upload failures are returned wrapped in ErrFirmwareUpload,
+// a firmware image rejected by the BMC is returned wrapped in ErrFirmwareVerification.
+//
+// Unless forceInstall is set, a BMC firmware image of the version already installed is not installed
+// and ErrFirmwareAlreadyInstalled is returned, the task status is reported as complete. The BMC does not
+// report the BIOS image version before the flash, BIOS firmware images are installed regardless of the version.
+//
+// A BMC firmware image older than the installed version is not installed and ErrDowngradeBlocked is returned,
+// unless downgrades are allowed with the WithAllowDowngrade option.
 func (a *ASRockRack) FirmwareInstall(ctx context.Context, component, applyAt string, forceInstall bool, reader io.Reader) (jobID string, err error) {
+	return a.FirmwareInstallWithChecksum(ctx, component, applyAt, forceInstall, reader, "")
+}
+
+// FirmwareInstallWithChecksum uploads and initiates firmware update for the component like FirmwareInstall,
+// verifying the uploaded image matches the expected hex encoded SHA-256 checksum before the install is applied.
+//
+// The SHA-256 checksum of the image is computed as it is streamed to the BMC, the install is aborted with
+// ErrFirmwareChecksumMismatch when the uploaded image does not match the expected checksum, the size of the image file
+// or the image size and checksum reported by the BMC. An empty checksum skips the comparison with the expected checksum.
+//
+// ErrInsufficientPrivilege is returned before the image is uploaded when the session user is not an administrator.
+func (a *ASRockRack) FirmwareInstallWithChecksum(ctx context.Context, component, applyAt string, forceInstall bool, reader io.Reader, checksum string) (jobID string, err error) {
+	// the size is required to set the upload content length,
+	// when undetermined the firmware image is uploaded with a chunked transfer encoding.
 	var size int64
 	if file, ok := reader.(*os.File); ok {
 		finfo, err := file.Stat()
 		if err != nil {
 			a.log.V(2).Error(err, "unable to determine file size")
+		} else {
+			size = finfo.Size()
 		}
+	}
+
+	switch component {
+	case common.SlugBIOS, common.SlugBMC:
+	default:
+		return "", errors.Wrap(bmclibErrs.ErrFirmwareInstall, "component unsupported: "+component)
+	}
+
+	if err := a.requirePrivilege("firmware install", privilegeAdministrator); err != nil {
+		return "", err
+	}
 
-		size = finfo.Size()
+	expected := &firmwareUpload{size: size, checksum: strings.ToLower(strings.TrimSpace(checksum))}
+
+	jobID = a.newFirmwareTask(component)
+
+	// the firmware image is not uploaded in dry run mode, the task is reported as complete
+	if a.dryRun {
+		a.log.V(1).Info("dry run, firmware image not uploaded", "component", component, "size", size)
+		a.setFirmwareTaskState(jobID, constants.FirmwareInstallComplete)
+
+		return jobID, nil
 	}
 
 	switch component {
 	case common.SlugBIOS:
-		err = a.firmwareInstallBIOS(ctx, reader, size)
+		err = a.firmwareInstallBIOS(ctx, jobID, reader, expected)
 	case common.SlugBMC:
-		err = a.firmwareInstallBMC(ctx, reader, size)
-	default:
-		return "", errors.Wrap(bmclibErrs.ErrFirmwareInstall, "component unsupported: "+component)
+		err = a.firmwareInstallBMC(ctx, jobID, reader, expected, forceInstall)
 	}
 
 	if err != nil {
-		err = errors.Wrap(bmclibErrs.ErrFirmwareInstall, err.Error())
+		state := constants.FirmwareInstallFailed
+		if errors.Is(err, ErrFirmwareAlreadyInstalled) {
+			state = constants.FirmwareInstallComplete
+		}
+
+		a.setFirmwareTaskState(jobID, state)
+		return jobID, err
 	}
 
-	return jobID, err
+	a.setFirmwareTaskState(jobID, constants.FirmwareInstallRunning)
+
+	return jobID, nil
 }
 
 // FirmwareInstallStatus returns the status of the firmware install process, a bool value indicating if the component requires a reset
+//
+// When the taskID returned by FirmwareInstall is given, the uploading, verifying, failed and complete
+// states of the install are returned as tracked by the provider.
 func (a *ASRockRack) FirmwareInstallStatus(ctx context.Context, installVersion, component, taskID string) (status string, err error) {
 	switch component {
 	case common.SlugBIOS, common.SlugBMC:
-		return a.firmwareUpdateStatus(ctx, component, installVersion)
 	default:
 		return "", errors.Wrap(bmclibErrs.ErrFirmwareInstallStatus, "component unsupported: "+component)
 	}
+
+	switch state := a.firmwareTaskState(taskID); state {
+	case constants.FirmwareInstallUploading, constants.FirmwareInstallVerifying, constants.FirmwareInstallFailed,
+		constants.FirmwareInstallComplete:
+		return state, nil
+	}
+
+	return a.firmwareUpdateStatus(ctx, component, installVersion)
 }
 
-// firmwareInstallBMC uploads and installs firmware for the BMC component
-func (a *ASRockRack) firmwareInstallBMC(ctx context.Context, reader io.Reader, fileSize int64) error {
-	var err error
+// newFirmwareTask registers a firmware install task for the component and returns its ID
+func (a *ASRockRack) newFirmwareTask(component string) string {
+	a.firmwareTasksMu.Lock()
+	defer a.firmwareTasksMu.Unlock()
+
+	if a.firmwareTasks == nil {
+		a.firmwareTasks = map[string]string{}
+	}
 
+	taskID := fmt.Sprintf("%s-%d", strings.ToLower(component), time.Now().UnixNano())
+	a.firmwareTasks[taskID] = constants.FirmwareInstallInitializing
+
+	return taskID
+}
+
+func (a *ASRockRack) setFirmwareTaskState(taskID, state string) {
+	a.firmwareTasksMu.Lock()
+	defer a.firmwareTasksMu.Unlock()
+
+	if _, exists := a.firmwareTasks[taskID]; exists {
+		a.firmwareTasks[taskID] = state
+	}
+}
+
+// firmwareTaskState returns the tracked firmware install task state, an empty string if the task is unknown
+func (a *ASRockRack) firmwareTaskState(taskID string) string {
+	a.firmwareTasksMu.Lock()
+	defer a.firmwareTasksMu.Unlock()
+
+	return a.firmwareTasks[taskID]
+}
+
+// firmwareInstallBMC uploads and installs firmware for the BMC component
+//
+// Unless forceInstall is set, the install is skipped with ErrFirmwareAlreadyInstalled when the BMC reports
+// the uploaded image version is the version installed.
+//
+// The flash mode ends the BMC sessions and blocks logins, when the upload or the verification fails
+// or the install is skipped, the BMC is reset to leave the flash mode.
+func (a *ASRockRack) firmwareInstallBMC(ctx context.Context, taskID string, reader io.Reader, expected *firmwareUpload, forceInstall bool) (err error) {
 	// 1. set the device to flash mode - prepares the flash
 	a.log.V(2).WithValues("step", "1/4").Info("set device to flash mode, takes a minute...")
 	err = a.setFlashMode(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed in step 1/4 - set device to flash mode")
+		return errors.Wrap(bmclibErrs.ErrFirmwareInstall, "failed in step 1/4 - set device to flash mode: "+err.Error())
 	}
 
+	// the BMC is not reset once the upgrade was requested, the flash may be in progress
+	var upgrading bool
+
+	defer func() {
+		if err == nil || upgrading {
+			return
+		}
+
+		a.log.V(2).Info("reset BMC to exit flash mode")
+
+		if resetErr := a.resetBMC(ctx); resetErr != nil {
+			a.log.V(2).Error(resetErr, "unable to reset BMC to exit flash mode")
+		}
+	}()
+
 	// 2. upload firmware image file
 	a.log.V(2).WithValues("step", "2/4").Info("upload BMC firmware image")
-	err = a.uploadFirmware(ctx, "api/maintenance/firmware", reader, fileSize)
+	a.setFirmwareTaskState(taskID, constants.FirmwareInstallUploading)
+	upload, err := a.uploadFirmware(ctx, "api/maintenance/firmware", reader, expected.size)
+	if err != nil {
+		return errors.Wrap(bmclibErrs.ErrFirmwareUpload, "failed in step 2/4 - upload BMC firmware image: "+err.Error())
+	}
+
+	err = verifyFirmwareUpload(upload, expected)
 	if err != nil {
 		return errors.Wrap(err, "failed in step 2/4 - upload BMC firmware image")
 	}
 
 	// 3. BMC to verify the uploaded file
-	err = a.verifyUploadedFirmware(ctx)
 	a.log.V(2).WithValues("step", "3/4").Info("verify uploaded BMC firmware")
+	a.setFirmwareTaskState(taskID, constants.FirmwareInstallVerifying)
+	sections, err := a.verifyUploadedFirmware(ctx)
+	if err != nil {
+		return errors.Wrap(ErrFirmwareVerification, "failed in step 3/4 - verify uploaded BMC firmware: "+err.Error())
+	}
+
+	err = firmwareVerificationError(sections)
 	if err != nil {
 		return errors.Wrap(err, "failed in step 3/4 - verify uploaded BMC firmware")
 	}
 
+	if version, installed := uploadedVersionInstalled(sections); installed && !forceInstall {
+		a.log.V(2).WithValues("version", version).Info("BMC firmware version already installed")
+
+		return errors.Wrap(ErrFirmwareAlreadyInstalled, "BMC firmware version: "+version)
+	}
+
+	if version, installed, downgrade := uploadedVersionDowngrade(sections); downgrade && !a.allowDowngrade {
+		a.log.V(2).WithValues("version", version, "installed", installed).Info("BMC firmware downgrade blocked")
+
+		return errors.Wrap(ErrDowngradeBlocked, fmt.Sprintf("BMC firmware version: %s, installed: %s", version, installed))
+	}
+
 	// 4. Run the upgrade - preserving current config
 	a.log.V(2).WithValues("step", "4/4").Info("proceed with BMC firmware install, preserve current configuration")
+	a.setFirmwareTaskState(taskID, constants.FirmwareInstallRunning)
+	upgrading = true
+
 	err = a.upgradeBMC(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed in step 4/4 - proceed with BMC firmware install")
+		return errors.Wrap(bmclibErrs.ErrFirmwareInstall, "failed in step 4/4 - proceed with BMC firmware install: "+err.Error())
 	}
 
 	return nil
 }
 
 // firmwareInstallBIOS uploads and installs firmware for the BIOS component
-func (a *ASRockRack) firmwareInstallBIOS(ctx context.Context, reader io.Reader, fileSize int64) error {
+func (a *ASRockRack) firmwareInstallBIOS(ctx context.Context, taskID string, reader io.Reader, expected *firmwareUpload) error {
 	var err error
 
 	// 1. upload firmware image file
 	a.log.V(2).WithValues("step", "1/3").Info("upload BIOS firmware image")
-	err = a.uploadFirmware(ctx, "api/asrr/maintenance/BIOS/firmware", reader, fileSize)
+	a.setFirmwareTaskState(taskID, constants.FirmwareInstallUploading)
+	upload, err := a.uploadFirmware(ctx, "api/asrr/maintenance/BIOS/firmware", reader, expected.size)
+	if err != nil {
+		return errors.Wrap(bmclibErrs.ErrFirmwareUpload, "failed in step 1/3 - upload BIOS firmware image: "+err.Error())
+	}
+
+	err = verifyFirmwareUpload(upload, expected)
 	if err != nil {
 		return errors.Wrap(err, "failed in step 1/3 - upload BIOS firmware image")
 	}
@@ -109,14 +259,154 @@ func (a *ASRockRack) firmwareInstallBIOS(ctx context.Context, reader io.Reader,
 	a.log.V(2).WithValues("step", "2/3").Info("set BIOS preserve flash configuration")
 	err = a.biosUpgradeConfiguration(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed in step 2/3 - set flash configuration")
+		return errors.Wrap(bmclibErrs.ErrFirmwareInstall, "failed in step 2/3 - set flash configuration: "+err.Error())
 	}
 
 	// 3. run upgrade
 	a.log.V(2).WithValues("step", "3/3").Info("proceed with BIOS firmware install")
+	a.setFirmwareTaskState(taskID, constants.FirmwareInstallRunning)
 	err = a.upgradeBIOS(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed in step 3/3 - proceed with BIOS firmware install")
+		return errors.Wrap(bmclibErrs.ErrFirmwareInstall, "failed in step 3/3 - proceed with BIOS firmware install: "+err.Error())
+	}
+
+	return nil
+}
+
+// firmwareVerificationError returns ErrFirmwareVerification naming the image sections the BMC reports as failed verification
+func firmwareVerificationError(sections []*firmwareVerification) error {
+	failed := []string{}
+
+	for _, section := range sections {
+		if section.SectionStatus == firmwareSectionStatusOK && section.VerificationStatus == firmwareVerificationVerified {
+			continue
+		}
+
+		name := section.CurrentImageName
+		if name == "" {
+			name = strconv.Itoa(section.ID)
+		}
+
+		failed = append(failed, fmt.Sprintf("%s (section status: %d, verification status: %d)", name, section.SectionStatus, section.VerificationStatus))
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return errors.Wrap(ErrFirmwareVerification, "image sections failed verification: "+strings.Join(failed, ", "))
+}
+
+// uploadedVersionInstalled returns the uploaded firmware image version and true
+// if the BMC reports the uploaded image version matches the current image version
+func uploadedVersionInstalled(sections []*firmwareVerification) (version string, installed bool) {
+	for _, section := range sections {
+		newVersion := strings.TrimSpace(section.NewImageVersion)
+		if newVersion == "" || !strings.EqualFold(newVersion, strings.TrimSpace(section.CurrentImageVersion1)) {
+			return newVersion, false
+		}
+
+		version = newVersion
+	}
+
+	return version, version != ""
+}
+
+// uploadedVersionDowngrade returns the uploaded firmware image version, the current image version and true
+// if the BMC reports the uploaded image version is older than the current image version of any firmware section
+func uploadedVersionDowngrade(sections []*firmwareVerification) (version, installed string, downgrade bool) {
+	for _, section := range sections {
+		version = strings.TrimSpace(section.NewImageVersion)
+		installed = strings.TrimSpace(section.CurrentImageVersion1)
+
+		if cmp, ok := compareFirmwareVersions(version, installed); ok && cmp < 0 {
+			return version, installed, true
+		}
+	}
+
+	return version, installed, false
+}
+
+// compareFirmwareVersions compares the numeric fields of the firmware versions - "01.19.00", "1.10.2",
+// returning -1 when a is older than b, 0 when equal and 1 when newer. Missing trailing fields are considered zero.
+//
+// ok is false when either version has a field which is not a number, the versions are not compared.
+func compareFirmwareVersions(a, b string) (cmp int, ok bool) {
+	fieldsA, okA := versionFields(a)
+	fieldsB, okB := versionFields(b)
+
+	if !okA || !okB {
+		return 0, false
+	}
+
+	for idx := 0; idx < len(fieldsA) || idx < len(fieldsB); idx++ {
+		var fieldA, fieldB int
+		if idx < len(fieldsA) {
+			fieldA = fieldsA[idx]
+		}
+
+		if idx < len(fieldsB) {
+			fieldB = fieldsB[idx]
+		}
+
+		switch {
+		case fieldA < fieldB:
+			return -1, true
+		case fieldA > fieldB:
+			return 1, true
+		}
+	}
+
+	return 0, true
+}
+
+// versionFields returns the numeric fields of the version separated by dots, hyphens or underscores
+func versionFields(version string) ([]int, bool) {
+	fields := strings.FieldsFunc(strings.TrimSpace(version), func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	})
+
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	numbers := make([]int, 0, len(fields))
+	for _, field := range fields {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	return numbers, true
+}
+
+// verifyFirmwareUpload returns ErrFirmwareChecksumMismatch when the uploaded image size or checksum
+// does not match the expected image, or the image size and checksum reported by the BMC.
+//
+// The expected size and checksum are compared only when set, a truncated image read from
+// a file is detected by the file size, a truncated upload by the size reported by the BMC.
+func verifyFirmwareUpload(upload, expected *firmwareUpload) error {
+	mismatch := func(what string, expected, uploaded interface{}) error {
+		return errors.Wrap(ErrFirmwareChecksumMismatch, fmt.Sprintf("%s mismatch, expected: %v, uploaded: %v", what, expected, uploaded))
+	}
+
+	if expected.size > 0 && upload.size != expected.size {
+		return mismatch("image size", expected.size, upload.size)
+	}
+
+	if expected.checksum != "" && upload.checksum != expected.checksum {
+		return mismatch("image checksum", expected.checksum, upload.checksum)
+	}
+
+	if upload.bmcSize > 0 && upload.bmcSize != upload.size {
+		return mismatch("BMC received image size", upload.size, upload.bmcSize)
+	}
+
+	if upload.bmcChecksum != "" && !strings.EqualFold(upload.bmcChecksum, upload.checksum) {
+		return mismatch("BMC received image checksum", upload.checksum, upload.bmcChecksum)
 	}
 
 	return nil
@@ -0,0 +1,517 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/constants"
+	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockFirmwareBMC returns a BMC mock for the BMC firmware install, failing the upload or verification steps as requested
+func mockFirmwareBMC(image []byte, failUpload, failVerification bool) *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/maintenance/flash", func(w http.ResponseWriter, r *http.Request) {})
+	handler.HandleFunc("/api/maintenance/firmware", func(w http.ResponseWriter, r *http.Request) {
+		if failUpload {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		file, _, err := r.FormFile("fwimage")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		uploaded, _ := io.ReadAll(file)
+		if !bytes.Equal(image, uploaded) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write(fwUploadResponse)
+	})
+
+	handler.HandleFunc("/api/maintenance/firmware/verification", func(w http.ResponseWriter, r *http.Request) {
+		if failVerification {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+
+		_, _ = w.Write(fwVerificationResponse)
+	})
+
+	handler.HandleFunc("/api/maintenance/firmware/upgrade", func(w http.ResponseWriter, r *http.Request) {})
+	handler.HandleFunc("/api/maintenance/firmware/flash-progress", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{ "id": 1, "action": "Flashing...", "progress": "50% done", "state": 0 }`))
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_FirmwareInstall(t *testing.T) {
+	image := []byte("firmware image")
+
+	testCases := []struct {
+		name             string
+		failUpload       bool
+		failVerification bool
+		err              error
+		expectedStatus   string
+	}{
+		{
+			"install initiated",
+			false,
+			false,
+			nil,
+			constants.FirmwareInstallRunning,
+		},
+		{
+			"upload failure",
+			true,
+			false,
+			bmclibErrs.ErrFirmwareUpload,
+			constants.FirmwareInstallFailed,
+		},
+		{
+			"verification failure",
+			false,
+			true,
+			ErrFirmwareVerification,
+			constants.FirmwareInstallFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := mockFirmwareBMC(image, tc.failUpload, tc.failVerification)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			// the image is streamed from a reader of unknown size
+			taskID, err := client.FirmwareInstall(context.TODO(), common.SlugBMC, constants.FirmwareApplyImmediate, false, bytes.NewReader(image))
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			assert.NotEmpty(t, taskID)
+
+			status, err := client.FirmwareInstallStatus(context.TODO(), "0.03.00", common.SlugBMC, taskID)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedStatus, status)
+		})
+	}
+}
+
+func Test_FirmwareInstallUnsupported(t *testing.T) {
+	client := New("127.0.0.1", "foo", "bar", logr.Discard())
+
+	taskID, err := client.FirmwareInstall(context.TODO(), common.SlugNIC, constants.FirmwareApplyImmediate, false, bytes.NewReader(nil))
+	assert.ErrorIs(t, err, bmclibErrs.ErrFirmwareInstall)
+	assert.Empty(t, taskID)
+}
+
+func Test_FirmwareInstallChecksum(t *testing.T) {
+	image := []byte("firmware image")
+	sum := sha256.Sum256(image)
+	checksum := hex.EncodeToString(sum[:])
+
+	testCases := []struct {
+		name     string
+		reader   io.Reader
+		checksum string
+		// the number of image bytes the BMC reports as received
+		received int
+		err      error
+	}{
+		{
+			"checksum match",
+			bytes.NewReader(image),
+			checksum,
+			len(image),
+			nil,
+		},
+		{
+			"truncated upload",
+			bytes.NewReader(image),
+			"",
+			len(image) - 4,
+			ErrFirmwareChecksumMismatch,
+		},
+		{
+			"truncated image",
+			bytes.NewReader(image[:len(image)-4]),
+			checksum,
+			len(image) - 4,
+			ErrFirmwareChecksumMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var upgraded, reset bool
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/maintenance/flash", func(w http.ResponseWriter, r *http.Request) {})
+			handler.HandleFunc("/api/maintenance/reset", func(w http.ResponseWriter, r *http.Request) {
+				reset = true
+			})
+			handler.HandleFunc("/api/maintenance/firmware", func(w http.ResponseWriter, r *http.Request) {
+				file, _, err := r.FormFile("fwimage")
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				uploaded, _ := io.ReadAll(file)
+				received := sha256.Sum256(uploaded[:tc.received])
+
+				_, _ = w.Write([]byte(fmt.Sprintf(`{"cc": 0, "size": %d, "checksum": "%x"}`, tc.received, received)))
+			})
+			handler.HandleFunc("/api/maintenance/firmware/verification", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(fwVerificationResponse)
+			})
+			handler.HandleFunc("/api/maintenance/firmware/upgrade", func(w http.ResponseWriter, r *http.Request) {
+				upgraded = true
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			taskID, err := client.FirmwareInstallWithChecksum(context.TODO(), common.SlugBMC, constants.FirmwareApplyImmediate, false, tc.reader, tc.checksum)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.False(t, upgraded, "firmware install applied for a mismatched image")
+				assert.True(t, reset, "BMC not reset to exit the flash mode")
+				assert.Equal(t, constants.FirmwareInstallFailed, client.firmwareTaskState(taskID))
+
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.True(t, upgraded)
+			assert.False(t, reset)
+		})
+	}
+}
+
+func Test_FirmwareInstallVerificationFailed(t *testing.T) {
+	image := []byte("firmware image")
+
+	failed, err := os.ReadFile("./fixtures/E3C246D4I-NL/firmware-verification-failed.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		response []byte
+		err      string
+	}{
+		{
+			// the BMC rejects the boot section of the image with a 200 OK
+			"rejected section",
+			failed,
+			"boot (section status: 1, verification status: 2)",
+		},
+		{
+			// the uploaded image version is not known, the installed version and downgrade checks cannot apply
+			"garbled response",
+			[]byte(`[ { "id": 1, "current_image_name": "ast2500e", "new_image_ver`),
+			"error decoding firmware verification response",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var upgraded, reset bool
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/maintenance/flash", func(w http.ResponseWriter, r *http.Request) {})
+			handler.HandleFunc("/api/maintenance/firmware", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(fwUploadResponse)
+			})
+			handler.HandleFunc("/api/maintenance/firmware/verification", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(tc.response)
+			})
+			handler.HandleFunc("/api/maintenance/firmware/upgrade", func(w http.ResponseWriter, r *http.Request) {
+				upgraded = true
+			})
+			handler.HandleFunc("/api/maintenance/reset", func(w http.ResponseWriter, r *http.Request) {
+				reset = true
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			taskID, err := client.FirmwareInstall(context.TODO(), common.SlugBMC, constants.FirmwareApplyImmediate, false, bytes.NewReader(image))
+			assert.ErrorIs(t, err, ErrFirmwareVerification)
+			assert.ErrorContains(t, err, tc.err)
+			assert.False(t, upgraded, "firmware install applied for a rejected image")
+			assert.True(t, reset, "BMC not reset to exit the flash mode")
+			assert.Equal(t, constants.FirmwareInstallFailed, client.firmwareTaskState(taskID))
+		})
+	}
+}
+
+func Test_FirmwareInstallFlashModeReset(t *testing.T) {
+	image := []byte("firmware image")
+
+	testCases := []struct {
+		name         string
+		upload       int
+		verification int
+		upgrade      int
+		err          error
+		reset        bool
+	}{
+		{"upload error", http.StatusInternalServerError, http.StatusOK, http.StatusOK, bmclibErrs.ErrFirmwareUpload, true},
+		{"verification error", http.StatusOK, http.StatusInternalServerError, http.StatusOK, ErrFirmwareVerification, true},
+		// the flash may be in progress when the upgrade request fails
+		{"upgrade error", http.StatusOK, http.StatusOK, http.StatusInternalServerError, bmclibErrs.ErrFirmwareInstall, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var reset bool
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/maintenance/flash", func(w http.ResponseWriter, r *http.Request) {})
+			handler.HandleFunc("/api/maintenance/firmware", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.upload)
+				_, _ = w.Write(fwUploadResponse)
+			})
+			handler.HandleFunc("/api/maintenance/firmware/verification", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.verification)
+				_, _ = w.Write(fwVerificationResponse)
+			})
+			handler.HandleFunc("/api/maintenance/firmware/upgrade", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.upgrade)
+			})
+			handler.HandleFunc("/api/maintenance/reset", func(w http.ResponseWriter, r *http.Request) {
+				reset = true
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			_, err := client.FirmwareInstall(context.TODO(), common.SlugBMC, constants.FirmwareApplyImmediate, false, bytes.NewReader(image))
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.reset, reset)
+		})
+	}
+}
+
+func Test_FirmwareInstallForce(t *testing.T) {
+	image := []byte("firmware image")
+
+	for _, forceInstall := range []bool{false, true} {
+		t.Run(fmt.Sprintf("forceInstall=%v", forceInstall), func(t *testing.T) {
+			var upgraded, reset bool
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/maintenance/flash", func(w http.ResponseWriter, r *http.Request) {})
+			handler.HandleFunc("/api/maintenance/firmware", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(fwUploadResponse)
+			})
+			handler.HandleFunc("/api/maintenance/firmware/verification", func(w http.ResponseWriter, r *http.Request) {
+				// the uploaded image is of the installed version
+				_, _ = w.Write(bytes.Replace(fwVerificationResponse, []byte(`"new_image_version": "0.03.00"`), []byte(`"new_image_version": "0.01.00"`), 1))
+			})
+			handler.HandleFunc("/api/maintenance/firmware/upgrade", func(w http.ResponseWriter, r *http.Request) {
+				upgraded = true
+			})
+			handler.HandleFunc("/api/maintenance/reset", func(w http.ResponseWriter, r *http.Request) {
+				reset = true
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			taskID, err := client.FirmwareInstall(context.TODO(), common.SlugBMC, constants.FirmwareApplyImmediate, forceInstall, bytes.NewReader(image))
+			if forceInstall {
+				assert.Nil(t, err)
+				assert.True(t, upgraded)
+				assert.False(t, reset)
+
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrFirmwareAlreadyInstalled)
+			assert.False(t, upgraded)
+			assert.True(t, reset, "BMC not reset to exit the flash mode")
+			assert.Equal(t, constants.FirmwareInstallComplete, client.firmwareTaskState(taskID))
+		})
+	}
+}
+
+func Test_FirmwareInstallDowngrade(t *testing.T) {
+	image := []byte("firmware image")
+
+	// the installed version is 0.01.00
+	testCases := []struct {
+		name           string
+		version        string
+		allowDowngrade bool
+		err            error
+		upgraded       bool
+		state          string
+	}{
+		{"upgrade", "0.03.00", false, nil, true, constants.FirmwareInstallRunning},
+		{"same version", "0.01.00", false, ErrFirmwareAlreadyInstalled, false, constants.FirmwareInstallComplete},
+		{"downgrade blocked", "0.00.09", false, ErrDowngradeBlocked, false, constants.FirmwareInstallFailed},
+		{"downgrade allowed", "0.00.09", true, nil, true, constants.FirmwareInstallRunning},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var upgraded, reset bool
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/maintenance/flash", func(w http.ResponseWriter, r *http.Request) {})
+			handler.HandleFunc("/api/maintenance/firmware", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(fwUploadResponse)
+			})
+			handler.HandleFunc("/api/maintenance/firmware/verification", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(bytes.Replace(fwVerificationResponse, []byte(`"new_image_version": "0.03.00"`), []byte(`"new_image_version": "`+tc.version+`"`), 1))
+			})
+			handler.HandleFunc("/api/maintenance/firmware/upgrade", func(w http.ResponseWriter, r *http.Request) {
+				upgraded = true
+			})
+			handler.HandleFunc("/api/maintenance/reset", func(w http.ResponseWriter, r *http.Request) {
+				reset = true
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			opts := []ASRockOption{WithInsecureSkipVerify()}
+			if tc.allowDowngrade {
+				opts = append(opts, WithAllowDowngrade())
+			}
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), opts...)
+
+			taskID, err := client.FirmwareInstall(context.TODO(), common.SlugBMC, constants.FirmwareApplyImmediate, false, bytes.NewReader(image))
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, tc.upgraded, upgraded)
+			// the BMC is reset to exit the flash mode when the install is skipped
+			assert.Equal(t, !tc.upgraded, reset)
+			assert.Equal(t, tc.state, client.firmwareTaskState(taskID))
+		})
+	}
+}
+
+func Test_compareFirmwareVersions(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		cmp  int
+		ok   bool
+	}{
+		{"0.03.00", "0.01.00", 1, true},
+		{"01.19.00", "1.19", 0, true},
+		{"1.9", "1.10", -1, true},
+		{"2.0-1", "2.0", 1, true},
+		{"L2.07B", "L2.07A", 0, false},
+		{"", "0.01.00", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.a+" "+tc.b, func(t *testing.T) {
+			cmp, ok := compareFirmwareVersions(tc.a, tc.b)
+			assert.Equal(t, tc.cmp, cmp)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func Test_FirmwareInstallInsufficientPrivilege(t *testing.T) {
+	image := []byte("firmware image")
+
+	testCases := []struct {
+		name      string
+		privilege int
+		err       error
+		uploads   int
+	}{
+		{"readonly user", privilegeUser, ErrInsufficientPrivilege, 0},
+		{"operator", privilegeOperator, ErrInsufficientPrivilege, 0},
+		{"administrator", privilegeAdministrator, nil, 1},
+		{"privilege not reported", 0, nil, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var uploads int
+
+			mock := mockFirmwareBMC(image, false, false)
+			defer mock.Close()
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/session", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(fmt.Sprintf(`{ "ok": 0, "privilege": %d, "CSRFToken": "l5L29IP7" }`, tc.privilege)))
+			})
+			handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/maintenance/firmware" {
+					uploads++
+				}
+
+				mock.Config.Handler.ServeHTTP(w, r)
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+			if err := client.Open(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := client.FirmwareInstall(context.TODO(), common.SlugBMC, "", false, bytes.NewReader(image))
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Contains(t, err.Error(), "firmware install requires the administrator privilege")
+			} else {
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, tc.uploads, uploads)
+		})
+	}
+}
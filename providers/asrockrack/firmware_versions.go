@@ -0,0 +1,116 @@
+package asrockrack
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// FirmwareVersions returns the installed firmware versions of the system components, keyed by the component
+//
+//	bios                      - BIOS
+//	bmc                       - BMC
+//	cpld, cpld.<n>            - CPLD, numbered from 1 when the board has multiple CPLDs
+//	intel_me                  - Intel Management Engine
+//	microcode                 - CPU microcode
+//	backplane                 - backplane board
+//	nic.<slot>                - PCIe/OCP network adapter by the slot - nic.pcie7
+//	drive.<port>              - drive by the port it is attached to, or the serial when the port is not known - drive.sata_4
+//	gpu.<slot>                - GPU by the slot - gpu.pcie1
+//	psu.<id>                  - PSU by the PSU identifier - psu.psu1
+//	storage_controller.<id>   - RAID/storage controller by the controller ID - storage_controller.0
+//
+// Components the BMC lists without a firmware version are not included. The BIOS and BMC versions
+// are required, the NIC, drive, GPU, PSU and storage controller versions are collected on a best effort basis.
+func (a *ASRockRack) FirmwareVersions(ctx context.Context) (map[string]string, error) {
+	fwInfo, err := a.firmwareInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]string{}
+
+	set := func(key, version string) {
+		version = strings.TrimSpace(version)
+		if !versionPlaceholder(version) {
+			versions[key] = version
+		}
+	}
+
+	set("bios", fwInfo.BIOSVersion)
+	set("bmc", fwInfo.BMCVersion)
+	set("intel_me", fwInfo.MEVersion)
+	set("microcode", fwInfo.MicrocodeVersion)
+	set("backplane", fwInfo.BPBVersion)
+
+	found := cplds(&common.Device{}, fwInfo.CPLDVersion)
+	for idx, cpld := range found {
+		if len(found) == 1 {
+			set("cpld", cpld.Firmware.Installed)
+			continue
+		}
+
+		set("cpld."+strconv.Itoa(idx+1), cpld.Firmware.Installed)
+	}
+
+	components, err := a.inventoryInfo(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect component firmware versions, skipped")
+	}
+
+	for _, component := range components {
+		slot := component.ProductAssetTag
+		if valuePlaceholder(slot) {
+			slot = component.DeviceName
+		}
+
+		switch {
+		case component.DeviceType == "Storage device":
+			if valuePlaceholder(component.ProductAssetTag) {
+				slot = component.ProductSerialNumber
+			}
+
+			set("drive."+sensorKey(slot), component.ProductVersion)
+		case component.DeviceType == "GPU", component.DeviceType == "Graphics", component.DeviceType == "Accelerator":
+			set("gpu."+sensorKey(slot), component.ProductVersion)
+		case isNICComponent(component):
+			set("nic."+sensorKey(slot), component.ProductVersion)
+		}
+	}
+
+	frus, err := a.fruInfo(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect PSU firmware versions, skipped")
+	}
+
+	for _, psu := range psusFromFRUs(frus) {
+		if psu.Firmware != nil {
+			set("psu."+sensorKey(psu.ID), psu.Firmware.Installed)
+		}
+	}
+
+	controllers, err := a.storageControllers(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect storage controller firmware versions, skipped")
+	}
+
+	for _, controller := range controllers {
+		set("storage_controller."+strconv.Itoa(controller.ID), controller.FirmwareVersion)
+	}
+
+	return versions, nil
+}
+
+// isNICComponent returns true if the inventory component is a network adapter,
+// PCIe cards are listed with the PCI class code and name - 020000(Ethernet controller).
+func isNICComponent(component *component) bool {
+	if component.DeviceType != "PCIe & OCP Card" {
+		return false
+	}
+
+	return strings.HasPrefix(component.ProductName, "02") ||
+		strings.Contains(strings.ToLower(component.ProductName), "ethernet") ||
+		strings.Contains(strings.ToLower(component.ProductName), "network")
+}
@@ -0,0 +1,59 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FirmwareVersions(t *testing.T) {
+	fwInfo := []byte(`{
+		"BMC_fw_version": "0.01.00",
+		"BIOS_fw_version": "L2.07B",
+		"ME_fw_version": "5.1.3.78",
+		"Micro_Code_version": "000000ca",
+		"CPLD_version": "CPLD1: 1.02, CPLD2: 2.01",
+		"CM_version": "0.13.01",
+		"BPB_version": "N/A",
+		"Node_id": "2"
+	}`)
+
+	server := asrockracktest.NewServer(asrockracktest.WithResponse("api/asrr/fw-info", http.StatusOK, fwInfo))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	versions, err := client.FirmwareVersions(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "L2.07B", versions["bios"])
+	assert.Equal(t, "0.01.00", versions["bmc"])
+	assert.Equal(t, "1.02", versions["cpld.1"])
+	assert.Equal(t, "2.01", versions["cpld.2"])
+	assert.Equal(t, "5.1.3.78", versions["intel_me"])
+	assert.Equal(t, "000000ca", versions["microcode"])
+	assert.Equal(t, "92.00.25.00.08", versions["gpu.pcie1"])
+
+	// components listed without a firmware version are not included
+	assert.NotContains(t, versions, "backplane")
+	assert.NotContains(t, versions, "drive.sata_4")
+
+	// a single CPLD is keyed without a number
+	server.SetResponse("api/asrr/fw-info", http.StatusOK, []byte(`{"BMC_fw_version": "0.01.00", "BIOS_fw_version": "L2.07B", "CPLD_version": "1.02"}`))
+
+	versions, err = client.FirmwareVersions(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "1.02", versions["cpld"])
+	assert.NotContains(t, versions, "cpld.1")
+}
@@ -0,0 +1,193 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const fruEndpoint = "api/fru"
+
+// FRU is the FRU data of the BMC FRU devices, as listed by the BMC
+//
+// The BMC FRU is device ID 0, additional FRU devices like PSUs are listed with their own ID.
+type FRU struct {
+	Devices []*FRUDevice `json:"devices"`
+}
+
+// FRUDevice is a FRU device and its FRU areas, areas not listed by the BMC are nil
+//
+// The BMC firmware does not decode the internal use and multi record areas, when listed they are included as returned.
+type FRUDevice struct {
+	ID           int              `json:"id"`
+	Name         string           `json:"name"`
+	CommonHeader *FRUCommonHeader `json:"common_header,omitempty"`
+	Chassis      *FRUChassisArea  `json:"chassis,omitempty"`
+	Board        *FRUBoardArea    `json:"board,omitempty"`
+	Product      *FRUProductArea  `json:"product,omitempty"`
+	InternalUse  json.RawMessage  `json:"internal_use,omitempty"`
+	MultiRecord  json.RawMessage  `json:"multi_record,omitempty"`
+}
+
+// FRUCommonHeader is the FRU common header, the area offsets are in multiples of 8 bytes, 0 when the area is not present
+type FRUCommonHeader struct {
+	Version                    int `json:"version"`
+	InternalUseAreaStartOffset int `json:"internal_use_area_start_offset"`
+	ChassisInfoAreaStartOffset int `json:"chassis_info_area_start_offset"`
+	BoardInfoAreaStartOffset   int `json:"board_info_area_start_offset"`
+	ProductInfoAreaStartOffset int `json:"product_info_area_start_offset"`
+	MultiRecordAreaStartOffset int `json:"multi_record_area_start_offset"`
+}
+
+// FRUChassisArea is the FRU chassis info area
+type FRUChassisArea struct {
+	Version      int    `json:"version"`
+	Length       int    `json:"length"`
+	Type         string `json:"type"`
+	PartNumber   string `json:"part_number"`
+	SerialNumber string `json:"serial_number"`
+	CustomFields string `json:"custom_fields"`
+}
+
+// FRUBoardArea is the FRU board info area
+//
+// Date is the manufacturing date as reported - "Mon Jul 20 06:04:00 2020", ManufacturedAt is the parsed date, nil when not parsed.
+type FRUBoardArea struct {
+	Version        int        `json:"version"`
+	Length         int        `json:"length"`
+	Language       int        `json:"language"`
+	Date           string     `json:"date"`
+	ManufacturedAt *time.Time `json:"manufactured_at,omitempty"`
+	Manufacturer   string     `json:"manufacturer"`
+	ProductName    string     `json:"product_name"`
+	SerialNumber   string     `json:"serial_number"`
+	PartNumber     string     `json:"part_number"`
+	FRUFileID      string     `json:"fru_file_id"`
+	CustomFields   string     `json:"custom_fields"`
+}
+
+// FRUProductArea is the FRU product info area
+type FRUProductArea struct {
+	Version        int    `json:"version"`
+	Length         int    `json:"length"`
+	Language       int    `json:"language"`
+	Manufacturer   string `json:"manufacturer"`
+	ProductName    string `json:"product_name"`
+	PartNumber     string `json:"part_number"`
+	ProductVersion string `json:"product_version"`
+	SerialNumber   string `json:"serial_number"`
+	AssetTag       string `json:"asset_tag"`
+	FRUFileID      string `json:"fru_file_id"`
+	CustomFields   string `json:"custom_fields"`
+}
+
+// GetFRU returns the FRU data of all FRU devices, with all fields of the common header and the chassis, board and product areas.
+//
+// The attributes the BMC reports as not available - N/A, To Be Filled By O.E.M. are cleared, like in the inventory.
+func (a *ASRockRack) GetFRU(ctx context.Context) (_ *FRU, err error) {
+	defer func() { err = a.endpointError("FRU info", fruEndpoint, err) }()
+
+	records, err := a.fruRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &FRU{Devices: make([]*FRUDevice, 0, len(records))}
+
+	for _, record := range records {
+		device := &FRUDevice{}
+
+		areas := []struct {
+			key   string
+			value interface{}
+		}{
+			{"device", &fruDevice{}},
+			{"common_header", &device.CommonHeader},
+			{"chassis", &device.Chassis},
+			{"board", &device.Board},
+			{"product", &device.Product},
+		}
+
+		for _, area := range areas {
+			raw, exists := record[area.key]
+			if !exists || string(raw) == "null" {
+				continue
+			}
+
+			if err := unmarshalTolerant(raw, area.value); err != nil {
+				return nil, errors.Wrap(err, "FRU "+area.key+" area")
+			}
+
+			if d, ok := area.value.(*fruDevice); ok {
+				device.ID, device.Name = d.ID, d.Name
+			}
+		}
+
+		device.InternalUse = record["internal_use"]
+		device.MultiRecord = record["multi_record"]
+
+		device.normalize()
+		export.Devices = append(export.Devices, device)
+	}
+
+	return export, nil
+}
+
+// normalize clears the FRU area attributes the BMC reports as not available and parses the board manufacturing date
+func (d *FRUDevice) normalize() {
+	fields := []*string{}
+
+	if d.Chassis != nil {
+		fields = append(fields, &d.Chassis.Type, &d.Chassis.PartNumber, &d.Chassis.SerialNumber, &d.Chassis.CustomFields)
+	}
+
+	if d.Board != nil {
+		fields = append(fields, &d.Board.Date, &d.Board.Manufacturer, &d.Board.ProductName, &d.Board.SerialNumber,
+			&d.Board.PartNumber, &d.Board.FRUFileID, &d.Board.CustomFields)
+	}
+
+	if d.Product != nil {
+		fields = append(fields, &d.Product.Manufacturer, &d.Product.ProductName, &d.Product.PartNumber, &d.Product.ProductVersion,
+			&d.Product.SerialNumber, &d.Product.AssetTag, &d.Product.FRUFileID, &d.Product.CustomFields)
+	}
+
+	for _, field := range fields {
+		*field = normalizeNA(*field)
+	}
+
+	if d.Board != nil && d.Board.Date != "" {
+		// the date is formatted by the BMC with ctime - "Mon Jul 20 06:04:00 2020\n"
+		if date, err := time.Parse(time.ANSIC, strings.Join(strings.Fields(d.Board.Date), " ")); err == nil {
+			d.Board.ManufacturedAt = &date
+		}
+	}
+}
+
+// fruRecords returns the FRU records listed by the fru info endpoint, one for each FRU device, keyed by the FRU area
+func (a *ASRockRack) fruRecords(ctx context.Context) ([]map[string]json.RawMessage, error) {
+	resp, statusCode, err := a.getWithRetry(ctx, fruEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCodeError(statusCode)
+	}
+
+	data := []map[string]json.RawMessage{}
+	err = json.Unmarshal(resp, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no FRU data returned")
+	}
+
+	return data, nil
+}
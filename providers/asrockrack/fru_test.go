@@ -0,0 +1,106 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetFRU(t *testing.T) {
+	server := asrockracktest.NewServer()
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	fru, err := client.GetFRU(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, len(fru.Devices))
+
+	device := fru.Devices[0]
+	assert.Equal(t, 0, device.ID)
+	assert.Equal(t, "BMC_FRU", device.Name)
+
+	assert.Equal(t, &FRUCommonHeader{
+		Version:                    1,
+		ChassisInfoAreaStartOffset: 1,
+		BoardInfoAreaStartOffset:   4,
+		ProductInfoAreaStartOffset: 11,
+	}, device.CommonHeader)
+
+	assert.Equal(t, &FRUChassisArea{
+		Version:      1,
+		Length:       3,
+		Type:         "Main Server Chassis",
+		SerialNumber: "K61206147700263",
+	}, device.Chassis)
+
+	manufactured := time.Date(2020, time.July, 20, 6, 4, 0, 0, time.UTC)
+	assert.Equal(t, &FRUBoardArea{
+		Version:        1,
+		Length:         7,
+		Date:           "Mon Jul 20 06:04:00 2020",
+		ManufacturedAt: &manufactured,
+		Manufacturer:   "ASRockRack",
+		ProductName:    "E3C246D4I-NL",
+		SerialNumber:   "197965920000514",
+	}, device.Board)
+
+	assert.Equal(t, &FRUProductArea{
+		Version:        1,
+		Length:         7,
+		Manufacturer:   "Packet",
+		ProductName:    "c3.small.x86",
+		PartNumber:     "Open19",
+		ProductVersion: "R1.00",
+		SerialNumber:   "D6S0R8000736",
+	}, device.Product)
+
+	assert.Nil(t, device.InternalUse)
+	assert.Nil(t, device.MultiRecord)
+}
+
+func Test_GetFRUDevices(t *testing.T) {
+	// the PSU FRU lists the product area only
+	frus := []byte(`[
+		{ "device": { "id": 0, "name": "BMC_FRU" }, "board": { "version": 1, "manufacturer": "ASRockRack", "product_name": "ROMED8-2T", "date": "N/A" }, "internal_use": { "data": "0102" } },
+		{ "device": { "id": 1, "name": "PSU1_FRU" }, "product": { "version": 1, "manufacturer": "N/A", "product_name": "DPS-800AB-5 A", "serial_number": "DPS8002104000123" } }
+	]`)
+
+	server := asrockracktest.NewServer(asrockracktest.WithResponse("api/fru", http.StatusOK, frus))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	fru, err := client.GetFRU(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 2, len(fru.Devices))
+
+	bmc := fru.Devices[0]
+	assert.Equal(t, "ROMED8-2T", bmc.Board.ProductName)
+	assert.Equal(t, "", bmc.Board.Date)
+	assert.Nil(t, bmc.Board.ManufacturedAt)
+	assert.Nil(t, bmc.Product)
+	assert.JSONEq(t, `{ "data": "0102" }`, string(bmc.InternalUse))
+
+	psu := fru.Devices[1]
+	assert.Equal(t, 1, psu.ID)
+	assert.Equal(t, "PSU1_FRU", psu.Name)
+	assert.Nil(t, psu.CommonHeader)
+	assert.Nil(t, psu.Chassis)
+	assert.Nil(t, psu.Board)
+	assert.Equal(t, &FRUProductArea{Version: 1, ProductName: "DPS-800AB-5 A", SerialNumber: "DPS8002104000123"}, psu.Product)
+}
@@ -0,0 +1,84 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+)
+
+const (
+	// bmclibModule is the bmclib module path, the module version is included in the default user agent
+	bmclibModule = "github.com/bmc-toolbox/bmclib/v2"
+
+	// defaultRequestIDHeader is the header the request ID is set in on the requests to the BMC
+	defaultRequestIDHeader = "X-Request-ID"
+)
+
+// requestIDKey is the context key for the request ID
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of the context carrying the request ID, the BMC requests made with the
+// context include the request ID in the request ID header - X-Request-ID unless set with WithRequestIDHeader,
+// to correlate the requests logged by the BMC with the caller.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// requestIDFromContext returns the request ID carried by the context, if any
+func requestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithUserAgent sets the User-Agent header of the requests to the BMC, by default bmclib/<bmclib module version>.
+func WithUserAgent(userAgent string) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.userAgent = userAgent
+	}
+}
+
+// WithRequestIDHeader sets the header the request ID carried by the request context is set in,
+// by default X-Request-ID. See ContextWithRequestID.
+func WithRequestIDHeader(header string) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.requestIDHeader = header
+	}
+}
+
+// setRequestHeaders sets the user agent and the request ID carried by the context on the request headers
+func (a *ASRockRack) setRequestHeaders(ctx context.Context, header http.Header) {
+	if a.userAgent != "" {
+		header.Set("User-Agent", a.userAgent)
+	}
+
+	if requestID := requestIDFromContext(ctx); requestID != "" && a.requestIDHeader != "" {
+		header.Set(a.requestIDHeader, requestID)
+	}
+}
+
+// defaultUserAgent returns the default user agent - bmclib/v2.1.0, the version is devel
+// when the bmclib module version is not known, as when bmclib itself is built.
+func defaultUserAgent() string {
+	version := "devel"
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		modules := append([]*debug.Module{&info.Main}, info.Deps...)
+		for _, module := range modules {
+			if module.Path != bmclibModule {
+				continue
+			}
+
+			if module.Replace != nil && module.Replace.Version != "" {
+				module = module.Replace
+			}
+
+			if module.Version != "" && module.Version != "(devel)" {
+				version = module.Version
+			}
+
+			break
+		}
+	}
+
+	return "bmclib/" + version
+}
@@ -0,0 +1,91 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequestHeaders(t *testing.T) {
+	var mu sync.Mutex
+	headers := map[string]http.Header{}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		headers[r.Method+" "+r.URL.Path] = r.Header.Clone()
+
+		switch r.URL.Path {
+		case "/api/session":
+			_, _ = w.Write(loginResponse)
+		case "/api/chassis-status":
+			_, _ = w.Write(chassisStatusResponse)
+		}
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	testCases := []struct {
+		name            string
+		opts            []ASRockOption
+		requestID       string
+		userAgent       string
+		requestIDHeader string
+	}{
+		{"defaults", nil, "req-1234", "bmclib/", "X-Request-ID"},
+		{"without a request ID", nil, "", "bmclib/", "X-Request-ID"},
+		{
+			"overridden",
+			[]ASRockOption{WithUserAgent("fleet-scanner/1.2"), WithRequestIDHeader("X-Correlation-ID")},
+			"req-5678",
+			"fleet-scanner/1.2",
+			"X-Correlation-ID",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			headers = map[string]http.Header{}
+			mu.Unlock()
+
+			opts := append([]ASRockOption{WithInsecureSkipVerify()}, tc.opts...)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), opts...)
+
+			ctx := context.Background()
+			if tc.requestID != "" {
+				ctx = ContextWithRequestID(ctx, tc.requestID)
+			}
+
+			if err := client.Open(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := client.PowerStateGet(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			assert.Equal(t, 2, len(headers))
+
+			for request, header := range headers {
+				assert.True(t, strings.HasPrefix(header.Get("User-Agent"), tc.userAgent), request)
+				assert.Equal(t, tc.requestID, header.Get(tc.requestIDHeader), request)
+			}
+		})
+	}
+}
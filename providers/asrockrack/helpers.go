@@ -2,7 +2,12 @@ package asrockrack
 
 import (
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +15,8 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/bmc-toolbox/bmclib/v2/constants"
 	"github.com/bmc-toolbox/bmclib/v2/errors"
@@ -33,6 +40,20 @@ type firmwareInfo struct {
 	CMVersion        string `json:"CM_version"`
 	BPBVersion       string `json:"BPB_version"`
 	NodeID           string `json:"Node_id"`
+
+	// the chassis slot and BMC role are reported by the BMC of multi-node chassis on recent BMC firmware
+	ChassisSlot string `json:"Chassis_slot"`
+	BMCRole     string `json:"BMC_role"` // master, slave
+
+	// the build dates and the dual image (primary, backup bank) details are reported on recent BMC firmware
+	BMCBuildDate       string `json:"BMC_build_date"`
+	BMCActiveImage     int    `json:"BMC_active_image"` // 1 - primary, 2 - backup
+	BMCPrimaryVersion  string `json:"BMC_primary_fw_version"`
+	BMCBackupVersion   string `json:"BMC_backup_fw_version"`
+	BIOSBuildDate      string `json:"BIOS_build_date"`
+	BIOSActiveImage    int    `json:"BIOS_active_image"` // 1 - primary, 2 - backup
+	BIOSPrimaryVersion string `json:"BIOS_primary_fw_version"`
+	BIOSBackupVersion  string `json:"BIOS_backup_fw_version"`
 }
 
 type biosPOSTCode struct {
@@ -54,9 +75,27 @@ type component struct {
 	ProductExtra            string `json:"product_extra"`
 }
 
+// normalize clears the component attributes the BMC reports as not available - N/A,
+// except the device name and type which identify the component.
+func (c *component) normalize() {
+	for _, field := range []*string{
+		&c.ProductManufacturerName,
+		&c.ProductName,
+		&c.ProductPartNumber,
+		&c.ProductVersion,
+		&c.ProductSerialNumber,
+		&c.ProductAssetTag,
+		&c.ProductExtra,
+	} {
+		*field = normalizeNA(*field)
+	}
+}
+
 // fru is part of a payload returned by the fru info endpoint
 type fru struct {
 	Component      string
+	DeviceID       int
+	DeviceName     string
 	Version        int    `json:"version"`
 	Length         int    `json:"length"`
 	Language       int    `json:"language"`
@@ -71,28 +110,164 @@ type fru struct {
 	CustomFields   string `json:"custom_fields"`
 }
 
-// sensor is part of the payload returned by the sensors endpoint
-type sensor struct {
-	ID                            int     `json:"id"`
-	SensorNumber                  int     `json:"sensor_number"`
-	Name                          string  `json:"name"`
-	OwnerID                       int     `json:"owner_id"`
-	OwnerLun                      int     `json:"owner_lun"`
-	RawReading                    float64 `json:"raw_reading"`
-	Type                          string  `json:"type"`
-	TypeNumber                    int     `json:"type_number"`
-	Reading                       float64 `json:"reading"`
-	SensorState                   int     `json:"sensor_state"`
-	DiscreteState                 int     `json:"discrete_state"`
-	SettableReadableThreshMask    int     `json:"settable_readable_threshMask"`
-	LowerNonRecoverableThreshold  float64 `json:"lower_non_recoverable_threshold"`
-	LowerCriticalThreshold        float64 `json:"lower_critical_threshold"`
-	LowerNonCriticalThreshold     float64 `json:"lower_non_critical_threshold"`
-	HigherNonCriticalThreshold    float64 `json:"higher_non_critical_threshold"`
-	HigherCriticalThreshold       float64 `json:"higher_critical_threshold"`
-	HigherNonRecoverableThreshold float64 `json:"higher_non_recoverable_threshold"`
-	Accessible                    int     `json:"accessible"`
-	Unit                          string  `json:"unit"`
+// normalize clears the FRU attributes the BMC reports as not available - N/A, To Be Filled By O.E.M.
+func (f *fru) normalize() {
+	for _, field := range []*string{
+		&f.Manufacturer,
+		&f.ProductName,
+		&f.PartNumber,
+		&f.ProductVersion,
+		&f.SerialNumber,
+		&f.AssetTag,
+		&f.FruFileID,
+		&f.Type,
+		&f.CustomFields,
+	} {
+		*field = normalizeNA(*field)
+	}
+}
+
+// fruDevice identifies the FRU device a set of fru areas belongs to
+//
+// the BMC FRU is device ID 0, additional FRU devices like PSUs are listed with their own ID.
+type fruDevice struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Sensor is a sensor reading, its state and thresholds as returned by the BMC sensors endpoint
+//
+// The SensorState is a bitmask of the threshold states, where 1 indicates the reading is within thresholds,
+// the thresholds are valid only when readable in the lower byte of the SettableReadableThreshMask.
+type Sensor struct {
+	ID                            int         `json:"id"`
+	SensorNumber                  int         `json:"sensor_number"`
+	Name                          string      `json:"name"`
+	OwnerID                       int         `json:"owner_id"`
+	OwnerLun                      int         `json:"owner_lun"`
+	RawReading                    float64     `json:"raw_reading"`
+	Type                          string      `json:"type"`
+	TypeNumber                    int         `json:"type_number"`
+	Reading                       float64     `json:"reading"`
+	SensorState                   SensorState `json:"sensor_state"`
+	DiscreteState                 int         `json:"discrete_state"`
+	SettableReadableThreshMask    int         `json:"settable_readable_threshMask"`
+	LowerNonRecoverableThreshold  float64     `json:"lower_non_recoverable_threshold"`
+	LowerCriticalThreshold        float64     `json:"lower_critical_threshold"`
+	LowerNonCriticalThreshold     float64     `json:"lower_non_critical_threshold"`
+	HigherNonCriticalThreshold    float64     `json:"higher_non_critical_threshold"`
+	HigherCriticalThreshold       float64     `json:"higher_critical_threshold"`
+	HigherNonRecoverableThreshold float64     `json:"higher_non_recoverable_threshold"`
+	Accessible                    int         `json:"accessible"`
+	Unit                          string      `json:"unit"`
+
+	// set by the WithSensorHealthIgnore, WithUnknownSensorsInformational and WithChassisIntrusionWarning options
+	healthIgnored      bool
+	unknownStateIsInfo bool
+	intrusionIsWarning bool
+}
+
+// networkInterface is part of the payload returned by the network settings endpoint
+type networkInterface struct {
+	ID            int    `json:"id"`
+	InterfaceName string `json:"interface_name"`
+	ChannelNumber int    `json:"channel_number"`
+	MACAddress    string `json:"mac_address"`
+	LanEnable     int    `json:"lan_enable"`
+	IPv4Enable    int    `json:"ipv4_enable"`
+	IPv4DHCP      int    `json:"ipv4_dhcp_enable"`
+	IPv4Address   string `json:"ipv4_address"`
+	IPv4Subnet    string `json:"ipv4_subnet"`
+	IPv4Gateway   string `json:"ipv4_gateway"`
+	IPv6Enable    int    `json:"ipv6_enable"`
+	IPv6DHCP      int    `json:"ipv6_dhcp_enable"`
+	IPv6Address   string `json:"ipv6_address"`
+	IPv6Prefix    int    `json:"ipv6_prefix"`
+	IPv6Gateway   string `json:"ipv6_gateway"`
+	VLANEnable    int    `json:"vlan_enable"`
+	VLANID        int    `json:"vlan_id"`
+}
+
+// networkLink is part of the payload returned by the network link settings endpoint
+type networkLink struct {
+	ID              int    `json:"id"`
+	InterfaceName   string `json:"interface_name"`
+	AutoNegotiation int    `json:"auto_negotiation"`
+	LinkSpeed       int    `json:"link_speed"` // Mbps
+	DuplexMode      string `json:"duplex_mode"`
+	LinkStatus      int    `json:"link_status"`
+}
+
+// sensor states reported in the sensor_state field of threshold based sensors
+const (
+	sensorStateNormal              = 0x01
+	sensorStateLowerNonCritical    = 0x02
+	sensorStateLowerCritical       = 0x04
+	sensorStateLowerNonRecoverable = 0x08
+	sensorStateUpperNonCritical    = 0x10
+	sensorStateUpperCritical       = 0x20
+	sensorStateUpperNonRecoverable = 0x40
+
+	sensorStatesWarning  = sensorStateLowerNonCritical | sensorStateUpperNonCritical
+	sensorStatesCritical = sensorStateLowerCritical | sensorStateLowerNonRecoverable |
+		sensorStateUpperCritical | sensorStateUpperNonRecoverable
+)
+
+// health returns the sensor health - OK, WARNING or CRITICAL based on the sensor state
+//
+// sensors reporting an unknown state are considered CRITICAL, unless configured as informational.
+// Sensors excluded from the health rollup are always OK.
+func (s *Sensor) health() string {
+	if s.healthIgnored {
+		return healthOK
+	}
+
+	// the chassis intrusion is informational unless the WithChassisIntrusionWarning option is set
+	if s.physicalSecurity() {
+		if s.intrusionIsWarning && s.ChassisIntrusion() {
+			return healthWarning
+		}
+
+		return healthOK
+	}
+
+	switch s.Name {
+	// discrete CPU fault sensors are expected to report a 0 state
+	case "CPU_CATERR", "CPU_THERMTRIP", "CPU_PROCHOT":
+		if s.SensorState == 0 {
+			return healthOK
+		}
+
+		return healthCritical
+	}
+
+	switch {
+	case s.SensorState == sensorStateNormal:
+		return healthOK
+	case s.SensorState&sensorStatesCritical != 0:
+		return healthCritical
+	case s.SensorState&sensorStatesWarning != 0:
+		return healthWarning
+	case s.unknownStateIsInfo:
+		return healthOK
+	default:
+		return healthCritical
+	}
+}
+
+// sensor threshold bits in the readable threshold mask (lower byte of settable_readable_threshMask)
+const (
+	thresholdLowerNonCritical = iota
+	thresholdLowerCritical
+	thresholdLowerNonRecoverable
+	thresholdUpperNonCritical
+	thresholdUpperCritical
+	thresholdUpperNonRecoverable
+)
+
+// thresholdReadable returns true if the given threshold bit is set in the sensor readable threshold mask
+func (s *Sensor) thresholdReadable(bit int) bool {
+	return s.SettableReadableThreshMask&(1<<bit) != 0
 }
 
 // Payload to preseve config when updating the BMC firmware
@@ -103,6 +278,44 @@ type preserveConfig struct {
 	PreserveUser    int `json:"preserve_user"`
 }
 
+// firmwareUpload is the size and SHA-256 checksum of the firmware image uploaded to the BMC
+type firmwareUpload struct {
+	size     int64
+	checksum string
+
+	// the size and checksum of the received image as reported by the BMC, not set when not reported
+	bmcSize     int64
+	bmcChecksum string
+}
+
+// Firmware upload response
+// { "cc": 0 }
+// { "cc": 0, "size": 33554432, "checksum": "8f43...e1c2" }
+type uploadResponse struct {
+	CC       int    `json:"cc"`
+	Size     int64  `json:"size,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// Uploaded firmware verification
+// [ { "id": 1, "current_image_name": "ast2500e", "current_image_version1": "0.01.00", "current_image_version2": "", "new_image_version": "0.03.00", "section_status": 0, "verification_status": 5 } ]
+type firmwareVerification struct {
+	ID                   int    `json:"id"`
+	CurrentImageName     string `json:"current_image_name"`
+	CurrentImageVersion1 string `json:"current_image_version1"`
+	CurrentImageVersion2 string `json:"current_image_version2"`
+	NewImageVersion      string `json:"new_image_version"`
+	SectionStatus        int    `json:"section_status"`
+	VerificationStatus   int    `json:"verification_status"`
+}
+
+// The firmware verification section and verification status reported for a verified image section,
+// any other status is a section the BMC rejected.
+const (
+	firmwareSectionStatusOK      = 0
+	firmwareVerificationVerified = 5
+)
+
 // Firmware flash progress
 // { "id": 1, "action": "Flashing...", "progress": "12% done         ", "state": 0 }
 // { "id": 1, "action": "Flashing...", "progress": "100% done", "state": 0 }
@@ -143,7 +356,7 @@ func (a *ASRockRack) listUsers(ctx context.Context) ([]*UserAccount, error) {
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
 	accounts := []*UserAccount{}
@@ -171,7 +384,22 @@ func (a *ASRockRack) createUpdateUser(ctx context.Context, account *UserAccount)
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return statusCodeError(statusCode)
+	}
+
+	return nil
+}
+
+func (a *ASRockRack) deleteUser(ctx context.Context, id int) error {
+	endpoint := "api/settings/users/" + fmt.Sprintf("%d", id)
+
+	_, statusCode, err := a.queryHTTPS(ctx, endpoint, "DELETE", nil, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return statusCodeError(statusCode)
 	}
 
 	return nil
@@ -187,7 +415,7 @@ func (a *ASRockRack) setFlashMode(ctx context.Context) error {
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return statusCodeError(statusCode)
 	}
 
 	a.resetRequired = true
@@ -204,9 +432,17 @@ func multipartSize(fieldname, filename string) int64 {
 }
 
 // 2 Upload the firmware file
-func (a *ASRockRack) uploadFirmware(ctx context.Context, endpoint string, fwReader io.Reader, fileSize int64) error {
+//
+// The SHA-256 checksum and size of the image are computed as it is streamed to the BMC,
+// along with the image size and checksum the BMC reports in the upload response, if any.
+func (a *ASRockRack) uploadFirmware(ctx context.Context, endpoint string, fwReader io.Reader, fileSize int64) (*firmwareUpload, error) {
 	fieldName, fileName := "fwimage", "image"
-	contentLength := multipartSize(fieldName, fileName) + fileSize
+
+	// a zero content length results in a chunked upload
+	var contentLength int64
+	if fileSize > 0 {
+		contentLength = multipartSize(fieldName, fileName) + fileSize
+	}
 
 	// setup pipe
 	pipeReader, pipeWriter := io.Pipe()
@@ -215,26 +451,32 @@ func (a *ASRockRack) uploadFirmware(ctx context.Context, endpoint string, fwRead
 	// initiate a mulitpart writer
 	form := multipart.NewWriter(pipeWriter)
 
+	hash := sha256.New()
+	upload := &firmwareUpload{}
+
 	errCh := make(chan error, 1)
 	go func() {
-		defer pipeWriter.Close()
-
 		// create form part
 		part, err := form.CreateFormFile(fieldName, fileName)
 		if err != nil {
+			_ = pipeWriter.CloseWithError(err)
 			errCh <- err
 			return
 		}
 
-		// copy from source into form part writer
-		_, err = io.Copy(part, fwReader)
+		// copy from source into form part writer, hashing the image as its streamed
+		upload.size, err = io.Copy(io.MultiWriter(part, hash), fwReader)
 		if err != nil {
+			// fail the request instead of completing the form with a partial image
+			_ = pipeWriter.CloseWithError(err)
 			errCh <- err
 			return
 		}
 
 		// add terminating boundary to multipart form
-		errCh <- form.Close()
+		err = form.Close()
+		_ = pipeWriter.CloseWithError(err)
+		errCh <- err
 	}()
 
 	// multi-part content type
@@ -242,31 +484,58 @@ func (a *ASRockRack) uploadFirmware(ctx context.Context, endpoint string, fwRead
 		"Content-Type": form.FormDataContentType(),
 	}
 
-	// POST payload
-	_, statusCode, err := a.queryHTTPS(ctx, endpoint, "POST", pipeReader, headers, contentLength)
+	// POST payload, the image upload duration depends on the image size and is not bound by the request timeout
+	resp, statusCode, err := a.queryHTTPS(withoutRequestTimeout(ctx), endpoint, "POST", pipeReader, headers, contentLength)
+
+	// unblock the form writer in case the request returned before the image was read
+	pipeReader.Close()
+
+	// a closed pipe indicates the request returned before the image was read, the request error is returned
+	if copyErr := <-errCh; copyErr != nil && copyErr != io.ErrClosedPipe {
+		return nil, fmt.Errorf("error reading firmware image: %w", copyErr)
+	}
+
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
-	return nil
+	upload.checksum = hex.EncodeToString(hash.Sum(nil))
+
+	// the image size and checksum are only included in the response by some BMC firmware revisions
+	reported := &uploadResponse{}
+	if err := json.Unmarshal(resp, reported); err == nil {
+		upload.bmcSize = reported.Size
+		upload.bmcChecksum = reported.Checksum
+	}
+
+	return upload, nil
 }
 
 // 3. Verify uploaded firmware file - to be invoked after uploadFirmware()
-func (a *ASRockRack) verifyUploadedFirmware(ctx context.Context) error {
-	_, statusCode, err := a.queryHTTPS(ctx, "api/maintenance/firmware/verification", "GET", nil, nil, 0)
+//
+// The image sections with the current and uploaded image versions are returned, when included in the response.
+func (a *ASRockRack) verifyUploadedFirmware(ctx context.Context) ([]*firmwareVerification, error) {
+	resp, statusCode, err := a.queryHTTPS(ctx, "api/maintenance/firmware/verification", "GET", nil, nil, 0)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
-	return nil
+	sections := []*firmwareVerification{}
+	// the uploaded image versions are compared to skip an installed version and block a downgrade,
+	// a response which cannot be decoded fails the verification
+	if err := unmarshalTolerant(resp, &sections); err != nil {
+		return nil, fmt.Errorf("error decoding firmware verification response: %w", err)
+	}
+
+	return sections, nil
 }
 
 // 4. Start firmware flashing process - to be invoked after verifyUploadedFirmware
@@ -287,7 +556,7 @@ func (a *ASRockRack) upgradeBMC(ctx context.Context) error {
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return statusCodeError(statusCode)
 	}
 
 	return nil
@@ -301,11 +570,11 @@ func (a *ASRockRack) flashProgress(ctx context.Context, endpoint string) (*upgra
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
 	p := &upgradeProgress{}
-	err = json.Unmarshal(resp, p)
+	err = unmarshalTolerant(resp, p)
 	if err != nil {
 		return nil, err
 	}
@@ -314,18 +583,20 @@ func (a *ASRockRack) flashProgress(ctx context.Context, endpoint string) (*upgra
 }
 
 // Query firmware information from the BMC
-func (a *ASRockRack) firmwareInfo(ctx context.Context) (*firmwareInfo, error) {
-	resp, statusCode, err := a.queryHTTPS(ctx, "api/asrr/fw-info", "GET", nil, nil, 0)
+func (a *ASRockRack) firmwareInfo(ctx context.Context) (_ *firmwareInfo, err error) {
+	defer func() { err = a.endpointError("firmware info", "api/asrr/fw-info", err) }()
+
+	resp, statusCode, err := a.getWithRetry(ctx, "api/asrr/fw-info")
 	if err != nil {
 		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
 	f := &firmwareInfo{}
-	err = json.Unmarshal(resp, f)
+	err = unmarshalTolerant(resp, f)
 	if err != nil {
 		return nil, err
 	}
@@ -334,18 +605,20 @@ func (a *ASRockRack) firmwareInfo(ctx context.Context) (*firmwareInfo, error) {
 }
 
 // Query BIOS/UEFI POST code information from the BMC
-func (a *ASRockRack) postCodeInfo(ctx context.Context) (*biosPOSTCode, error) {
+func (a *ASRockRack) postCodeInfo(ctx context.Context) (_ *biosPOSTCode, err error) {
+	defer func() { err = a.endpointError("POST code", "/api/asrr/getbioscode", err) }()
+
 	resp, statusCode, err := a.queryHTTPS(ctx, "/api/asrr/getbioscode", "GET", nil, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
 	b := &biosPOSTCode{}
-	err = json.Unmarshal(resp, b)
+	err = unmarshalTolerant(resp, b)
 	if err != nil {
 		return nil, err
 	}
@@ -354,89 +627,116 @@ func (a *ASRockRack) postCodeInfo(ctx context.Context) (*biosPOSTCode, error) {
 }
 
 // Query the inventory info endpoint
-func (a *ASRockRack) inventoryInfo(ctx context.Context) ([]*component, error) {
-	resp, statusCode, err := a.queryHTTPS(ctx, "api/asrr/inventory_info", "GET", nil, nil, 0)
+func (a *ASRockRack) inventoryInfo(ctx context.Context) (_ []*component, err error) {
+	defer func() { err = a.endpointError("inventory info", "api/asrr/inventory_info", err) }()
+
+	resp, statusCode, err := a.getWithRetry(ctx, "api/asrr/inventory_info")
 	if err != nil {
 		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
 	components := []*component{}
-	err = json.Unmarshal(resp, &components)
+	err = unmarshalTolerant(resp, &components)
 	if err != nil {
 		return nil, err
 	}
 
+	for _, component := range components {
+		component.normalize()
+	}
+
 	return components, nil
 }
 
 // Query the fru info endpoint
-func (a *ASRockRack) fruInfo(ctx context.Context) ([]*fru, error) {
-	resp, statusCode, err := a.queryHTTPS(ctx, "api/fru", "GET", nil, nil, 0)
+func (a *ASRockRack) fruInfo(ctx context.Context) (_ []*fru, err error) {
+	defer func() { err = a.endpointError("FRU info", fruEndpoint, err) }()
+
+	data, err := a.fruRecords(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+	frus := []*fru{}
+	for _, record := range data {
+		device := &fruDevice{}
+		if rawDevice, exists := record["device"]; exists {
+			err = unmarshalTolerant(rawDevice, device)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, key := range []string{"chassis", "board", "product"} {
+			rawArea, exists := record[key]
+			if !exists {
+				continue
+			}
+
+			f := &fru{}
+			err = unmarshalTolerant(rawArea, f)
+			if err != nil {
+				return nil, err
+			}
+
+			f.normalize()
+			f.Component = key
+			f.DeviceID = device.ID
+			f.DeviceName = device.Name
+
+			frus = append(frus, f)
+		}
 	}
 
-	data := []map[string]*fru{}
-	err = json.Unmarshal(resp, &data)
+	return frus, nil
+}
+
+// Query the network settings endpoint
+func (a *ASRockRack) networkInterfaces(ctx context.Context) (_ []*networkInterface, err error) {
+	defer func() { err = a.endpointError("network settings", "api/settings/network", err) }()
+
+	resp, statusCode, err := a.queryHTTPS(ctx, "api/settings/network", "GET", nil, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(data) == 0 {
-		return nil, fmt.Errorf("no FRU data returned")
+	if statusCode != http.StatusOK {
+		return nil, statusCodeError(statusCode)
 	}
 
-	frus := []*fru{}
-	for key, f := range data[0] {
-		switch key {
-		case "chassis", "board", "product":
-			frus = append(frus, &fru{
-				Component:      key,
-				Version:        f.Version,
-				Length:         f.Length,
-				Language:       f.Language,
-				Manufacturer:   f.Manufacturer,
-				ProductName:    f.ProductName,
-				PartNumber:     f.PartNumber,
-				ProductVersion: f.ProductVersion,
-				SerialNumber:   f.SerialNumber,
-				AssetTag:       f.SerialNumber,
-				FruFileID:      f.FruFileID,
-				CustomFields:   f.CustomFields,
-				Type:           f.Type,
-			})
-		}
+	interfaces := []*networkInterface{}
+	err = unmarshalTolerant(resp, &interfaces)
+	if err != nil {
+		return nil, err
 	}
 
-	return frus, nil
+	return interfaces, nil
 }
 
-// Query the sensors  endpoint
-func (a *ASRockRack) sensors(ctx context.Context) ([]*sensor, error) {
-	resp, statusCode, err := a.queryHTTPS(ctx, "api/sensors", "GET", nil, nil, 0)
+// Query the network link settings endpoint
+func (a *ASRockRack) networkLinks(ctx context.Context) (_ []*networkLink, err error) {
+	defer func() { err = a.endpointError("network link settings", "api/settings/network-link", err) }()
+
+	resp, statusCode, err := a.queryHTTPS(ctx, "api/settings/network-link", "GET", nil, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
-	sensors := []*sensor{}
-	err = json.Unmarshal(resp, &sensors)
+	links := []*networkLink{}
+	err = unmarshalTolerant(resp, &links)
 	if err != nil {
 		return nil, err
 	}
 
-	return sensors, nil
+	return links, nil
 }
 
 // Set the BIOS upgrade configuration
@@ -458,7 +758,7 @@ func (a *ASRockRack) biosUpgradeConfiguration(ctx context.Context) error {
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return statusCodeError(statusCode)
 	}
 
 	f := &firmwareInfo{}
@@ -483,7 +783,7 @@ func (a *ASRockRack) upgradeBIOS(ctx context.Context) error {
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return statusCodeError(statusCode)
 	}
 
 	f := &firmwareInfo{}
@@ -491,18 +791,20 @@ func (a *ASRockRack) upgradeBIOS(ctx context.Context) error {
 }
 
 // Returns the chassis status object which includes the power state
-func (a *ASRockRack) chassisStatusInfo(ctx context.Context) (*chassisStatus, error) {
-	resp, statusCode, err := a.queryHTTPS(ctx, "/api/chassis-status", "GET", nil, nil, 0)
+func (a *ASRockRack) chassisStatusInfo(ctx context.Context) (_ *chassisStatus, err error) {
+	defer func() { err = a.endpointError("chassis status", "api/chassis-status", err) }()
+
+	resp, statusCode, err := a.queryHTTPS(ctx, "api/chassis-status", "GET", nil, nil, 0)
 	if err != nil {
 		return nil, err
 	}
 
 	if statusCode != http.StatusOK {
-		return nil, fmt.Errorf("non 200 response: %d", statusCode)
+		return nil, statusCodeError(statusCode)
 	}
 
 	chassisStatus := chassisStatus{}
-	err = json.Unmarshal(resp, &chassisStatus)
+	err = unmarshalTolerant(resp, &chassisStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -534,14 +836,55 @@ func (a *ASRockRack) httpsLogin(ctx context.Context) error {
 	}
 
 	// Unmarshal login session
-	err = json.Unmarshal(resp, a.loginSession)
+	session := &loginSession{}
+	err = unmarshalTolerant(resp, session)
 	if err != nil {
 		return fmt.Errorf("error unmarshalling response payload: " + err.Error())
 	}
 
+	a.sessionMu.Lock()
+	a.loginSession = session
+	a.sessionMu.Unlock()
+
 	return nil
 }
 
+// csrfToken returns the CSRF token of the current session
+func (a *ASRockRack) csrfToken() string {
+	a.sessionMu.RLock()
+	defer a.sessionMu.RUnlock()
+
+	return a.loginSession.CSRFToken
+}
+
+// sessionOpen returns true when a session has been established with the BMC
+func (a *ASRockRack) sessionOpen() bool {
+	return a.csrfToken() != ""
+}
+
+// clearSession discards the current session
+func (a *ASRockRack) clearSession() {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+
+	a.loginSession = &loginSession{}
+}
+
+// refreshSession re-authenticates when the session identified by the given CSRF token has expired,
+// a session already refreshed by a concurrent request is reused.
+func (a *ASRockRack) refreshSession(ctx context.Context, expiredToken string) error {
+	a.sessionRefreshMu.Lock()
+	defer a.sessionRefreshMu.Unlock()
+
+	if a.csrfToken() != expiredToken {
+		return nil
+	}
+
+	a.log.V(1).Info("session expired, re-authenticating", "ip", a.ip)
+
+	return a.httpsLogin(ctx)
+}
+
 // Close ends the BMC session
 func (a *ASRockRack) httpsLogout(ctx context.Context) error {
 	_, statusCode, err := a.queryHTTPS(ctx, "api/session", "DELETE", nil, nil, 0)
@@ -558,24 +901,95 @@ func (a *ASRockRack) httpsLogout(ctx context.Context) error {
 
 // queryHTTPS run the HTTPS query passing in the required headers
 // the / suffix should be excluded from the URLendpoint
+//
+// When the BMC responds with a 401 on an established session, the session is refreshed
+// and the request is retried once - requests with a payload are only retried if the payload can be rewound.
+// The request duration and outcome is reported to the metrics recorder set with WithMetrics,
+// and a span is created for the request with the tracer set with WithTracer.
+// In dry run mode, requests changing the BMC or host state are logged and not sent.
 // returns - response body, http status code, error if any
-func (a *ASRockRack) queryHTTPS(ctx context.Context, endpoint, method string, payload io.Reader, headers map[string]string, contentLength int64) ([]byte, int, error) {
+func (a *ASRockRack) queryHTTPS(ctx context.Context, endpoint, method string, payload io.Reader, headers map[string]string, contentLength int64) (body []byte, statusCode int, err error) {
+	if a.dryRunSkip(endpoint, method, payload) {
+		return dryRunResponse, http.StatusOK, nil
+	}
+
+	ctx, endSpan := a.startSpan(ctx, method, endpoint)
+
+	start := time.Now()
+	defer func() {
+		a.observeRequest(method, endpoint, time.Since(start), statusCode, err)
+		endSpan(statusCode, err)
+	}()
+
+	token := a.csrfToken()
+
+	body, statusCode, err = a.doHTTPS(ctx, endpoint, method, payload, headers, contentLength, token)
+	if err != nil || statusCode != http.StatusUnauthorized || token == "" || endpoint == "api/session" {
+		return body, statusCode, err
+	}
+
+	if payload != nil {
+		seeker, ok := payload.(io.Seeker)
+		if !ok {
+			return body, statusCode, nil
+		}
+
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return body, statusCode, nil
+		}
+	}
+
+	if err := a.refreshSession(ctx, token); err != nil {
+		return body, statusCode, fmt.Errorf("error refreshing session: %w", err)
+	}
+
+	return a.doHTTPS(ctx, endpoint, method, payload, headers, contentLength, a.csrfToken())
+}
+
+// doHTTPS runs a single HTTPS query with the given CSRF token
+func (a *ASRockRack) doHTTPS(ctx context.Context, endpoint, method string, payload io.Reader, headers map[string]string, contentLength int64, token string) ([]byte, int, error) {
 	var body []byte
 	var err error
 	var req *http.Request
 
-	URL := fmt.Sprintf("https://%s/%s", a.ip, endpoint)
-	req, err = http.NewRequestWithContext(ctx, method, URL, payload)
+	if a.configErr != nil {
+		return nil, 0, a.configErr
+	}
+
+	release, err := acquireRequestSlot(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer release()
+
+	// the request timeout context is derived from the parent, cancelling the parent cancels the request
+	reqCtx := ctx
+	if a.requestTimeout > 0 && !requestTimeoutExempt(ctx) {
+		var cancel context.CancelFunc
+
+		reqCtx, cancel = context.WithTimeout(ctx, a.requestTimeout)
+		defer cancel()
+	}
+
+	URL := a.baseURL + endpoint
+	req, err = http.NewRequestWithContext(reqCtx, method, URL, payload)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// add headers
-	req.Header.Add("X-CSRFTOKEN", a.loginSession.CSRFToken)
+	a.setRequestHeaders(ctx, req.Header)
+	req.Header.Add("X-CSRFTOKEN", token)
 	for k, v := range headers {
 		req.Header.Add(k, v)
 	}
 
+	// compressed responses are decoded by decodeBody, setting the header disables the transport gzip decoding
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+
 	// Content-Length headers are ignored, unless defined in this manner
 	// https://go.googlesource.com/go/+/go1.16/src/net/http/request.go#161
 	// https://go.googlesource.com/go/+/go1.16/src/net/http/request.go#88
@@ -591,7 +1005,7 @@ func (a *ASRockRack) queryHTTPS(ctx context.Context, endpoint, method string, pa
 
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
-		return body, 0, err
+		return body, 0, a.requestTimeoutError(ctx, reqCtx, err)
 	}
 
 	// debug dump response
@@ -600,12 +1014,109 @@ func (a *ASRockRack) queryHTTPS(ctx context.Context, endpoint, method string, pa
 		a.log.V(3).Info("trace", "responseDump", string(respDump))
 	}
 
-	body, err = io.ReadAll(resp.Body)
+	defer resp.Body.Close()
+
+	body, err = decodeBody(resp)
 	if err != nil {
-		return body, 0, err
+		return body, 0, a.requestTimeoutError(ctx, reqCtx, err)
 	}
 
-	defer resp.Body.Close()
-
 	return body, resp.StatusCode, nil
 }
+
+// requestTimeoutError returns an ErrRequestTimeout error when the request context timed out before the parent context
+func (a *ASRockRack) requestTimeoutError(ctx, reqCtx context.Context, err error) error {
+	if ctx.Err() == nil && reqCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w: %s: %s", ErrRequestTimeout, a.requestTimeout, err)
+	}
+
+	return err
+}
+
+// requestTimeoutExemptKey is the context key marking requests not subject to the request timeout
+type requestTimeoutExemptKey struct{}
+
+// withoutRequestTimeout returns a context for requests not subject to the request timeout - firmware image uploads
+func withoutRequestTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestTimeoutExemptKey{}, true)
+}
+
+// requestTimeoutExempt returns true when the request context is marked as not subject to the request timeout
+func requestTimeoutExempt(ctx context.Context) bool {
+	exempt, _ := ctx.Value(requestTimeoutExemptKey{}).(bool)
+	return exempt
+}
+
+// requestLimitKey is the context key of the semaphore limiting the BMC requests in flight
+type requestLimitKey struct{}
+
+// withRequestLimit returns a context limiting the BMC requests in flight at once to the limit, unlimited when 0
+func withRequestLimit(ctx context.Context, limit int) context.Context {
+	if limit <= 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, requestLimitKey{}, make(chan struct{}, limit))
+}
+
+// acquireRequestSlot waits for a request slot when the context limits the requests in flight,
+// the returned function releases the slot once the request completed.
+func acquireRequestSlot(ctx context.Context) (release func(), err error) {
+	slots, ok := ctx.Value(requestLimitKey{}).(chan struct{})
+	if !ok {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// gzipMagic is the gzip header magic number
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decodeBody reads the response body, decompressing gzip and deflate encoded responses
+//
+// Some firmware revisions return gzip compressed JSON without the Content-Encoding header,
+// JSON responses starting with the gzip magic number are decompressed as well.
+func decodeBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return body, err
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" && strings.Contains(resp.Header.Get("Content-Type"), "json") && bytes.HasPrefix(body, gzipMagic) {
+		encoding = "gzip"
+	}
+
+	var reader io.ReadCloser
+
+	switch encoding {
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding gzip response: %w", err)
+		}
+	case "deflate":
+		// deflate is the zlib format, though some servers send raw deflate data
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			reader = flate.NewReader(bytes.NewReader(body))
+		}
+	default:
+		return body, nil
+	}
+
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s response: %w", encoding, err)
+	}
+
+	return decoded, nil
+}
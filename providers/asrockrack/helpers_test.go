@@ -1,9 +1,24 @@
 package asrockrack
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
 
+	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+
 	"gopkg.in/go-playground/assert.v1"
 )
 
@@ -43,9 +58,10 @@ func Test_inventoryInfo(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 
-	assert.Equal(t, 6, len(inventory))
+	assert.Equal(t, 7, len(inventory))
 	assert.Equal(t, "CPU", inventory[0].DeviceType)
 	assert.Equal(t, "Storage device", inventory[5].DeviceType)
+	assert.Equal(t, "GPU", inventory[6].DeviceType)
 }
 func Test_fruInfo(t *testing.T) {
 	err := aClient.httpsLogin(context.TODO())
@@ -67,7 +83,7 @@ func Test_sensors(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 
-	sensors, err := aClient.sensors(context.TODO())
+	sensors, err := aClient.Sensors(context.TODO())
 	if err != nil {
 		t.Fatal(err.Error())
 	}
@@ -112,3 +128,257 @@ func Test_chassisStatus(t *testing.T) {
 
 	assert.Equal(t, expected, info)
 }
+
+// mockExpiringSessionBMC returns a BMC mock which expires the session after the given number of requests
+func mockExpiringSessionBMC(requestsPerSession int) (server *httptest.Server, logins func() int) {
+	var mu sync.Mutex
+	var sessions, requests int
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/session", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		sessions++
+		requests = 0
+
+		_, _ = w.Write([]byte(fmt.Sprintf(`{ "ok": 0, "privilege": 4, "csrftoken": "token-%d" }`, sessions)))
+	})
+
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("X-CSRFTOKEN") != fmt.Sprintf("token-%d", sessions) || requests >= requestsPerSession {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		requests++
+
+		_, _ = w.Write([]byte(`{ "power_status": 1, "led_status": 0 }`))
+	})
+
+	handler.HandleFunc("/api/actions/power", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("X-CSRFTOKEN") != fmt.Sprintf("token-%d", sessions) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		buf := new(bytes.Buffer)
+		_, _ = buf.ReadFrom(r.Body)
+		if buf.String() != `{"power_command":1}` {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	logins = func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return sessions
+	}
+
+	return httptest.NewTLSServer(handler), logins
+}
+
+func Test_queryHTTPSSessionRefresh(t *testing.T) {
+	server, logins := mockExpiringSessionBMC(2)
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	client := NewWithOptions(u.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+	if err := client.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		state, err := client.PowerStateGet(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "On", state)
+	}
+
+	// 5 requests at 2 requests per session
+	assert.Equal(t, 3, logins())
+	assert.Equal(t, "token-3", client.csrfToken())
+
+	// a stale token is rejected, the payload is rewound and replayed with the refreshed session
+	client.loginSession.CSRFToken = "token-2"
+
+	ok, err := client.PowerSet(ctx, "on")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 4, logins())
+}
+
+func Test_queryHTTPSNoSessionNoRefresh(t *testing.T) {
+	server, logins := mockExpiringSessionBMC(2)
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewWithOptions(u.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, statusCode, err := client.queryHTTPS(context.Background(), "api/chassis-status", "GET", nil, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+	assert.Equal(t, 0, logins())
+}
+
+func Test_statusCodeError(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		kind       error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusNotFound, ErrUnsupported},
+		{http.StatusMethodNotAllowed, ErrUnsupported},
+		{http.StatusNotImplemented, ErrUnsupported},
+		{http.StatusTooManyRequests, ErrBMCBusy},
+		{http.StatusServiceUnavailable, ErrBMCBusy},
+		{http.StatusInternalServerError, nil},
+		{http.StatusBadRequest, nil},
+	}
+
+	kinds := []error{ErrUnauthorized, ErrUnsupported, ErrBMCBusy}
+
+	for _, tc := range testCases {
+		t.Run(http.StatusText(tc.statusCode), func(t *testing.T) {
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/fru", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			u, _ := url.Parse(server.URL)
+			client := NewWithOptions(u.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+			_, err := client.fruInfo(context.TODO())
+			assert.Equal(t, true, errors.Is(err, bmclibErrs.ErrNon200Response))
+
+			for _, kind := range kinds {
+				assert.Equal(t, kind == tc.kind, errors.Is(err, kind))
+			}
+		})
+	}
+
+	// the wrapped error is matched along with the status code error kind
+	err := wrapStatusCodeError(ErrSELRead, http.StatusServiceUnavailable)
+	assert.Equal(t, true, errors.Is(err, ErrSELRead))
+	assert.Equal(t, true, errors.Is(err, ErrBMCBusy))
+	assert.Equal(t, "non 200 response: 503: BMC busy: error reading the system event log", err.Error())
+}
+
+func Test_wrapCause(t *testing.T) {
+	cause := &EndpointError{Op: "sensors", URL: "https://127.0.0.1/api/sensors", Err: context.DeadlineExceeded}
+
+	err := wrapCause(ErrInventoryTimeout, cause)
+	assert.Equal(t, true, errors.Is(err, ErrInventoryTimeout))
+	assert.Equal(t, true, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, false, errors.Is(err, ErrSELClear))
+
+	var endpointErr *EndpointError
+	assert.Equal(t, true, errors.As(err, &endpointErr))
+	assert.Equal(t, "sensors", endpointErr.Op)
+
+	// formatted as errors.Wrap(kind, cause.Error())
+	assert.Equal(t, errors.Wrap(ErrInventoryTimeout, cause.Error()).Error(), err.Error())
+}
+
+func Test_queryHTTPSIPv6(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 loopback not available: " + err.Error())
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{ "power_status": 1, "led_status": 0 }`))
+	})
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.StartTLS()
+	defer server.Close()
+
+	// the bracketed host and port - [::1]:<port>
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	state, err := client.PowerStateGet(context.TODO())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "On", state)
+}
+
+func Test_inventoryInfoCompressed(t *testing.T) {
+	compress := map[string]func(*bytes.Buffer) io.WriteCloser{
+		"gzip":        func(b *bytes.Buffer) io.WriteCloser { return gzip.NewWriter(b) },
+		"deflate":     func(b *bytes.Buffer) io.WriteCloser { return zlib.NewWriter(b) },
+		"raw deflate": func(b *bytes.Buffer) io.WriteCloser { w, _ := flate.NewWriter(b, flate.DefaultCompression); return w },
+		"no header":   func(b *bytes.Buffer) io.WriteCloser { return gzip.NewWriter(b) },
+	}
+
+	for name, writer := range compress {
+		t.Run(name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			w := writer(buf)
+			_, _ = w.Write(inventoryinfoResponse)
+			_ = w.Close()
+
+			var acceptEncoding string
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/asrr/inventory_info", func(w http.ResponseWriter, r *http.Request) {
+				acceptEncoding = r.Header.Get("Accept-Encoding")
+
+				w.Header().Set("Content-Type", "application/json")
+				switch name {
+				case "gzip":
+					w.Header().Set("Content-Encoding", "gzip")
+				case "deflate", "raw deflate":
+					w.Header().Set("Content-Encoding", "deflate")
+				}
+
+				_, _ = w.Write(buf.Bytes())
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			inventory, err := client.inventoryInfo(context.TODO())
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			assert.Equal(t, "gzip, deflate", acceptEncoding)
+			assert.Equal(t, 7, len(inventory))
+			assert.Equal(t, "CPU", inventory[0].DeviceType)
+		})
+	}
+}
@@ -2,13 +2,107 @@ package asrockrack
 
 import (
 	"context"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
 
 	"github.com/bmc-toolbox/bmclib/v2/constants"
 	"github.com/bmc-toolbox/common"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+const (
+	healthOK       = "OK"
+	healthWarning  = "WARNING"
+	healthCritical = "CRITICAL"
+
+	// metadataIntelMEVersion is the device metadata key for the Intel Management Engine firmware version
+	metadataIntelMEVersion = "intel_me.version"
+)
+
+var (
+	// matches the memory module speed - "2666 MT/s", "3200 MHz"
+	memorySpeedRegexp = regexp.MustCompile(`(?i)(\d+)\s?(MT/s|MHz)`)
+
+	// matches the memory module size - "16GB", "512 MB"
+	memorySizeRegexp = regexp.MustCompile(`(?i)(\d+)\s?(MB|GB|TB)\b`)
+
+	// matches the drive capacity - "480GB", "1.92 TB"
+	driveCapacityRegexp = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s?(GB|TB)\b`)
+
+	// matches the capacity in an Intel drive part number - "SSDSC2KB480G8", "SSDPE2KX019T8"
+	intelDriveCapacityRegexp = regexp.MustCompile(`(?i)SSD[A-Z]{2}\d[A-Z]{2}(\d{3})([GT])\d`)
+
+	// matches the CPU core count - "8 Cores", "32-Core Processor", "Cores: 8"
+	cpuCoresRegexp = regexp.MustCompile(`(?i)(?:(\d+)[\s-]?cores?\b|\bcores?\s?[:=]?\s?(\d+))`)
+
+	// matches the CPU thread count - "16 Threads", "Threads: 16"
+	cpuThreadsRegexp = regexp.MustCompile(`(?i)(?:(\d+)[\s-]?threads?\b|\bthreads?\s?[:=]?\s?(\d+))`)
+
+	// matches the CPU clock speed - "3400 MHz", "@ 3.40GHz"
+	cpuClockRegexp = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s?(MHz|GHz)\b`)
+
+	// matches the wattage in a PSU model or part number - "CRPS 800W", "PSU-1200W-R"
+	psuWattsRegexp = regexp.MustCompile(`(?i)(\d{3,4})\s?W\b`)
+)
+
+// InventorySection identifies a section of the inventory which can be skipped with the WithInventorySkip option
+type InventorySection string
+
+const (
+	// InventorySectionFRU is the board, chassis and product FRU attributes
+	InventorySectionFRU InventorySection = "fru"
+	// InventorySectionComponents is the CPU, memory, drive, GPU, TPM, NIC and PCIe slot inventory,
+	// the firmware versions are collected regardless.
+	InventorySectionComponents InventorySection = "components"
+	// InventorySectionNIC is the BMC network interfaces, reported as the BMC NIC
+	InventorySectionNIC InventorySection = "nic"
+	// InventorySectionPSU is the power supply FRU and sensor attributes
+	InventorySectionPSU InventorySection = "psu"
+	// InventorySectionHealth is the sensor based device and component health, the POST code and the power state
+	InventorySectionHealth InventorySection = "health"
+	// InventorySectionStorage is the storage controllers, backplanes, the drive virtual disk membership, bay and SMART data
+	InventorySectionStorage InventorySection = "storage"
+	// InventorySectionBIOS is the BIOS boot mode and UEFI support, read from the BIOS settings
+	InventorySectionBIOS InventorySection = "bios"
 )
 
 // Inventory returns hardware and firmware inventory
+//
+// The inventory sections are collected concurrently, each into a device of its own
+// which are merged in the section order once all sections are collected.
+//
+// A failed BMC request is returned as an EndpointError, identifying the operation and the request URL.
+//
+// When the context deadline is exceeded during collection, the partially populated device
+// is returned along with an ErrInventoryTimeout error, wrapping the error the collection failed with -
+// errors.As and errors.Is match the EndpointError and context.DeadlineExceeded.
+//
+// With the WithBestEffortInventory option, the collection continues past failed inventory sections,
+// the populated device is returned along with a multierror listing the failed sections.
+//
+// Sections skipped with the WithInventorySkip option are not queried and their attributes are left empty.
 func (a *ASRockRack) Inventory(ctx context.Context) (device *common.Device, err error) {
+	events, err := a.InventoryStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for event := range events {
+		if event.Type == InventoryEventDone {
+			device, err = event.Device, event.Err
+		}
+	}
+
+	return device, err
+}
+
+// collectInventory collects the inventory sections, the components are passed to emit as each section is collected
+func (a *ASRockRack) collectInventory(ctx context.Context, emit func(InventoryEvent)) (device *common.Device, err error) {
 	// initialize device to be populated with inventory
 	newDevice := common.NewDevice()
 	device = &newDevice
@@ -16,84 +110,465 @@ func (a *ASRockRack) Inventory(ctx context.Context) (device *common.Device, err
 
 	device.Metadata = map[string]string{}
 
-	// populate device BMC, BIOS component attributes
-	err = a.fruAttributes(ctx, device)
-	if err != nil {
-		return nil, err
+	// the sensors collected by the health section, for correlation with the components
+	var sensors []*Sensor
+
+	// the BMC NIC collected by the nic section, set on the BMC component collected by the fru section
+	var bmcNIC *common.NIC
+
+	// the FRU records collected by the fru section, for the psu section to build the PSUs from
+	var (
+		frus          []*fru
+		frusErr       error
+		frusCollected = make(chan struct{})
+	)
+
+	// the storage controllers, backplanes and drive SMART data collected by the storage section, to link the drives
+	var (
+		controllers []*storageController
+		backplanes  []*backplane
+		smart       []*driveSMART
+	)
+
+	allSections := []struct {
+		name    string
+		collect func(context.Context, *common.Device) error
+	}{
+		// populate device BMC, BIOS component attributes
+		{"fru", func(ctx context.Context, device *common.Device) error {
+			defer close(frusCollected)
+
+			frus, frusErr = a.fruAttributes(ctx, device)
+			return frusErr
+		}},
+		// populate device System components attributes, the firmware versions are always collected
+		{"system", a.systemAttributes},
+		// populate the BMC NIC from the BMC network interfaces, older firmware may not expose the endpoints
+		{"nic", func(ctx context.Context, device *common.Device) error {
+			bmcNIC = a.bmcNIC(ctx)
+			return nil
+		}},
+		// populate device PSUs based on the FRU records, the PSU health is set from the health section sensors
+		{"psu", func(ctx context.Context, device *common.Device) error {
+			if a.inventorySkip[InventorySectionFRU] {
+				return a.psuAttributes(ctx, device)
+			}
+
+			select {
+			case <-frusCollected:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			if frusErr != nil {
+				return frusErr
+			}
+
+			device.PSUs = append(device.PSUs, psusFromFRUs(frus)...)
+
+			return nil
+		}},
+		// populate device storage controllers and backplanes, boards without a RAID controller or backplane list none
+		{"storage", func(ctx context.Context, device *common.Device) (err error) {
+			controllers, err = a.storageControllerAttributes(ctx, device)
+			if err != nil {
+				return err
+			}
+
+			backplanes, err = a.backplaneAttributes(ctx, device)
+			if err != nil {
+				return err
+			}
+
+			smart, err = a.drivesSMART(ctx)
+			return err
+		}},
+		// populate device BIOS boot mode metadata, older firmware may not expose the BIOS settings
+		{"bios", a.biosBootModeAttributes},
+		// populate device health based on sensor readings
+		{"health", func(ctx context.Context, device *common.Device) (err error) {
+			sensors, err = a.deviceHealth(ctx, device)
+			for _, sensor := range sensors {
+				s := *sensor
+				emit(InventoryEvent{Type: InventoryEventSensor, Section: InventorySectionHealth, Component: &s})
+			}
+
+			return err
+		}},
 	}
 
-	// populate device System components attributes
-	err = a.systemAttributes(ctx, device)
-	if err != nil {
-		return nil, err
+	sections := allSections[:0]
+	for _, section := range allSections {
+		if !a.inventorySkip[InventorySection(section.name)] {
+			sections = append(sections, section)
+		}
 	}
 
-	// populate device health based on sensor readings
-	err = a.systemHealth(ctx, device)
-	if err != nil {
-		return nil, err
+	// a failed section cancels the collection of the remaining sections, unless in best effort mode
+	sectionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the sections share the limit of the BMC requests in flight
+	sectionCtx = withRequestLimit(sectionCtx, a.inventoryConcurrency)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	collected := make([]*common.Device, len(sections))
+	errs := make([]error, len(sections))
+
+	for idx, section := range sections {
+		collected[idx] = &common.Device{
+			Common: common.Common{
+				Metadata: map[string]string{},
+				Status:   &common.Status{},
+			},
+		}
+
+		wg.Add(1)
+
+		go func(idx int, name string, collect func(context.Context, *common.Device) error) {
+			defer wg.Done()
+
+			errs[idx] = collect(sectionCtx, collected[idx])
+			emitSection(emit, InventorySection(name), collected[idx], errs[idx])
+
+			if errs[idx] == nil || a.inventoryBestEffort {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if firstErr == nil {
+				firstErr = errs[idx]
+				cancel()
+			}
+		}(idx, section.name, section.collect)
+	}
+
+	wg.Wait()
+
+	for _, sectionDevice := range collected {
+		mergeDevice(device, sectionDevice)
+	}
+
+	inheritSystemAttributes(device)
+	bmcNICAttributes(device, bmcNIC)
+	linkStorageDrives(device, controllers)
+	linkBackplaneDrives(device, backplanes)
+	drivesSMARTAttributes(device, smart)
+	componentsHealth(device, sensors)
+	backplaneTemperatures(device, sensors)
+	firmwareBanksHealth(device, a.firmwareDesired, a.firmwareBankWarning)
+
+	if firstErr != nil {
+		return inventoryError(ctx, device, firstErr)
+	}
+
+	var sectionErrs *multierror.Error
+	for idx, section := range sections {
+		if errs[idx] == nil {
+			continue
+		}
+
+		a.log.V(2).Error(errs[idx], "inventory section collection failed, continuing", "section", section.name)
+		sectionErrs = multierror.Append(sectionErrs, errors.Wrap(errs[idx], section.name))
+	}
+
+	if sectionErrs != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return device, wrapCause(ErrInventoryTimeout, sectionErrs)
+		}
+
+		return device, sectionErrs
 	}
 
 	return device, nil
 }
 
+// mergeDevice merges the attributes collected by an inventory section into the device
+func mergeDevice(device, section *common.Device) {
+	if section.Vendor != "" {
+		device.Vendor = section.Vendor
+	}
+
+	if section.Model != "" {
+		device.Model = section.Model
+	}
+
+	if section.Serial != "" {
+		device.Serial = section.Serial
+	}
+
+	for k, v := range section.Metadata {
+		device.Metadata[k] = v
+	}
+
+	if section.Status != nil && *section.Status != (common.Status{}) {
+		device.Status = section.Status
+	}
+
+	if section.BIOS != nil {
+		device.BIOS = section.BIOS
+	}
+
+	if section.BMC != nil {
+		device.BMC = section.BMC
+	}
+
+	device.CPLDs = append(device.CPLDs, section.CPLDs...)
+	device.TPMs = append(device.TPMs, section.TPMs...)
+	device.GPUs = append(device.GPUs, section.GPUs...)
+	device.CPUs = append(device.CPUs, section.CPUs...)
+	device.Memory = append(device.Memory, section.Memory...)
+	device.NICs = append(device.NICs, section.NICs...)
+	device.Drives = append(device.Drives, section.Drives...)
+	device.StorageControllers = append(device.StorageControllers, section.StorageControllers...)
+	device.PSUs = append(device.PSUs, section.PSUs...)
+	device.Enclosures = append(device.Enclosures, section.Enclosures...)
+}
+
+// inheritSystemAttributes sets the system vendor and model on the BIOS, BMC and CPLD components
+// which are collected independent of the FRU.
+func inheritSystemAttributes(device *common.Device) {
+	inherit := func(c *common.Common) {
+		if c.Vendor == "" {
+			c.Vendor = device.Vendor
+		}
+
+		if c.Model == "" {
+			c.Model = device.Model
+		}
+	}
+
+	if device.BIOS != nil {
+		inherit(&device.BIOS.Common)
+	}
+
+	if device.BMC != nil {
+		inherit(&device.BMC.Common)
+	}
+
+	for _, cpld := range device.CPLDs {
+		inherit(&cpld.Common)
+	}
+}
+
+// inventoryError returns the partial device and an ErrInventoryTimeout error wrapping the error if the context deadline
+// was exceeded, any other error is returned as is, without the device.
+func inventoryError(ctx context.Context, device *common.Device, err error) (*common.Device, error) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return device, wrapCause(ErrInventoryTimeout, err)
+	}
+
+	return nil, err
+}
+
 // systemHealth collects system health information based on the sensors data
 func (a *ASRockRack) systemHealth(ctx context.Context, device *common.Device) error {
-	sensors, err := a.sensors(ctx)
+	sensors, err := a.deviceHealth(ctx, device)
 	if err != nil {
 		return err
 	}
 
-	ok := true
-	device.Status.Health = "OK"
+	componentsHealth(device, sensors)
+
+	return nil
+}
+
+// deviceHealth collects the device health and POST code, the sensors are returned for correlation with the components
+func (a *ASRockRack) deviceHealth(ctx context.Context, device *common.Device) ([]*Sensor, error) {
+	sensors, err := a.Sensors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sensorsHealth(device, sensors)
+	chassisIntrusionMetadata(device, sensors)
+	powerConsumptionMetadata(device, sensors)
+
+	// the power state is recorded to distinguish a powered off node, whose sensors may not be readable
+	state, err := a.PowerStateGet(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect power state, skipped")
+	} else {
+		device.Metadata["power.state"] = state
+	}
+
+	if a.bmcUptimeMetadata {
+		a.setBMCUptimeMetadata(ctx, device)
+	}
+
+	// we don't want to fail inventory collection hence ignore POST code collection error
+	device.Status.PostCodeStatus, device.Status.PostCode, err = a.PostCode(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect POST code, skipped")
+		return sensors, nil
+	}
+
+	if description := PostCodeDescription(device.Status.PostCode); description != constants.POSTCodeUnknown {
+		device.Metadata["post_code.description"] = description
+	}
+
+	return sensors, nil
+}
+
+// sensorsHealth sets the device health based on the sensor states
+//
+// the device health is set to the most severe health of all sensors,
+// the names of all sensors in a non OK state are listed in the device Status.State
+// and the health.failing_sensors metadata key as a comma separated list.
+func sensorsHealth(device *common.Device, sensors []*Sensor) {
+	failing := []string{}
+
+	device.Status.Health = healthOK
 	for _, sensor := range sensors {
-		switch sensor.Name {
-		case "CPU_CATERR", "CPU_THERMTRIP", "CPU_PROCHOT":
-			if sensor.SensorState != 0 {
-				ok = false
-				device.Status.State = sensor.Name
-				break
-			}
-		default:
-			if sensor.SensorState != 1 {
-				ok = false
-				device.Status.State = sensor.Name
-				break
-			}
+		health := sensor.health()
+
+		if health != healthOK {
+			failing = append(failing, sensor.Name)
+		}
+
+		if healthSeverity(health) > healthSeverity(device.Status.Health) {
+			device.Status.Health = health
+		}
+
+		switch sensor.Type {
+		case "fan":
+			fanMetadata(device, sensor, health)
+		case "temperature":
+			temperatureMetadata(device, sensor)
 		}
 	}
 
-	if !ok {
-		device.Status.Health = "CRITICAL"
+	if len(failing) > 0 {
+		device.Status.State = strings.Join(failing, ",")
+		device.Metadata["health.failing_sensors"] = strings.Join(failing, ",")
 	}
+}
 
-	// we don't want to fail inventory collection hence ignore POST code collection error
-	device.Status.PostCodeStatus, device.Status.PostCode, _ = a.PostCode(ctx)
+// chassisIntrusionMetadata sets the chassis.intrusion metadata to true or false
+// when the BMC exposes a chassis intrusion sensor, true when any intrusion sensor is asserted.
+func chassisIntrusionMetadata(device *common.Device, sensors []*Sensor) {
+	for _, sensor := range sensors {
+		if !sensor.physicalSecurity() || sensor.Accessible != 0 {
+			continue
+		}
 
-	return nil
+		if sensor.ChassisIntrusion() {
+			device.Metadata["chassis.intrusion"] = "true"
+			return
+		}
+
+		device.Metadata["chassis.intrusion"] = "false"
+	}
+}
+
+// healthSeverity returns an int value to compare health values by their severity
+func healthSeverity(health string) int {
+	switch health {
+	case healthOK:
+		return 0
+	case healthWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// fanMetadata sets the fan RPM reading and health in the device metadata
+//
+// sensor.fan.<name> = <RPM>, sensor.fan.<name>.health = <OK|WARNING|CRITICAL>
+func fanMetadata(device *common.Device, sensor *Sensor, health string) {
+	key := "sensor.fan." + sensorKey(sensor.Name)
+
+	device.Metadata[key] = strconv.FormatFloat(sensor.Reading, 'f', -1, 64)
+	device.Metadata[key+".health"] = health
+}
+
+// temperatureMetadata sets the temperature reading, unit and the readable thresholds in the device metadata
+//
+// sensor.temp.<name> = <reading>, sensor.temp.<name>.unit = °C, sensor.temp.<name>.upper_critical = <threshold>
+func temperatureMetadata(device *common.Device, sensor *Sensor) {
+	// the sensor is not present or its reading is not available
+	if sensor.Accessible != 0 {
+		return
+	}
+
+	key := "sensor.temp." + sensorKey(sensor.Name)
+
+	device.Metadata[key] = strconv.FormatFloat(sensor.Reading, 'f', -1, 64)
+	device.Metadata[key+".unit"] = sensor.Unit
+
+	for name, value := range sensor.ReadableThresholds() {
+		device.Metadata[key+"."+name] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+}
+
+// sensorKey returns the sensor name formatted for use in a metadata key - "IPB FAN1" -> "ipb_fan1"
+func sensorKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
 }
 
 // fruAttributes collects chassis information
-func (a *ASRockRack) fruAttributes(ctx context.Context, device *common.Device) error {
+//
+// The first board FRU identifies the system, each chassis FRU is included as an enclosure,
+// this allows for multi-node chassis which report multiple FRU devices.
+// The FRU records are returned for the PSUs to be built from.
+func (a *ASRockRack) fruAttributes(ctx context.Context, device *common.Device) ([]*fru, error) {
 	components, err := a.fruInfo(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if len(components) == 0 {
+		return nil, errors.New("no FRU information found")
 	}
 
+	var boardFound, productFound bool
+
 	for _, component := range components {
+		// PSU FRU devices are collected in the psu section
+		if isPSUFRU(component) {
+			continue
+		}
+
 		switch component.Component {
 		case "board":
-			device.Vendor = component.Manufacturer
-			device.Model = component.ProductName
+			if boardFound {
+				continue
+			}
+
+			boardFound = true
+
+			device.Vendor = constants.NormalizeVendor(component.Manufacturer)
+			device.Model = constants.NormalizeModel(component.ProductName)
+			device.Metadata["board.manufacturer_raw"] = component.Manufacturer
 			device.Serial = component.SerialNumber
 		case "chassis":
-			device.Enclosures = append(device.Enclosures, &common.Enclosure{
+			enclosure := &common.Enclosure{
 				Common: common.Common{
 					Serial:      component.SerialNumber,
 					Description: component.Type,
 				},
-			})
+			}
+
+			if component.DeviceID != 0 {
+				enclosure.ID = component.DeviceName
+			}
+
+			device.Enclosures = append(device.Enclosures, enclosure)
 		case "product":
+			if productFound {
+				continue
+			}
+
+			productFound = true
+
 			device.Metadata["product.manufacturer"] = component.Manufacturer
 			device.Metadata["product.name"] = component.ProductName
 			device.Metadata["product.part_number"] = component.PartNumber
@@ -102,7 +577,7 @@ func (a *ASRockRack) fruAttributes(ctx context.Context, device *common.Device) e
 		}
 	}
 
-	return nil
+	return components, nil
 }
 
 // systemAttributes collects system component attributes
@@ -114,88 +589,890 @@ func (a *ASRockRack) systemAttributes(ctx context.Context, device *common.Device
 
 	device.BIOS = &common.BIOS{
 		Common: common.Common{
-			Vendor:   device.Vendor,
-			Model:    device.Model,
-			Firmware: &common.Firmware{Installed: fwInfo.BIOSVersion},
+			Vendor: device.Vendor,
+			Model:  device.Model,
+			Firmware: dualImageFirmware(
+				fwInfo.BIOSVersion,
+				fwInfo.BIOSBuildDate,
+				fwInfo.BIOSActiveImage,
+				fwInfo.BIOSPrimaryVersion,
+				fwInfo.BIOSBackupVersion,
+			),
 		},
 	}
 
 	device.BMC = &common.BMC{
 		Common: common.Common{
-			Vendor:   device.Vendor,
-			Model:    device.Model,
-			Firmware: &common.Firmware{Installed: fwInfo.BMCVersion},
+			Vendor: device.Vendor,
+			Model:  device.Model,
+			Firmware: dualImageFirmware(
+				fwInfo.BMCVersion,
+				fwInfo.BMCBuildDate,
+				fwInfo.BMCActiveImage,
+				fwInfo.BMCPrimaryVersion,
+				fwInfo.BMCBackupVersion,
+			),
 		},
 	}
 
-	if fwInfo.CPLDVersion != "N/A" {
-		device.CPLDs = append(device.CPLDs, &common.CPLD{
-			Common: common.Common{
-				Vendor:   device.Vendor,
-				Model:    device.Model,
-				Firmware: &common.Firmware{Installed: fwInfo.CPLDVersion},
-			},
-		})
+	device.CPLDs = append(device.CPLDs, cplds(device, fwInfo.CPLDVersion)...)
+
+	nodeIdentityMetadata(device, nodeIdentity(fwInfo))
+
+	if !versionPlaceholder(fwInfo.MEVersion) {
+		device.Metadata[metadataIntelMEVersion] = fwInfo.MEVersion
 	}
 
-	device.Metadata["node_id"] = fwInfo.NodeID
+	if a.inventorySkip[InventorySectionComponents] {
+		return nil
+	}
 
 	components, err := a.inventoryInfo(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, component := range components {
-		switch component.DeviceType {
-		case "CPU":
-			device.CPUs = append(device.CPUs,
-				&common.CPU{
-					Common: common.Common{
-						Vendor: component.ProductManufacturerName,
-						Model:  component.ProductName,
-						Firmware: &common.Firmware{
-							Installed: fwInfo.MicrocodeVersion,
-							Metadata: map[string]string{
-								"Intel_ME_version": fwInfo.MEVersion,
-							},
-						},
-					},
-				},
-			)
-		case "Memory":
-			device.Memory = append(device.Memory,
-				&common.Memory{
-					Common: common.Common{
-						Vendor:      component.ProductManufacturerName,
-						Serial:      component.ProductSerialNumber,
-						Description: component.ProductExtra,
-					},
+	componentAttributes(device, fwInfo, components)
+	pcieAttributes(device, components)
 
-					PartNumber: component.ProductPartNumber,
-					Type:       component.DeviceName,
-				},
-			)
+	// retained for compatibility, the Intel ME version was previously included on each CPU
+	if a.cpuMEVersionMetadata {
+		for _, cpu := range device.CPUs {
+			cpu.Firmware.Metadata = map[string]string{"Intel_ME_version": fwInfo.MEVersion}
+		}
+	}
 
-		case "Storage device":
-			var vendor string
+	return nil
+}
 
-			if component.ProductManufacturerName == "N/A" &&
-				component.ProductPartNumber != "N/A" {
-				vendor = constants.VendorFromProductName(component.ProductPartNumber)
-			}
+// firmware image banks reported in the firmware info active image fields
+const (
+	firmwareImagePrimary = 1
+	firmwareImageBackup  = 2
+)
 
-			device.Drives = append(device.Drives,
-				&common.Drive{
-					Common: common.Common{
-						Vendor:      vendor,
-						Serial:      component.ProductSerialNumber,
-						ProductName: component.ProductPartNumber,
-					},
-				},
-			)
+// dualImageFirmware returns the installed firmware along with the build date and the firmware image bank details
+// in the firmware metadata, when reported by the BMC.
+//
+// build_date = <date>, active_bank = primary|backup, primary_version = <version>, backup_version = <version>
+//
+// A node running from the backup bank has failed over from the primary firmware image.
+func dualImageFirmware(installed, buildDate string, activeImage int, primaryVersion, backupVersion string) *common.Firmware {
+	firmware := &common.Firmware{Installed: installed}
+
+	metadata := map[string]string{}
+
+	if !valuePlaceholder(buildDate) {
+		metadata["build_date"] = strings.TrimSpace(buildDate)
+	}
+
+	switch activeImage {
+	case firmwareImagePrimary:
+		metadata["active_bank"] = "primary"
+	case firmwareImageBackup:
+		metadata["active_bank"] = "backup"
+	}
+
+	if !versionPlaceholder(primaryVersion) {
+		metadata["primary_version"] = strings.TrimSpace(primaryVersion)
+	}
+
+	if !versionPlaceholder(backupVersion) {
+		metadata["backup_version"] = strings.TrimSpace(backupVersion)
+	}
+
+	if len(metadata) > 0 {
+		firmware.Metadata = metadata
+	}
+
+	return firmware
+}
+
+// firmwareBanksHealth sets the bmc.firmware.bank_mismatch and bios.firmware.bank_mismatch metadata
+// when the firmware bank versions are mismatched, the device health is set to WARNING when warn is set
+// and the device health is not more severe.
+//
+// A node failing over to a backup bank with a stale firmware image would run an unexpected firmware version.
+func firmwareBanksHealth(device *common.Device, desired map[string]string, warn bool) {
+	banks := map[string]*common.Firmware{}
+	if device.BMC != nil {
+		banks[common.SlugBMC] = device.BMC.Firmware
+	}
+
+	if device.BIOS != nil {
+		banks[common.SlugBIOS] = device.BIOS.Firmware
+	}
+
+	for slug, firmware := range banks {
+		if !firmwareBanksMismatch(firmware, desired[slug]) {
+			continue
+		}
+
+		device.Metadata[strings.ToLower(slug)+".firmware.bank_mismatch"] = "true"
+
+		if !warn {
+			continue
+		}
+
+		if device.Status == nil {
+			device.Status = &common.Status{}
+		}
+
+		if device.Status.Health == "" || healthSeverity(device.Status.Health) < healthSeverity(healthWarning) {
+			device.Status.Health = healthWarning
 		}
+	}
+}
 
+// firmwareBanksMismatch returns true when the primary and backup bank versions differ,
+// or differ from the desired version when given. Firmware without both bank versions is not considered.
+func firmwareBanksMismatch(firmware *common.Firmware, desired string) bool {
+	if firmware == nil {
+		return false
 	}
 
-	return nil
+	primary, backup := firmware.Metadata["primary_version"], firmware.Metadata["backup_version"]
+	if primary == "" || backup == "" {
+		return false
+	}
+
+	if desired = strings.TrimSpace(desired); desired != "" {
+		return primary != desired || backup != desired
+	}
+
+	return primary != backup
+}
+
+// cplds returns the CPLDs for the firmware info CPLD version,
+// which may list multiple CPLD versions separated by commas, semicolons or pipes - optionally prefixed by a name "CPLD1: 1.02".
+//
+// Version values which are placeholders for an absent CPLD are ignored.
+func cplds(device *common.Device, version string) []*common.CPLD {
+	found := []*common.CPLD{}
+
+	fields := strings.FieldsFunc(version, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	})
+
+	for _, field := range fields {
+		var name string
+
+		field = strings.TrimSpace(field)
+		if idx := strings.Index(field, ":"); idx != -1 {
+			name, field = strings.TrimSpace(field[:idx]), strings.TrimSpace(field[idx+1:])
+		}
+
+		if versionPlaceholder(field) {
+			continue
+		}
+
+		found = append(found, &common.CPLD{
+			Common: common.Common{
+				Description: name,
+				Vendor:      device.Vendor,
+				Model:       device.Model,
+				Firmware:    &common.Firmware{Installed: field},
+			},
+		})
+	}
+
+	return found
+}
+
+// cpldAttributes sets the CPLD vendor, model and serial from the inventory CPLD components
+//
+// Each component is correlated with the firmware info CPLD of the same name - "MB_CPLD" matches "MB CPLD",
+// the remaining components with the remaining firmware info CPLDs in the order listed. The firmware info version
+// is retained, the component version is used when the firmware info lists none. Components without a firmware info CPLD
+// are included as a CPLD when they report a model or version.
+func cpldAttributes(device *common.Device, components []*component) {
+	if len(components) == 0 {
+		return
+	}
+
+	matched := make([]*common.CPLD, len(components))
+	correlated := map[*common.CPLD]bool{}
+
+	// correlate by name
+	for idx, component := range components {
+		key := cpldKey(component.DeviceName)
+		if key == "" {
+			continue
+		}
+
+		for _, cpld := range device.CPLDs {
+			if !correlated[cpld] && cpldKey(cpld.Description) == key {
+				matched[idx] = cpld
+				correlated[cpld] = true
+
+				break
+			}
+		}
+	}
+
+	// correlate by order
+	for idx := range components {
+		if matched[idx] != nil {
+			continue
+		}
+
+		for _, cpld := range device.CPLDs {
+			if !correlated[cpld] {
+				matched[idx] = cpld
+				correlated[cpld] = true
+
+				break
+			}
+		}
+	}
+
+	for idx, component := range components {
+		version := normalizeNA(component.ProductVersion)
+
+		cpld := matched[idx]
+		if cpld == nil {
+			if version == "" && normalizeNA(component.ProductName) == "" {
+				continue
+			}
+
+			cpld = &common.CPLD{}
+			device.CPLDs = append(device.CPLDs, cpld)
+		}
+
+		if cpld.Description == "" {
+			cpld.Description = strings.TrimSpace(component.DeviceName)
+		}
+
+		if vendor := normalizeNA(component.ProductManufacturerName); vendor != "" {
+			cpld.Vendor = vendor
+		}
+
+		if model := normalizeNA(component.ProductName); model != "" {
+			cpld.Model = model
+		}
+
+		if serial := normalizeNA(component.ProductSerialNumber); serial != "" {
+			cpld.Serial = serial
+		}
+
+		if cpld.Firmware == nil && version != "" {
+			cpld.Firmware = &common.Firmware{Installed: version}
+		}
+	}
+}
+
+// cpldKey returns the CPLD name for comparison, in lower case without spaces, underscores or hyphens
+func cpldKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' {
+			return -1
+		}
+
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// naPlaceholders are the values the BMC reports for an attribute not available, matched case insensitively
+var naPlaceholders = map[string]bool{
+	"n/a":                    true,
+	"na":                     true,
+	"not available":          true,
+	"not specified":          true,
+	"to be filled by o.e.m.": true,
+	"default string":         true,
+}
+
+// normalizeNA returns the value with the surrounding whitespace trimmed,
+// or an empty string when the value indicates the attribute is not available - N/A, Not Available, Default string.
+func normalizeNA(value string) string {
+	value = strings.TrimSpace(value)
+	if naPlaceholders[strings.ToLower(value)] {
+		return ""
+	}
+
+	return value
+}
+
+// valuePlaceholders are the values the BMC reports for an absent attribute in addition to the naPlaceholders,
+// matched case insensitively
+var valuePlaceholders = map[string]bool{
+	"none":    true,
+	"unknown": true,
+	"-":       true,
+}
+
+// valuePlaceholder returns true when the attribute value - a serial, part number or slot, indicates the attribute is absent,
+// all zero values are values - unlike versionPlaceholder, a "00000000" serial number is a serial number.
+func valuePlaceholder(value string) bool {
+	value = normalizeNA(value)
+
+	return value == "" || valuePlaceholders[strings.ToLower(value)]
+}
+
+// versionPlaceholder returns true when the firmware version value indicates the component is absent
+func versionPlaceholder(version string) bool {
+	if valuePlaceholder(version) {
+		return true
+	}
+
+	// all zero versions - 0, 0.00, 00.00.00
+	return strings.Trim(normalizeNA(version), "0.") == ""
+}
+
+// componentAttributes populates the device components from the inventory info components
+func componentAttributes(device *common.Device, fwInfo *firmwareInfo, components []*component) {
+	var memorySlots int
+
+	cpldComponents := []*component{}
+
+	for _, component := range components {
+		switch component.DeviceType {
+		case "CPU":
+			device.CPUs = append(device.CPUs, cpuFromComponent(component, fwInfo))
+		case "Memory":
+			memorySlots++
+
+			// unpopulated slots are listed with N/A values
+			if valuePlaceholder(component.ProductSerialNumber) && valuePlaceholder(component.ProductPartNumber) {
+				continue
+			}
+
+			device.Memory = append(device.Memory, memoryFromComponent(component))
+
+		case "Storage device":
+			device.Drives = append(device.Drives, driveFromComponent(component))
+
+		case "GPU", "Graphics", "Accelerator":
+			var firmware *common.Firmware
+			if version := normalizeNA(component.ProductVersion); version != "" {
+				firmware = &common.Firmware{Installed: version}
+			}
+
+			device.GPUs = append(device.GPUs,
+				&common.GPU{
+					Common: common.Common{
+						Vendor:      component.ProductManufacturerName,
+						Model:       component.ProductName,
+						Serial:      component.ProductSerialNumber,
+						Description: component.DeviceName,
+						Firmware:    firmware,
+					},
+				},
+			)
+
+		case "TPM":
+			device.TPMs = append(device.TPMs, tpmFromComponent(component))
+
+		case "PCIe & OCP Card":
+			if isNICComponent(component) {
+				device.NICs = append(device.NICs, nicFromComponent(component))
+			}
+
+		case "CPLD":
+			cpldComponents = append(cpldComponents, component)
+		}
+	}
+
+	cpldAttributes(device, cpldComponents)
+
+	if memorySlots > 0 {
+		if device.Metadata == nil {
+			device.Metadata = map[string]string{}
+		}
+
+		device.Metadata["memory.slots.total"] = strconv.Itoa(memorySlots)
+		device.Metadata["memory.slots.populated"] = strconv.Itoa(len(device.Memory))
+	}
+}
+
+// cpuFromComponent returns the CPU attributes from the inventory component
+//
+// the core, thread count and clock speed are reported in the product extra field by some BMC firmware -
+// "8 Cores 16 Threads 3400 MHz", the attributes not reported are parsed from the model -
+// "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz", "AMD EPYC 7502P 32-Core Processor".
+func cpuFromComponent(component *component, fwInfo *firmwareInfo) *common.CPU {
+	cpu := &common.CPU{
+		Common: common.Common{
+			Vendor: component.ProductManufacturerName,
+			Model:  component.ProductName,
+			Firmware: &common.Firmware{
+				Installed: fwInfo.MicrocodeVersion,
+			},
+		},
+	}
+
+	for _, value := range []string{component.ProductExtra, component.ProductName} {
+		if cpu.Cores == 0 {
+			cpu.Cores = matchCount(cpuCoresRegexp, value)
+		}
+
+		if cpu.Threads == 0 {
+			cpu.Threads = matchCount(cpuThreadsRegexp, value)
+		}
+
+		if cpu.ClockSpeedHz == 0 {
+			cpu.ClockSpeedHz = clockSpeedHz(value)
+		}
+	}
+
+	return cpu
+}
+
+// matchCount returns the count matched in either of the regular expression groups, zero when not matched
+func matchCount(re *regexp.Regexp, value string) int {
+	matches := re.FindStringSubmatch(value)
+	if matches == nil {
+		return 0
+	}
+
+	for _, match := range matches[1:] {
+		if count, err := strconv.Atoi(match); err == nil {
+			return count
+		}
+	}
+
+	return 0
+}
+
+// clockSpeedHz returns the clock speed in Hz from the value - "3400 MHz", "3.40GHz", zero when not matched
+func clockSpeedHz(value string) int64 {
+	matches := cpuClockRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return 0
+	}
+
+	speed, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0
+	}
+
+	if strings.EqualFold(matches[2], "GHz") {
+		return int64(math.Round(speed * 1000 * 1000 * 1000))
+	}
+
+	return int64(math.Round(speed * 1000 * 1000))
+}
+
+// memoryFromComponent returns the memory module attributes from the inventory component
+//
+// the speed and size are reported in the product extra field by the BMC - "2666 MT/s  16GB"
+func memoryFromComponent(component *component) *common.Memory {
+	memory := &common.Memory{
+		Common: common.Common{
+			Vendor:      component.ProductManufacturerName,
+			Serial:      component.ProductSerialNumber,
+			Description: component.ProductExtra,
+		},
+
+		Slot:       component.DeviceName,
+		PartNumber: strings.TrimSpace(component.ProductPartNumber),
+		Type:       component.DeviceName,
+	}
+
+	if !valuePlaceholder(component.ProductName) {
+		memory.FormFactor = component.ProductName
+	}
+
+	if matches := memorySpeedRegexp.FindStringSubmatch(component.ProductExtra); matches != nil {
+		speed, _ := strconv.ParseInt(matches[1], 10, 64)
+		memory.ClockSpeedHz = speed * 1000 * 1000
+	}
+
+	if matches := memorySizeRegexp.FindStringSubmatch(component.ProductExtra); matches != nil {
+		size, _ := strconv.ParseInt(matches[1], 10, 64)
+		switch strings.ToUpper(matches[2]) {
+		case "MB":
+			memory.SizeBytes = size << 20
+		case "GB":
+			memory.SizeBytes = size << 30
+		case "TB":
+			memory.SizeBytes = size << 40
+		}
+	}
+
+	return memory
+}
+
+// driveFromComponent returns the drive attributes from the inventory component
+//
+// the drive protocol is inferred from the drive port reported in the asset tag field by the BMC - "SATA_4", "SAS_1", "M2_1",
+// falling back to the part number, the media is inferred from the part number.
+func driveFromComponent(component *component) *common.Drive {
+	vendor := normalizeNA(component.ProductManufacturerName)
+	if vendor == "" && normalizeNA(component.ProductPartNumber) != "" {
+		vendor = constants.VendorFromProductName(component.ProductPartNumber)
+	}
+
+	drive := &common.Drive{
+		Common: common.Common{
+			Vendor:      vendor,
+			Serial:      component.ProductSerialNumber,
+			ProductName: component.ProductPartNumber,
+		},
+	}
+
+	// the asset tag identifies the port the drive is attached to - SATA_4, M2_1
+	if !valuePlaceholder(component.ProductAssetTag) {
+		drive.ID = component.ProductAssetTag
+	}
+
+	// the port identifies the protocol the drive is attached with, the part number may not - drive families are sold in SATA and SAS variants
+	port := strings.ToUpper(component.ProductAssetTag)
+	switch {
+	case strings.HasPrefix(port, "SATA"):
+		drive.Protocol = constants.DriveProtocolSATA
+	case strings.HasPrefix(port, "SAS"):
+		drive.Protocol = constants.DriveProtocolSAS
+	case strings.HasPrefix(port, "M2"), strings.HasPrefix(port, "NVME"), strings.HasPrefix(port, "U2"):
+		drive.Protocol = constants.DriveProtocolNVMe
+	default:
+		drive.Protocol = constants.DriveProtocolFromProductName(component.ProductPartNumber)
+	}
+
+	media := constants.DriveMediaFromProductName(component.ProductPartNumber)
+
+	switch {
+	case drive.Protocol == constants.DriveProtocolNVMe:
+		drive.Type = common.SlugDriveTypePCIeNVMEeSSD
+	case drive.Protocol == constants.DriveProtocolSATA && media == constants.DriveMediaSSD:
+		drive.Type = common.SlugDriveTypeSATASSD
+	case drive.Protocol == constants.DriveProtocolSATA && media == constants.DriveMediaHDD:
+		drive.Type = common.SlugDriveTypeSATAHDD
+	default:
+		drive.Type = media
+	}
+
+	drive.CapacityBytes = driveCapacity(component)
+
+	return drive
+}
+
+// driveCapacity returns the drive capacity in bytes from the product extra field - "480GB", "1.92 TB",
+// falling back to the capacity encoded in Intel part numbers - "SSDSC2KB480G8", "SSDPE2KX019T8".
+func driveCapacity(component *component) int64 {
+	if matches := driveCapacityRegexp.FindStringSubmatch(component.ProductExtra); matches != nil {
+		capacity, _ := strconv.ParseFloat(matches[1], 64)
+		switch strings.ToUpper(matches[2]) {
+		case "GB":
+			return int64(capacity * 1e9)
+		case "TB":
+			return int64(capacity * 1e12)
+		}
+	}
+
+	if matches := intelDriveCapacityRegexp.FindStringSubmatch(component.ProductPartNumber); matches != nil {
+		capacity, _ := strconv.ParseInt(matches[1], 10, 64)
+		switch strings.ToUpper(matches[2]) {
+		case "G":
+			return capacity * 1e9
+		case "T":
+			// the terabyte capacity is encoded with a single decimal - 019T is 1.9TB
+			return capacity * 1e11
+		}
+	}
+
+	return 0
+}
+
+// tpmFromComponent returns the TPM attributes from the inventory component
+//
+// the enabled state is reported in the product extra field by the BMC
+func tpmFromComponent(component *component) *common.TPM {
+	tpm := &common.TPM{
+		Common: common.Common{
+			Vendor:      component.ProductManufacturerName,
+			Model:       component.ProductName,
+			Serial:      component.ProductSerialNumber,
+			Description: component.DeviceName,
+		},
+	}
+
+	if version := normalizeNA(component.ProductVersion); version != "" {
+		tpm.Firmware = &common.Firmware{Installed: version}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(component.ProductExtra)) {
+	case "enabled":
+		tpm.Status = &common.Status{State: "Enabled"}
+	case "disabled":
+		tpm.Status = &common.Status{State: "Disabled"}
+	}
+
+	return tpm
+}
+
+// nicFromComponent returns the host NIC for a network adapter listed in the inventory info,
+// the NIC is identified by the PCIe slot. The inventory info does not include the NIC port MAC addresses.
+func nicFromComponent(c *component) *common.NIC {
+	slot := pcieSlots([]*component{c})[0]
+
+	nic := &common.NIC{
+		Common: common.Common{
+			Vendor:       slot.Vendor,
+			Description:  slot.Class,
+			PCIVendorID:  slot.VendorID,
+			PCIProductID: slot.DeviceID,
+		},
+		ID: slot.Slot,
+	}
+
+	if serial := normalizeNA(c.ProductSerialNumber); !valuePlaceholder(serial) {
+		nic.Serial = serial
+	}
+
+	if version := normalizeNA(c.ProductVersion); !versionPlaceholder(version) {
+		nic.Firmware = &common.Firmware{Installed: version}
+	}
+
+	return nic
+}
+
+// bmcNIC returns the BMC management network interfaces as the BMC NIC ports, with the MAC addresses, link speed and status,
+// nil when the interfaces are not available.
+//
+// errors are logged and ignored since the network endpoints are not available on all firmware revisions.
+func (a *ASRockRack) bmcNIC(ctx context.Context) *common.NIC {
+	interfaces, err := a.networkInterfaces(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect BMC network interfaces, skipped BMC NIC inventory")
+		return nil
+	}
+
+	if len(interfaces) == 0 {
+		return nil
+	}
+
+	// link information is optional
+	links, err := a.networkLinks(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect BMC network link information")
+	}
+
+	nic := &common.NIC{
+		Common: common.Common{
+			Description: "BMC management network",
+		},
+		ID: "BMC",
+	}
+
+	for _, iface := range interfaces {
+		port := &common.NICPort{
+			Common: common.Common{
+				Description: iface.InterfaceName,
+			},
+			ID:         iface.InterfaceName,
+			MacAddress: strings.ToLower(iface.MACAddress),
+		}
+
+		for _, link := range links {
+			if link.InterfaceName != iface.InterfaceName {
+				continue
+			}
+
+			port.SpeedBits = int64(link.LinkSpeed) * 1000 * 1000
+			port.AutoNeg = link.AutoNegotiation == 1
+
+			if link.LinkStatus == 1 {
+				port.LinkStatus = "up"
+			} else {
+				port.LinkStatus = "down"
+			}
+		}
+
+		nic.NICPorts = append(nic.NICPorts, port)
+	}
+
+	return nic
+}
+
+// bmcNICAttributes sets the BMC NIC on the device BMC component
+func bmcNICAttributes(device *common.Device, nic *common.NIC) {
+	if nic == nil {
+		return
+	}
+
+	if device.BMC == nil {
+		device.BMC = &common.BMC{}
+	}
+
+	device.BMC.NIC = nic
+}
+
+// psuAttributes collects power supply attributes based on the FRU data,
+// used when the fru section is skipped and its FRU records are not available.
+func (a *ASRockRack) psuAttributes(ctx context.Context, device *common.Device) error {
+	frus, err := a.fruInfo(ctx)
+	if err != nil {
+		return err
+	}
+
+	device.PSUs = append(device.PSUs, psusFromFRUs(frus)...)
+
+	return nil
+}
+
+// psusFromFRUs returns PSU components for each PSU FRU device.
+func psusFromFRUs(frus []*fru) []*common.PSU {
+	psus := []*common.PSU{}
+	byDeviceID := map[int]*common.PSU{}
+
+	for _, f := range frus {
+		if !isPSUFRU(f) {
+			continue
+		}
+
+		psu, exists := byDeviceID[f.DeviceID]
+		if !exists {
+			psu = &common.PSU{ID: psuID(f.DeviceName)}
+			byDeviceID[f.DeviceID] = psu
+			psus = append(psus, psu)
+		}
+
+		// the product area takes precedence over the board area
+		if psu.Model != "" && f.Component != "product" {
+			continue
+		}
+
+		psu.Vendor = f.Manufacturer
+		psu.Model = f.ProductName
+		psu.Serial = f.SerialNumber
+		psu.ProductName = f.PartNumber
+
+		if f.ProductVersion != "" {
+			psu.Firmware = &common.Firmware{Installed: f.ProductVersion}
+		}
+
+		psu.PowerCapacityWatts = psuWatts(f.ProductName, f.PartNumber)
+	}
+
+	return psus
+}
+
+// isPSUFRU returns true if the FRU belongs to a PSU FRU device
+func isPSUFRU(f *fru) bool {
+	return f.DeviceID != 0 && strings.Contains(strings.ToUpper(f.DeviceName), "PSU")
+}
+
+// psuID returns the PSU identifier from the FRU device name - PSU1_FRU -> PSU1
+func psuID(fruDeviceName string) string {
+	id := strings.ToUpper(strings.TrimSpace(fruDeviceName))
+	id = strings.TrimSuffix(id, "_FRU")
+
+	return strings.TrimSpace(strings.TrimSuffix(id, " FRU"))
+}
+
+// psuWatts returns the wattage parsed from the given PSU attributes, 0 if none could be identified
+func psuWatts(attributes ...string) int64 {
+	for _, attr := range attributes {
+		matches := psuWattsRegexp.FindStringSubmatch(attr)
+		if len(matches) < 2 {
+			continue
+		}
+
+		watts, err := strconv.ParseInt(matches[1], 10, 64)
+		if err == nil {
+			return watts
+		}
+	}
+
+	return 0
+}
+
+// psuStatus returns the PSU status based on the sensors prefixed with the PSU identifier,
+// nil is returned when no sensors match the PSU.
+func psuStatus(id string, sensors []*Sensor) *common.Status {
+	return sensorsStatus(sensors, id)
+}
+
+// sensorsStatus returns the status based on the sensors prefixed with any of the given component identifiers,
+// the health is set to the most severe sensor health and the state to the name of that sensor.
+//
+// nil is returned when no sensors match the component.
+func sensorsStatus(sensors []*Sensor, ids ...string) *common.Status {
+	var status *common.Status
+
+	for _, sensor := range sensors {
+		if !sensorMatches(sensor, ids) {
+			continue
+		}
+
+		if status == nil {
+			status = &common.Status{Health: healthOK}
+		}
+
+		health := sensor.health()
+		if healthSeverity(health) > healthSeverity(status.Health) {
+			status.Health = health
+			status.State = sensor.Name
+		}
+	}
+
+	return status
+}
+
+// sensorMatches returns true when the sensor name is prefixed with any of the given component identifiers
+func sensorMatches(sensor *Sensor, ids []string) bool {
+	name := strings.ToUpper(sensor.Name)
+	for _, id := range ids {
+		id = strings.ToUpper(id)
+		if strings.HasPrefix(name, id+"_") || strings.HasPrefix(name, id+" ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// componentsHealth sets the CPU, memory module and drive status based on the sensors correlated with the component,
+// CPU sensors are named CPU<n> on multi socket boards, the unnumbered CPU sensors are correlated on single socket boards.
+// Memory sensors are named with the slot - DDR4_A1_TEMP, DIMM_A1 Temp, drive sensors with the port - M2_1 Temp.
+//
+// The component status is left unset when no sensors could be correlated,
+// drives with a status set from the SMART data are not correlated.
+func componentsHealth(device *common.Device, sensors []*Sensor) {
+	for idx, cpu := range device.CPUs {
+		ids := []string{"CPU" + strconv.Itoa(idx+1)}
+		if len(device.CPUs) == 1 {
+			ids = append(ids, "CPU")
+		}
+
+		if status := sensorsStatus(sensors, ids...); status != nil {
+			cpu.Status = status
+		}
+	}
+
+	for _, memory := range device.Memory {
+		if memory.Slot == "" {
+			continue
+		}
+
+		ids := []string{memory.Slot}
+
+		// DDR4_A1 -> DIMM_A1, DIMMA1
+		if idx := strings.Index(memory.Slot, "_"); idx > 0 && strings.HasPrefix(strings.ToUpper(memory.Slot), "DDR") {
+			bank := memory.Slot[idx+1:]
+			ids = append(ids, "DIMM_"+bank, "DIMM"+bank)
+		}
+
+		if status := sensorsStatus(sensors, ids...); status != nil {
+			memory.Status = status
+		}
+	}
+
+	for _, drive := range device.Drives {
+		if drive.Status != nil || drive.ID == "" {
+			continue
+		}
+
+		if status := sensorsStatus(sensors, drive.ID); status != nil {
+			drive.Status = status
+		}
+	}
+
+	// the PSU health is set from the sensors named with the PSU identifier prefix
+	for _, psu := range device.PSUs {
+		if status := psuStatus(psu.ID, sensors); status != nil {
+			psu.Status = status
+		}
+	}
 }
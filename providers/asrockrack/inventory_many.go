@@ -0,0 +1,88 @@
+package asrockrack
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// InventoryMany collects the inventory of the BMCs with up to concurrency inventories in flight,
+// returning the devices and the inventory errors keyed by the BMC base URL - https://<host>[:port]/,
+// so BMCs sharing an address behind distinct ports or base URL paths are not collapsed.
+//
+// A BMC failing the inventory is returned in the errors, along with the partial inventory in the devices
+// when the Inventory returned one. Once the context is cancelled no further inventories are started,
+// the BMCs not inventoried are returned with the context error. A concurrency below 1 inventories one BMC at a time.
+func InventoryMany(ctx context.Context, providers []*ASRockRack, concurrency int) (map[string]*common.Device, map[string]error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	devices := map[string]*common.Device{}
+	errs := map[string]error{}
+
+	var mu sync.Mutex
+
+	record := func(host string, device *common.Device, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if device != nil {
+			devices[host] = device
+		}
+
+		if err != nil {
+			errs[host] = err
+		}
+	}
+
+	jobs := make(chan *ASRockRack)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency && i < len(providers); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for provider := range jobs {
+				device, err := provider.Inventory(ctx)
+				record(inventoryKey(provider), device, err)
+			}
+		}()
+	}
+
+	for idx, provider := range providers {
+		// a cancelled context is checked first, as the select picks any of the ready cases
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case jobs <- provider:
+				continue
+			}
+		}
+
+		for _, skipped := range providers[idx:] {
+			record(inventoryKey(skipped), nil, ctx.Err())
+		}
+
+		break
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return devices, errs
+}
+
+// inventoryKey returns the key of the BMC inventory results - the BMC base URL,
+// or the BMC address when no base URL could be formed from an invalid address.
+func inventoryKey(provider *ASRockRack) string {
+	if provider.baseURL == "" {
+		return provider.ip
+	}
+
+	return provider.baseURL
+}
@@ -0,0 +1,91 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InventoryMany(t *testing.T) {
+	providers := []*ASRockRack{}
+	healthy, failing := []string{}, []string{}
+
+	for i := 0; i < 4; i++ {
+		server := asrockracktest.NewServer()
+		defer server.Close()
+
+		serverURL, _ := url.Parse(server.URL)
+		providers = append(providers, NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify()))
+		healthy = append(healthy, "https://"+serverURL.Host+"/")
+	}
+
+	for i := 0; i < 2; i++ {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		serverURL, _ := url.Parse(server.URL)
+		providers = append(providers, NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify()))
+		failing = append(failing, "https://"+serverURL.Host+"/")
+	}
+
+	devices, errs := InventoryMany(context.TODO(), providers, 3)
+	assert.Len(t, devices, len(healthy))
+	assert.Len(t, errs, len(failing))
+
+	for _, host := range healthy {
+		if assert.Contains(t, devices, host) {
+			assert.Equal(t, "E3C246D4I-NL", devices[host].Model)
+		}
+	}
+
+	for _, host := range failing {
+		assert.Error(t, errs[host])
+	}
+}
+
+func Test_InventoryManyCancelled(t *testing.T) {
+	providers := []*ASRockRack{
+		NewWithOptions("127.0.0.1:1", "foo", "bar", logr.Discard()),
+		NewWithOptions("127.0.0.1:2", "foo", "bar", logr.Discard()),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	devices, errs := InventoryMany(ctx, providers, 0)
+	assert.Empty(t, devices)
+	assert.Len(t, errs, 2)
+
+	for _, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func Test_InventoryManySharedAddress(t *testing.T) {
+	providers := []*ASRockRack{}
+
+	for i := 0; i < 2; i++ {
+		server := asrockracktest.NewServer()
+		defer server.Close()
+
+		serverURL, _ := url.Parse(server.URL)
+		providers = append(providers, NewWithOptions(serverURL.Hostname(), "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithPort(serverURL.Port())))
+	}
+
+	// the BMCs share the address, the results are keyed by the base URL including the port
+	devices, errs := InventoryMany(context.TODO(), providers, 2)
+	assert.Empty(t, errs)
+	assert.Len(t, devices, 2)
+
+	for _, provider := range providers {
+		assert.Contains(t, devices, provider.baseURL)
+	}
+}
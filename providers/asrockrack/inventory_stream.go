@@ -0,0 +1,172 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// streamDoneGrace is the time the done event is awaited to be received once the context is done,
+// the stream goroutine exits when the consumer stopped reading.
+const streamDoneGrace = time.Second
+
+// InventoryEventType is the type of an inventory stream event
+type InventoryEventType string
+
+const (
+	// InventoryEventSection is emitted once an inventory section is collected, with the section error if it failed
+	InventoryEventSection InventoryEventType = "section"
+	// InventoryEventDone is the last event emitted, with the inventory device and error as returned by Inventory
+	InventoryEventDone InventoryEventType = "done"
+
+	// component events, the Component is set to the component of the event type - *common.BIOS, *Sensor
+	InventoryEventBIOS              InventoryEventType = "bios"
+	InventoryEventBMC               InventoryEventType = "bmc"
+	InventoryEventCPLD              InventoryEventType = "cpld"
+	InventoryEventTPM               InventoryEventType = "tpm"
+	InventoryEventGPU               InventoryEventType = "gpu"
+	InventoryEventCPU               InventoryEventType = "cpu"
+	InventoryEventMemory            InventoryEventType = "memory"
+	InventoryEventNIC               InventoryEventType = "nic"
+	InventoryEventDrive             InventoryEventType = "drive"
+	InventoryEventStorageController InventoryEventType = "storage_controller"
+	InventoryEventPSU               InventoryEventType = "psu"
+	InventoryEventEnclosure         InventoryEventType = "enclosure"
+	InventoryEventSensor            InventoryEventType = "sensor"
+)
+
+// InventoryEvent is an inventory stream event
+//
+// Component events carry the component as collected by its section - *common.Drive, *common.CPU, *Sensor,
+// the attributes correlated across sections, the drive storage membership, SMART data and the component health,
+// are set on the device of the done event only.
+type InventoryEvent struct {
+	Type      InventoryEventType
+	Section   InventorySection
+	Component interface{}
+	Device    *common.Device
+	Err       error
+}
+
+// InventoryStream collects the hardware and firmware inventory as Inventory does, emitting the components
+// as each inventory section is collected.
+//
+// The last event emitted is an InventoryEventDone event with the device and error returned by Inventory,
+// after which the channel is closed. The channel is to be drained until it is closed, once the context is done
+// the component events are no longer emitted and the done event is dropped unless received within streamDoneGrace.
+//
+// The BMC address, port or base URL configuration error is returned without collecting the inventory.
+func (a *ASRockRack) InventoryStream(ctx context.Context) (<-chan InventoryEvent, error) {
+	if a.configErr != nil {
+		return nil, a.configErr
+	}
+
+	events := make(chan InventoryEvent)
+
+	go func() {
+		defer close(events)
+
+		emit := func(event InventoryEvent) {
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+			}
+		}
+
+		device, err := a.collectInventory(ctx, emit)
+		done := InventoryEvent{Type: InventoryEventDone, Device: device, Err: err}
+
+		select {
+		case events <- done:
+		case <-ctx.Done():
+			// the partial device is returned on the context deadline, to a consumer still draining the channel
+			timer := time.NewTimer(streamDoneGrace)
+			defer timer.Stop()
+
+			select {
+			case events <- done:
+			case <-timer.C:
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// emitSection emits the components collected by an inventory section, followed by the section event
+//
+// The components are emitted as deep copies, sharing no metadata, status or firmware with the device components
+// which are updated once all sections are collected.
+func emitSection(emit func(InventoryEvent), section InventorySection, device *common.Device, err error) {
+	component := func(eventType InventoryEventType, c, copied interface{}) {
+		copyComponent(c, copied)
+		emit(InventoryEvent{Type: eventType, Section: section, Component: copied})
+	}
+
+	if device.BIOS != nil {
+		component(InventoryEventBIOS, device.BIOS, &common.BIOS{})
+	}
+
+	if device.BMC != nil {
+		component(InventoryEventBMC, device.BMC, &common.BMC{})
+	}
+
+	for _, c := range device.CPLDs {
+		component(InventoryEventCPLD, c, &common.CPLD{})
+	}
+
+	for _, c := range device.TPMs {
+		component(InventoryEventTPM, c, &common.TPM{})
+	}
+
+	for _, c := range device.GPUs {
+		component(InventoryEventGPU, c, &common.GPU{})
+	}
+
+	for _, c := range device.CPUs {
+		component(InventoryEventCPU, c, &common.CPU{})
+	}
+
+	for _, c := range device.Memory {
+		component(InventoryEventMemory, c, &common.Memory{})
+	}
+
+	for _, c := range device.NICs {
+		component(InventoryEventNIC, c, &common.NIC{})
+	}
+
+	for _, c := range device.Drives {
+		component(InventoryEventDrive, c, &common.Drive{})
+	}
+
+	for _, c := range device.StorageControllers {
+		component(InventoryEventStorageController, c, &common.StorageController{})
+	}
+
+	for _, c := range device.PSUs {
+		component(InventoryEventPSU, c, &common.PSU{})
+	}
+
+	for _, c := range device.Enclosures {
+		component(InventoryEventEnclosure, c, &common.Enclosure{})
+	}
+
+	emit(InventoryEvent{Type: InventoryEventSection, Section: section, Err: err})
+}
+
+// copyComponent deep copies the component into copied through its JSON encoding,
+// the common components are plain data types which always encode.
+func copyComponent(c, copied interface{}) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+
+	_ = json.Unmarshal(b, copied)
+}
@@ -0,0 +1,159 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InventoryStream(t *testing.T) {
+	events, err := aClient.InventoryStream(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[InventoryEventType]int{}
+	sections := map[InventorySection]bool{}
+
+	var done InventoryEvent
+
+	for event := range events {
+		counts[event.Type]++
+
+		switch event.Type {
+		case InventoryEventSection:
+			assert.Nil(t, event.Err)
+			sections[event.Section] = true
+		case InventoryEventDone:
+			done = event
+		case InventoryEventDrive:
+			assert.IsType(t, &common.Drive{}, event.Component)
+		case InventoryEventSensor:
+			assert.IsType(t, &Sensor{}, event.Component)
+			assert.Equal(t, InventorySectionHealth, event.Section)
+		}
+	}
+
+	// the done event is emitted last, once
+	assert.Equal(t, 1, counts[InventoryEventDone])
+	assert.Nil(t, done.Err)
+	device := done.Device
+	assert.NotNil(t, device)
+
+	assert.Equal(t, 7, counts[InventoryEventSection])
+	assert.True(t, sections[InventorySectionFRU])
+	assert.True(t, sections[InventorySectionHealth])
+
+	assert.Equal(t, 27, counts[InventoryEventSensor])
+	assert.Equal(t, len(device.Drives), counts[InventoryEventDrive])
+	assert.Equal(t, len(device.CPUs), counts[InventoryEventCPU])
+	assert.Equal(t, len(device.Memory), counts[InventoryEventMemory])
+	assert.Equal(t, len(device.GPUs), counts[InventoryEventGPU])
+	assert.Equal(t, len(device.StorageControllers), counts[InventoryEventStorageController])
+	assert.Equal(t, len(device.PSUs), counts[InventoryEventPSU])
+	assert.Equal(t, 1, counts[InventoryEventBIOS])
+	assert.Equal(t, 1, counts[InventoryEventBMC])
+}
+
+func Test_InventoryStreamComponentCopies(t *testing.T) {
+	backplanes, err := os.ReadFile("./fixtures/ROMED8-2T/backplanes.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sensors := []byte(`[
+		{ "id": 1, "name": "BPB1_TEMP", "type": "temperature", "type_number": 1, "reading": 31.000000, "sensor_state": 1, "unit": "°C" }
+	]`)
+
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse("api/asrr/storage/backplanes", http.StatusOK, backplanes),
+		asrockracktest.WithResponse("api/sensors", http.StatusOK, sensors),
+	)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	events, err := client.InventoryStream(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		enclosures []*common.Enclosure
+		done       InventoryEvent
+	)
+
+	// the emitted enclosures are read while the device enclosures are updated with the backplane temperatures
+	for event := range events {
+		switch event.Type {
+		case InventoryEventEnclosure:
+			enclosure := event.Component.(*common.Enclosure)
+			for k, v := range enclosure.Metadata {
+				_, _ = k, v
+			}
+
+			enclosures = append(enclosures, enclosure)
+		case InventoryEventDone:
+			done = event
+		}
+	}
+
+	if done.Err != nil {
+		t.Fatal(done.Err)
+	}
+
+	assert.Equal(t, len(done.Device.Enclosures), len(enclosures))
+
+	// the temperatures are correlated on the device enclosures only
+	var temperature bool
+	for _, enclosure := range done.Device.Enclosures {
+		temperature = temperature || enclosure.Metadata["temperature"] == "31"
+	}
+
+	assert.True(t, temperature)
+
+	for _, enclosure := range enclosures {
+		assert.NotContains(t, enclosure.Metadata, "temperature")
+	}
+}
+
+func Test_InventoryStreamCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	events, err := aClient.InventoryStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the consumer stops reading once the context is canceled
+	<-events
+	cancel()
+
+	time.Sleep(streamDoneGrace + 500*time.Millisecond)
+
+	select {
+	case _, ok := <-events:
+		for ok {
+			_, ok = <-events
+		}
+	case <-time.After(time.Second):
+		t.Fatal("inventory stream blocked after the context was canceled")
+	}
+}
+
+func Test_InventoryStreamConfigError(t *testing.T) {
+	client := NewWithOptions("", "foo", "bar", logr.Discard())
+
+	events, err := client.InventoryStream(context.TODO())
+	assert.ErrorIs(t, err, ErrInvalidBMCAddress)
+	assert.Nil(t, events)
+}
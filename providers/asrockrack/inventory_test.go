@@ -2,8 +2,22 @@ package asrockrack
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,6 +29,7 @@ func Test_GetInventory(t *testing.T) {
 
 	assert.NotNil(t, device)
 	assert.Equal(t, "ASRockRack", device.Vendor)
+	assert.Equal(t, "ASRockRack", device.Metadata["board.manufacturer_raw"])
 	assert.Equal(t, "E3C246D4I-NL", device.Model)
 
 	assert.Equal(t, "L2.07B", device.BIOS.Firmware.Installed)
@@ -23,5 +38,1631 @@ func Test_GetInventory(t *testing.T) {
 	assert.Equal(t, "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz", device.CPUs[0].Model)
 	assert.Equal(t, 2, len(device.Memory))
 	assert.Equal(t, 2, len(device.Drives))
+	assert.Equal(t, 1, len(device.GPUs))
+	assert.Equal(t, "NVIDIA Corporation", device.GPUs[0].Vendor)
+	assert.Equal(t, "NVIDIA A100-PCIE-40GB", device.GPUs[0].Model)
+	assert.Equal(t, "1561221012345", device.GPUs[0].Serial)
+	assert.Equal(t, "92.00.25.00.08", device.GPUs[0].Firmware.Installed)
 	assert.Equal(t, "OK", device.Status.Health)
+
+	// the Intel ME version is reported once on the device
+	assert.Equal(t, "5.1.3.78", device.Metadata["intel_me.version"])
+	assert.Nil(t, device.CPUs[0].Firmware.Metadata)
+
+	assert.Equal(t, 160, device.Status.PostCode)
+	assert.Equal(t, "OS boot, control handed to the OS loader", device.Metadata["post_code.description"])
+
+	// drives are linked to the storage controller virtual disk
+	assert.Equal(t, 1, len(device.StorageControllers))
+	for _, drive := range device.Drives {
+		assert.Equal(t, "0", drive.StorageController)
+		assert.Equal(t, "Volume0", drive.Metadata["storage.virtual_disk"])
+	}
+
+	// the drive health is set from the SMART data
+	assert.Equal(t, "OK", device.Drives[0].Status.Health)
+	assert.Equal(t, "CRITICAL", device.Drives[1].Status.Health)
+}
+
+func Test_systemAttributesCPUMEVersionMetadata(t *testing.T) {
+	client := NewWithOptions(bmcURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithCPUMEVersionMetadata())
+
+	device := common.NewDevice()
+	device.Metadata = map[string]string{}
+
+	err := client.systemAttributes(context.TODO(), &device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "5.1.3.78", device.Metadata["intel_me.version"])
+	assert.Equal(t, "5.1.3.78", device.CPUs[0].Firmware.Metadata["Intel_ME_version"])
+}
+
+func Test_systemHealthFans(t *testing.T) {
+	device := common.NewDevice()
+	device.Status = &common.Status{}
+	device.Metadata = map[string]string{}
+
+	err := aClient.systemHealth(context.TODO(), &device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 8; i++ {
+		key := fmt.Sprintf("sensor.fan.ipb_fan%d", i)
+		assert.Equal(t, "5200", device.Metadata[key])
+		assert.Equal(t, "OK", device.Metadata[key+".health"])
+	}
+}
+
+func Test_systemHealthTemperatures(t *testing.T) {
+	device := common.NewDevice()
+	device.Status = &common.Status{}
+	device.Metadata = map[string]string{}
+
+	err := aClient.systemHealth(context.TODO(), &device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "28", device.Metadata["sensor.temp.cpu_temp"])
+	assert.Equal(t, "°C", device.Metadata["sensor.temp.cpu_temp.unit"])
+	assert.Equal(t, "99", device.Metadata["sensor.temp.cpu_temp.upper_non_critical"])
+	assert.Equal(t, "100", device.Metadata["sensor.temp.cpu_temp.upper_critical"])
+	assert.Equal(t, "30", device.Metadata["sensor.temp.mb_temp"])
+	assert.Equal(t, "55", device.Metadata["sensor.temp.mb_temp.upper_critical"])
+
+	// thresholds not readable
+	assert.NotContains(t, device.Metadata, "sensor.temp.cpu_temp.lower_critical")
+
+	// sensor not accessible
+	assert.NotContains(t, device.Metadata, "sensor.temp.tr1_temp")
+}
+
+func Test_bmcNIC(t *testing.T) {
+	nic := aClient.bmcNIC(context.TODO())
+	if nic == nil {
+		t.Fatal("expected the BMC NIC")
+	}
+
+	assert.Equal(t, 2, len(nic.NICPorts))
+	assert.Equal(t, "d0:50:99:f7:84:35", nic.NICPorts[0].MacAddress)
+	assert.Equal(t, int64(1000*1000*1000), nic.NICPorts[0].SpeedBits)
+	assert.Equal(t, "up", nic.NICPorts[0].LinkStatus)
+	assert.Equal(t, "d0:50:99:f7:84:36", nic.NICPorts[1].MacAddress)
+	assert.Equal(t, "down", nic.NICPorts[1].LinkStatus)
+}
+
+func Test_InventoryNICs(t *testing.T) {
+	inventory, err := os.ReadFile("./fixtures/ROMED8-2T/inventory-info-nics.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse("api/asrr/inventory_info", http.StatusOK, inventory),
+		asrockracktest.WithResponse("api/settings/network", http.StatusOK, networkResponse),
+		asrockracktest.WithResponse("api/settings/network-link", http.StatusOK, networkLinkResponse),
+	)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the host network adapters, the empty slot and the SAS controller are not NICs
+	assert.Equal(t, []*common.NIC{
+		{
+			Common: common.Common{
+				Vendor:       "Mellanox Technologies",
+				Description:  "Ethernet controller",
+				Serial:       "MT2014X12345",
+				PCIVendorID:  "15b3",
+				PCIProductID: "1017",
+				Firmware:     &common.Firmware{Installed: "16.28.1002"},
+			},
+			ID: "PCIE1",
+		},
+		{
+			Common: common.Common{
+				Vendor:       "Intel Corporation",
+				Description:  "Ethernet controller",
+				PCIVendorID:  "8086",
+				PCIProductID: "1572",
+			},
+			ID: "OCP1",
+		},
+	}, device.NICs)
+
+	// the BMC management network interfaces are reported as the BMC NIC
+	if device.BMC == nil || device.BMC.NIC == nil {
+		t.Fatal("expected the BMC NIC")
+	}
+
+	assert.Equal(t, "BMC", device.BMC.NIC.ID)
+	assert.Equal(t, 2, len(device.BMC.NIC.NICPorts))
+	assert.Equal(t, "d0:50:99:f7:84:35", device.BMC.NIC.NICPorts[0].MacAddress)
+}
+
+func Test_psusFromFRUs(t *testing.T) {
+	psuFRU := func(deviceID int, deviceName, component, serial string) *fru {
+		return &fru{
+			Component:      component,
+			DeviceID:       deviceID,
+			DeviceName:     deviceName,
+			Manufacturer:   "Delta",
+			ProductName:    "CRPS 800W",
+			PartNumber:     "DPS-800AB-1",
+			ProductVersion: "S1",
+			SerialNumber:   serial,
+		}
+	}
+
+	testcases := []struct {
+		name    string
+		frus    []*fru
+		sensors []*Sensor
+		health  []string
+		serials []string
+	}{
+		{
+			"no PSU FRUs",
+			[]*fru{{Component: "board", DeviceID: 0, DeviceName: "BMC_FRU"}},
+			nil,
+			[]string{},
+			[]string{},
+		},
+		{
+			"single PSU",
+			[]*fru{
+				{Component: "board", DeviceID: 0, DeviceName: "BMC_FRU"},
+				psuFRU(1, "PSU1_FRU", "board", "board-serial"),
+				psuFRU(1, "PSU1_FRU", "product", "DSPT1234"),
+			},
+			[]*Sensor{{Name: "PSU1_Status", SensorState: 1}},
+			[]string{"OK"},
+			[]string{"DSPT1234"},
+		},
+		{
+			"dual PSU, one failed",
+			[]*fru{
+				psuFRU(1, "PSU1_FRU", "product", "DSPT1234"),
+				psuFRU(2, "PSU2_FRU", "product", "DSPT5678"),
+			},
+			[]*Sensor{
+				{Name: "PSU1_Status", SensorState: 1},
+				{Name: "PSU2_Status", SensorState: 0},
+				{Name: "PSU2 PIN", SensorState: 1},
+			},
+			[]string{"OK", "CRITICAL"},
+			[]string{"DSPT1234", "DSPT5678"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := &common.Device{PSUs: psusFromFRUs(tc.frus)}
+			componentsHealth(device, tc.sensors)
+
+			assert.Equal(t, len(tc.serials), len(device.PSUs))
+			for idx, psu := range device.PSUs {
+				assert.Equal(t, tc.serials[idx], psu.Serial)
+				assert.Equal(t, tc.health[idx], psu.Status.Health)
+				assert.Equal(t, "CRPS 800W", psu.Model)
+				assert.Equal(t, int64(800), psu.PowerCapacityWatts)
+			}
+		})
+	}
+}
+
+func Test_componentAttributesMemory(t *testing.T) {
+	fwInfo := &firmwareInfo{}
+
+	device := common.NewDevice()
+	componentAttributes(&device, fwInfo, []*component{
+		{
+			DeviceName:              "DDR4_A1",
+			DeviceType:              "Memory",
+			ProductManufacturerName: "Micron",
+			ProductName:             "SODIMM",
+			ProductPartNumber:       "18ASF2G72HZ-2G6E1   ",
+			ProductSerialNumber:     "2724B52D",
+			ProductExtra:            "2666 MT/s  16GB",
+		},
+		{
+			DeviceName:              "DDR4_A2",
+			DeviceType:              "Memory",
+			ProductManufacturerName: "N/A",
+			ProductName:             "N/A",
+			ProductPartNumber:       "N/A",
+			ProductSerialNumber:     "N/A",
+			ProductExtra:            "N/A",
+		},
+		{
+			DeviceName:              "DDR4_B1",
+			DeviceType:              "Memory",
+			ProductManufacturerName: "Samsung",
+			ProductName:             "DIMM",
+			ProductPartNumber:       "M393A4K40DB3-CWE",
+			ProductSerialNumber:     "03E1C2F4",
+			ProductExtra:            "3200 MHz 32 GB",
+		},
+		{
+			DeviceName:          "DDR4_B2",
+			DeviceType:          "Memory",
+			ProductPartNumber:   "N/A     ",
+			ProductSerialNumber: "N/A",
+		},
+		// an all zero serial number is reported by some DIMMs
+		{
+			DeviceName:          "DDR4_C1",
+			DeviceType:          "Memory",
+			ProductName:         "DIMM",
+			ProductPartNumber:   "N/A",
+			ProductSerialNumber: "00000000",
+			ProductExtra:        "3200 MHz 32 GB",
+		},
+	})
+
+	assert.Equal(t, 3, len(device.Memory))
+	assert.Equal(t, "5", device.Metadata["memory.slots.total"])
+	assert.Equal(t, "3", device.Metadata["memory.slots.populated"])
+
+	assert.Equal(t, "DDR4_A1", device.Memory[0].Slot)
+	assert.Equal(t, "18ASF2G72HZ-2G6E1", device.Memory[0].PartNumber)
+	assert.Equal(t, "SODIMM", device.Memory[0].FormFactor)
+	assert.Equal(t, int64(2666000000), device.Memory[0].ClockSpeedHz)
+	assert.Equal(t, int64(16<<30), device.Memory[0].SizeBytes)
+
+	assert.Equal(t, "DDR4_B1", device.Memory[1].Slot)
+	assert.Equal(t, "DIMM", device.Memory[1].FormFactor)
+	assert.Equal(t, int64(3200000000), device.Memory[1].ClockSpeedHz)
+	assert.Equal(t, int64(32<<30), device.Memory[1].SizeBytes)
+
+	assert.Equal(t, "DDR4_C1", device.Memory[2].Slot)
+	assert.Equal(t, "00000000", device.Memory[2].Serial)
+}
+
+func Test_componentAttributesCPU(t *testing.T) {
+	fwInfo := &firmwareInfo{MicrocodeVersion: "000000ca"}
+
+	device := common.NewDevice()
+	componentAttributes(&device, fwInfo, []*component{
+		{
+			DeviceName:              "CPU1",
+			DeviceType:              "CPU",
+			ProductManufacturerName: "Intel(R) Corporation",
+			ProductName:             "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz",
+			ProductExtra:            "8 Cores 16 Threads 3400 MHz",
+		},
+		{
+			DeviceName:              "CPU1",
+			DeviceType:              "CPU",
+			ProductManufacturerName: "Intel(R) Corporation",
+			ProductName:             "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz",
+			ProductExtra:            "Cores: 8, Threads: 16",
+		},
+		{
+			DeviceName:              "CPU1",
+			DeviceType:              "CPU",
+			ProductManufacturerName: "AMD",
+			ProductName:             "AMD EPYC 7502P 32-Core Processor",
+			ProductExtra:            "N/A",
+		},
+		{
+			DeviceName:              "CPU1",
+			DeviceType:              "CPU",
+			ProductManufacturerName: "Intel(R) Corporation",
+			ProductName:             "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz",
+			ProductExtra:            "N/A",
+		},
+	})
+
+	assert.Equal(t, 4, len(device.CPUs))
+
+	// reported by the component
+	assert.Equal(t, 8, device.CPUs[0].Cores)
+	assert.Equal(t, 16, device.CPUs[0].Threads)
+	assert.Equal(t, int64(3400000000), device.CPUs[0].ClockSpeedHz)
+	assert.Equal(t, "000000ca", device.CPUs[0].Firmware.Installed)
+
+	// the clock speed parsed from the model
+	assert.Equal(t, 8, device.CPUs[1].Cores)
+	assert.Equal(t, 16, device.CPUs[1].Threads)
+	assert.Equal(t, int64(3400000000), device.CPUs[1].ClockSpeedHz)
+
+	// the core count parsed from the model
+	assert.Equal(t, 32, device.CPUs[2].Cores)
+	assert.Equal(t, 0, device.CPUs[2].Threads)
+	assert.Equal(t, int64(0), device.CPUs[2].ClockSpeedHz)
+
+	assert.Equal(t, 0, device.CPUs[3].Cores)
+	assert.Equal(t, 0, device.CPUs[3].Threads)
+	assert.Equal(t, int64(3400000000), device.CPUs[3].ClockSpeedHz)
+}
+
+func Test_componentAttributesTPM(t *testing.T) {
+	fwInfo := &firmwareInfo{}
+
+	// fixture without a TPM
+	device := common.NewDevice()
+	componentAttributes(&device, fwInfo, []*component{
+		{DeviceName: "CPU1", DeviceType: "CPU", ProductName: "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz"},
+	})
+
+	assert.Equal(t, 0, len(device.TPMs))
+
+	// fixture with a TPM
+	device = common.NewDevice()
+	componentAttributes(&device, fwInfo, []*component{
+		{DeviceName: "CPU1", DeviceType: "CPU", ProductName: "Intel(R) Xeon(R) E-2278G CPU @ 3.40GHz"},
+		{
+			DeviceName:              "TPM",
+			DeviceType:              "TPM",
+			ProductManufacturerName: "Infineon",
+			ProductName:             "SLB9670 TPM2.0",
+			ProductVersion:          "7.85",
+			ProductSerialNumber:     "N/A",
+			ProductExtra:            "Enabled",
+		},
+	})
+
+	assert.Equal(t, 1, len(device.TPMs))
+	assert.Equal(t, "Infineon", device.TPMs[0].Vendor)
+	assert.Equal(t, "7.85", device.TPMs[0].Firmware.Installed)
+	assert.Equal(t, "Enabled", device.TPMs[0].Status.State)
+}
+
+func Test_sensorsHealth(t *testing.T) {
+	testcases := []struct {
+		name    string
+		sensors []*Sensor
+		health  string
+		failing string
+	}{
+		{
+			"all nominal",
+			[]*Sensor{
+				{Name: "CPU Temp", SensorState: 1},
+				{Name: "CPU_CATERR", SensorState: 0},
+			},
+			"OK",
+			"",
+		},
+		{
+			"warning only",
+			[]*Sensor{
+				{Name: "CPU Temp", SensorState: 1},
+				{Name: "MB Temp", SensorState: sensorStateUpperNonCritical},
+				{Name: "BAT", SensorState: sensorStateLowerNonCritical},
+			},
+			"WARNING",
+			"MB Temp,BAT",
+		},
+		{
+			"warning and critical",
+			[]*Sensor{
+				{Name: "MB Temp", SensorState: sensorStateUpperNonCritical},
+				{Name: "CPU Temp", SensorState: sensorStateUpperCritical},
+				{Name: "BAT", SensorState: sensorStateLowerNonCritical},
+			},
+			"CRITICAL",
+			"MB Temp,CPU Temp,BAT",
+		},
+		{
+			"multiple failing",
+			[]*Sensor{
+				{Name: "CPU Temp", SensorState: 1},
+				{Name: "IPB FAN1", Type: "fan", SensorState: 2},
+				{Name: "CPU_CATERR", SensorState: 1},
+				{Name: "CPU_THERMTRIP", SensorState: 1},
+				{Name: "CPU_PROCHOT", SensorState: 1},
+			},
+			"CRITICAL",
+			"IPB FAN1,CPU_CATERR,CPU_THERMTRIP,CPU_PROCHOT",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := common.NewDevice()
+			device.Status = &common.Status{}
+			device.Metadata = map[string]string{}
+
+			sensorsHealth(&device, tc.sensors)
+
+			assert.Equal(t, tc.health, device.Status.Health)
+			assert.Equal(t, tc.failing, device.Status.State)
+			assert.Equal(t, tc.failing, device.Metadata["health.failing_sensors"])
+		})
+	}
+}
+
+func Test_componentsHealth(t *testing.T) {
+	testcases := []struct {
+		name         string
+		cpus         int
+		sensors      []*Sensor
+		cpuHealth    []string
+		memoryHealth []string
+	}{
+		{
+			"single socket, unnumbered CPU sensors",
+			1,
+			[]*Sensor{
+				{Name: "CPU Temp", SensorState: 1},
+				{Name: "CPU_CATERR", SensorState: 1},
+				{Name: "DDR4_A1_TEMP", SensorState: 1},
+			},
+			[]string{"CRITICAL"},
+			[]string{"OK", ""},
+		},
+		{
+			"dual socket, numbered CPU sensors",
+			2,
+			[]*Sensor{
+				{Name: "CPU Temp", SensorState: sensorStateUpperCritical},
+				{Name: "CPU1_Temp", SensorState: 1},
+				{Name: "CPU2 Temp", SensorState: sensorStateUpperNonCritical},
+				{Name: "DIMM_B1 Temp", SensorState: sensorStateUpperCritical},
+			},
+			[]string{"OK", "WARNING"},
+			[]string{"", "CRITICAL"},
+		},
+		{
+			"no correlated sensors",
+			1,
+			[]*Sensor{
+				{Name: "MB Temp", SensorState: sensorStateUpperCritical},
+				{Name: "IPB FAN1", Type: "fan", SensorState: 2},
+			},
+			[]string{""},
+			[]string{"", ""},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := common.NewDevice()
+			for i := 0; i < tc.cpus; i++ {
+				device.CPUs = append(device.CPUs, &common.CPU{})
+			}
+
+			device.Memory = []*common.Memory{{Slot: "DDR4_A1"}, {Slot: "DDR4_B1"}}
+			device.Drives = []*common.Drive{{}}
+
+			componentsHealth(&device, tc.sensors)
+
+			for i, cpu := range device.CPUs {
+				if tc.cpuHealth[i] == "" {
+					assert.Nil(t, cpu.Status)
+					continue
+				}
+
+				assert.Equal(t, tc.cpuHealth[i], cpu.Status.Health)
+			}
+
+			for i, memory := range device.Memory {
+				if tc.memoryHealth[i] == "" {
+					assert.Nil(t, memory.Status)
+					continue
+				}
+
+				assert.Equal(t, tc.memoryHealth[i], memory.Status.Health)
+			}
+
+			// drives are not correlated with sensors
+			assert.Nil(t, device.Drives[0].Status)
+		})
+	}
+}
+
+func Test_componentsHealthDrives(t *testing.T) {
+	device := common.NewDevice()
+	device.Drives = []*common.Drive{
+		{ID: "M2_1"},
+		{ID: "SATA_4", Common: common.Common{Status: &common.Status{Health: "OK"}}},
+		{},
+	}
+
+	sensors := []*Sensor{
+		{Name: "M2_1 Temp", SensorState: sensorStateUpperCritical},
+		{Name: "SATA_4 Temp", SensorState: sensorStateUpperCritical},
+	}
+
+	componentsHealth(&device, sensors)
+
+	assert.Equal(t, "CRITICAL", device.Drives[0].Status.Health)
+	assert.Equal(t, "M2_1 Temp", device.Drives[0].Status.State)
+
+	// the status set from the SMART data is retained
+	assert.Equal(t, "OK", device.Drives[1].Status.Health)
+	assert.Nil(t, device.Drives[2].Status)
+}
+
+func Test_InventoryDeadline(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/fw-info", fwinfo)
+	handler.HandleFunc("/api/fru", fruinfo)
+	handler.HandleFunc("/api/asrr/inventory_info", inventoryinfo)
+	handler.HandleFunc("/api/sensors", func(w http.ResponseWriter, r *http.Request) {
+		// hung BMC
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	})
+
+	slowServer := httptest.NewTLSServer(handler)
+	defer slowServer.Close()
+
+	slowURL, _ := url.Parse(slowServer.URL)
+	client := NewWithOptions(slowURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	device, err := client.Inventory(ctx)
+
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.ErrorIs(t, err, ErrInventoryTimeout)
+	assert.NotNil(t, device)
+
+	// the cause remains in the error chain
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var endpointErr *EndpointError
+	if assert.ErrorAs(t, err, &endpointErr) {
+		assert.Equal(t, "sensors", endpointErr.Op)
+	}
+
+	// attributes collected before the deadline are included
+	assert.Equal(t, "E3C246D4I-NL", device.Model)
+	assert.Equal(t, "L2.07B", device.BIOS.Firmware.Installed)
+}
+
+func Test_InventoryBestEffort(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/fw-info", fwinfo)
+	handler.HandleFunc("/api/fru", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	handler.HandleFunc("/api/asrr/inventory_info", inventoryinfo)
+	handler.HandleFunc("/api/sensors", sensorsinfo)
+
+	flakyServer := httptest.NewTLSServer(handler)
+	defer flakyServer.Close()
+
+	flakyURL, _ := url.Parse(flakyServer.URL)
+
+	// strict
+	client := NewWithOptions(flakyURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	device, err := client.Inventory(context.TODO())
+	assert.NotNil(t, err)
+	assert.Nil(t, device)
+
+	// best effort
+	client = NewWithOptions(flakyURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithBestEffortInventory(), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	device, err = client.Inventory(context.TODO())
+	assert.NotNil(t, err)
+	assert.NotNil(t, device)
+
+	merr := &multierror.Error{}
+	assert.True(t, errors.As(err, &merr))
+	assert.Equal(t, 2, len(merr.Errors))
+	assert.Contains(t, merr.Errors[0].Error(), "fru")
+	assert.Contains(t, merr.Errors[1].Error(), "psu")
+
+	assert.Equal(t, "L2.07B", device.BIOS.Firmware.Installed)
+	assert.Equal(t, 1, len(device.CPUs))
+	assert.Equal(t, "OK", device.Status.Health)
+}
+
+func Test_InventoryEndpointError(t *testing.T) {
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse("api/asrr/fw-info", http.StatusInternalServerError, nil),
+	)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	// the credentials in the base URL are not included in the error
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(),
+		WithBaseURL("https://admin:secret@"+serverURL.Host+"/"))
+
+	_, err := client.Inventory(context.TODO())
+	assert.NotNil(t, err)
+
+	var endpointErr *EndpointError
+	if !errors.As(err, &endpointErr) {
+		t.Fatalf("expected an EndpointError, got: %v", err)
+	}
+
+	assert.Equal(t, "firmware info", endpointErr.Op)
+	assert.Equal(t, "https://"+serverURL.Host+"/api/asrr/fw-info", endpointErr.URL)
+	assert.NotContains(t, err.Error(), "secret")
+
+	// the status code error is wrapped
+	var statusErr *statusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusInternalServerError, statusErr.statusCode)
+}
+
+func Test_InventoryRequestTimeout(t *testing.T) {
+	// the FRU endpoint responds after the request timeout
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/fw-info", fwinfo)
+	handler.HandleFunc("/api/fru", slow)
+	handler.HandleFunc("/api/asrr/inventory_info", inventoryinfo)
+	handler.HandleFunc("/api/sensors", sensorsinfo)
+
+	slowServer := httptest.NewTLSServer(handler)
+	defer slowServer.Close()
+
+	slowURL, _ := url.Parse(slowServer.URL)
+	client := NewWithOptions(slowURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithBestEffortInventory(),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithRequestTimeout(100*time.Millisecond))
+
+	start := time.Now()
+
+	device, err := client.Inventory(context.TODO())
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.NotNil(t, device)
+	assert.True(t, errors.Is(err, ErrRequestTimeout))
+	assert.False(t, errors.Is(err, ErrInventoryTimeout))
+
+	// the sections not depending on the slow endpoint are collected
+	assert.Equal(t, "L2.07B", device.BIOS.Firmware.Installed)
+	assert.Equal(t, 1, len(device.CPUs))
+
+	// cancelling the parent context cancels the in flight request
+	client = NewWithOptions(slowURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithRequestTimeout(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start = time.Now()
+
+	_, err = client.fruInfo(ctx)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.False(t, errors.Is(err, ErrRequestTimeout))
+}
+
+func Test_InventorySkip(t *testing.T) {
+	mock := mockASRockBMC()
+	defer mock.Close()
+
+	var mu sync.Mutex
+	requested := map[string]int{}
+
+	recordingServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path]++
+		mu.Unlock()
+
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer recordingServer.Close()
+
+	recordingURL, _ := url.Parse(recordingServer.URL)
+	client := NewWithOptions(
+		recordingURL.Host,
+		"foo",
+		"bar",
+		logr.Discard(),
+		WithInsecureSkipVerify(),
+		WithInventorySkip(InventorySectionComponents, InventorySectionPSU, InventorySectionHealth),
+	)
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// firmware versions and FRU attributes are collected
+	assert.Equal(t, "E3C246D4I-NL", device.Model)
+	assert.Equal(t, "L2.07B", device.BIOS.Firmware.Installed)
+	assert.Equal(t, 2, len(device.BMC.NIC.NICPorts))
+
+	// skipped sections are left empty
+	assert.Empty(t, device.CPUs)
+	assert.Empty(t, device.NICs)
+	assert.Empty(t, device.Memory)
+	assert.Empty(t, device.Drives)
+	assert.Empty(t, device.PSUs)
+	assert.Equal(t, "", device.Status.Health)
+
+	assert.Equal(t, 0, requested["/api/asrr/inventory_info"])
+	assert.Equal(t, 0, requested["/api/sensors"])
+	assert.Equal(t, 0, requested["/api/asrr/getbioscode"])
+	assert.Equal(t, 1, requested["/api/fru"])
+}
+
+func Test_InventorySkipHealth(t *testing.T) {
+	mock := mockASRockBMC()
+	defer mock.Close()
+
+	var mu sync.Mutex
+	requested := map[string]int{}
+
+	recordingServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path]++
+		mu.Unlock()
+
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer recordingServer.Close()
+
+	recordingURL, _ := url.Parse(recordingServer.URL)
+	client := NewWithOptions(recordingURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithInventorySkip(InventorySectionHealth))
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the remaining sections are collected without the sensors
+	assert.Equal(t, "E3C246D4I-NL", device.Model)
+	assert.Equal(t, 1, len(device.CPUs))
+	assert.Nil(t, device.CPUs[0].Status)
+	assert.Equal(t, "", device.Status.Health)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, 0, requested["/api/sensors"])
+	assert.Equal(t, 1, requested["/api/fru"])
+}
+
+func Test_InventoryPSUs(t *testing.T) {
+	psuFRU := []byte(`[
+		{ "device": { "id": 0, "name": "BMC_FRU" },
+		  "board": { "manufacturer": "ASRockRack", "product_name": "E3C246D4I-NL", "serial_number": "197965920000514" } },
+		{ "device": { "id": 1, "name": "PSU1_FRU" },
+		  "product": { "manufacturer": "Delta", "product_name": "CRPS 800W", "serial_number": "PSU0001" } }
+	]`)
+	psuSensors := []byte(`[ { "id": 1, "sensor_number": 100, "name": "PSU1_Status", "type": "power_supply", "sensor_state": 1 } ]`)
+
+	mock := mockASRockBMC()
+	defer mock.Close()
+
+	var mu sync.Mutex
+	requested := map[string]int{}
+
+	recordingServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/fru":
+			_, _ = w.Write(psuFRU)
+		case "/api/sensors":
+			_, _ = w.Write(psuSensors)
+		default:
+			mock.Config.Handler.ServeHTTP(w, r)
+		}
+	}))
+	defer recordingServer.Close()
+
+	recordingURL, _ := url.Parse(recordingServer.URL)
+
+	testcases := []struct {
+		name     string
+		skip     []InventorySection
+		fru      int
+		sensors  int
+		serial   string
+		health   string
+		noStatus bool
+	}{
+		{"all sections", nil, 1, 1, "197965920000514", "OK", false},
+		{"fru skipped", []InventorySection{InventorySectionFRU}, 1, 1, "", "OK", false},
+		{"health skipped", []InventorySection{InventorySectionHealth}, 1, 0, "197965920000514", "", true},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			requested = map[string]int{}
+			mu.Unlock()
+
+			client := NewWithOptions(recordingURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithInventorySkip(tc.skip...))
+
+			device, err := client.Inventory(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.serial, device.Serial)
+
+			if assert.Equal(t, 1, len(device.PSUs)) {
+				assert.Equal(t, "PSU1", device.PSUs[0].ID)
+				assert.Equal(t, "PSU0001", device.PSUs[0].Serial)
+
+				if tc.noStatus {
+					assert.Nil(t, device.PSUs[0].Status)
+				} else if assert.NotNil(t, device.PSUs[0].Status) {
+					assert.Equal(t, tc.health, device.PSUs[0].Status.Health)
+				}
+			}
+
+			// the PSUs are built from the FRU records and sensors collected by the other sections
+			mu.Lock()
+			defer mu.Unlock()
+
+			assert.Equal(t, tc.fru, requested["/api/fru"])
+			assert.Equal(t, tc.sensors, requested["/api/sensors"])
+		})
+	}
+}
+
+func Test_fruAttributesMultiNode(t *testing.T) {
+	multiNodeFRU := []byte(`[
+		{ "device": { "id": 0, "name": "BMC_FRU" },
+		  "chassis": { "type": "Multi-system Chassis", "serial_number": "CHASSIS0001" },
+		  "board": { "manufacturer": "ASRockRack", "product_name": "2U4N-F/ROME", "serial_number": "BOARD0001" },
+		  "product": { "manufacturer": "ASRockRack", "product_name": "2U4N-F", "serial_number": "PRODUCT0001" } },
+		{ "device": { "id": 1, "name": "NODE2_FRU" },
+		  "chassis": { "type": "Sub Chassis", "serial_number": "CHASSIS0002" },
+		  "board": { "manufacturer": "ASRockRack", "product_name": "2U4N-F/ROME", "serial_number": "BOARD0002" } },
+		{ "device": { "id": 2, "name": "PSU1_FRU" },
+		  "product": { "manufacturer": "Delta", "product_name": "CRPS 1600W", "serial_number": "PSU0001" } }
+	]`)
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/fru", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(multiNodeFRU)
+	})
+
+	multiNodeServer := httptest.NewTLSServer(handler)
+	defer multiNodeServer.Close()
+
+	multiNodeURL, _ := url.Parse(multiNodeServer.URL)
+	client := NewWithOptions(multiNodeURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device := common.NewDevice()
+	device.Metadata = map[string]string{}
+
+	_, err := client.fruAttributes(context.TODO(), &device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "BOARD0001", device.Serial)
+	assert.Equal(t, "2U4N-F/ROME", device.Model)
+	assert.Equal(t, "PRODUCT0001", device.Metadata["product.serialnumber"])
+	assert.Equal(t, 2, len(device.Enclosures))
+	assert.Equal(t, "CHASSIS0001", device.Enclosures[0].Serial)
+	assert.Equal(t, "CHASSIS0002", device.Enclosures[1].Serial)
+	assert.Equal(t, "NODE2_FRU", device.Enclosures[1].ID)
+}
+
+func Test_valuePlaceholder(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected bool
+	}{
+		{"", true},
+		{" N/A ", true},
+		{"None", true},
+		{"UNKNOWN", true},
+		{"-", true},
+		// all zero values are values
+		{"00000000", false},
+		{"0", false},
+		{"0.00", false},
+		{"2724B52D", false},
+		{"SATA_4", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, valuePlaceholder(tc.value))
+		})
+	}
+}
+
+func Test_versionPlaceholder(t *testing.T) {
+	testCases := []struct {
+		version  string
+		expected bool
+	}{
+		{"", true},
+		{"N/A", true},
+		{"None", true},
+		{"-", true},
+		{"0", true},
+		{"0.00", true},
+		{" 00.00.00 ", true},
+		{"L2.07B", false},
+		{"1.00", false},
+		{"000000ca", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			assert.Equal(t, tc.expected, versionPlaceholder(tc.version))
+		})
+	}
+}
+
+func Test_normalizeNA(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"N/A", ""},
+		{"n/a", ""},
+		{" N/A ", ""},
+		{"NA", ""},
+		{"Not Available", ""},
+		{"Not Specified", ""},
+		{"To Be Filled By O.E.M.", ""},
+		{"Default string", ""},
+		{"", ""},
+		{"   ", ""},
+		// meaningful values are retained
+		{"18ASF2G72HZ-2G6E1   ", "18ASF2G72HZ-2G6E1"},
+		{"0", "0"},
+		{"None", "None"},
+		{"N/A Corporation", "N/A Corporation"},
+		{"NAS", "NAS"},
+		{"Micron", "Micron"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, normalizeNA(tc.value))
+		})
+	}
+}
+
+func Test_inventoryNormalized(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/inventory_info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{ "device_id": 5, "device_name": "DDR4_A1", "device_type": "Memory", "product_manufacturer_name": "Micron", "product_name": "N/A", "product_part_number": "18ASF2G72HZ-2G6E1   ", "product_version": "N/A", "product_serial_number": "2724B52D", "product_asset_tag": "N/A", "product_extra": "N/A" },
+			{ "device_id": 120, "device_name": "GPU1", "device_type": "GPU", "product_manufacturer_name": "NVIDIA Corporation", "product_name": "NVIDIA A100-PCIE-40GB", "product_part_number": "N/A", "product_version": "N/A", "product_serial_number": "N/A", "product_asset_tag": "PCIE1", "product_extra": "N/A" }
+		]`))
+	})
+	handler.HandleFunc("/api/fru", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[ { "device": { "id": 0, "name": "BMC_FRU" },
+			"chassis": { "type": "Main Server Chassis", "serial_number": "To Be Filled By O.E.M." },
+			"board": { "manufacturer": "ASRockRack", "product_name": "E3C246D4I-NL", "serial_number": "197965920000514" },
+			"product": { "manufacturer": "ASRockRack", "product_name": "Default string", "part_number": "N/A", "product_version": " R1.00 ", "serial_number": "Not Specified" } } ]`))
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	components, err := client.inventoryInfo(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device := common.NewDevice()
+	device.Metadata = map[string]string{}
+
+	componentAttributes(&device, &firmwareInfo{}, components)
+
+	// the device name and type identifying the component are retained
+	assert.Equal(t, "DDR4_A1", device.Memory[0].Slot)
+	assert.Equal(t, "", device.Memory[0].Description)
+	assert.Equal(t, "", device.Memory[0].FormFactor)
+	assert.Equal(t, "18ASF2G72HZ-2G6E1", device.Memory[0].PartNumber)
+	assert.Equal(t, "", device.GPUs[0].Serial)
+	assert.Nil(t, device.GPUs[0].Firmware)
+
+	if _, err := client.fruAttributes(context.TODO(), &device); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "197965920000514", device.Serial)
+	assert.Equal(t, "", device.Enclosures[0].Serial)
+	assert.Equal(t, "Main Server Chassis", device.Enclosures[0].Description)
+	assert.Equal(t, "", device.Metadata["product.name"])
+	assert.Equal(t, "", device.Metadata["product.part_number"])
+	assert.Equal(t, "R1.00", device.Metadata["product.version"])
+	assert.Equal(t, "", device.Metadata["product.serialnumber"])
+}
+
+func Test_cplds(t *testing.T) {
+	device := common.NewDevice()
+	device.Vendor = "ASRockRack"
+	device.Model = "E3C246D4I-NL"
+
+	testCases := []struct {
+		name     string
+		version  string
+		expected []string
+		names    []string
+	}{
+		{"single", "1.02", []string{"1.02"}, []string{""}},
+		{"multiple", "1.02, 2.01", []string{"1.02", "2.01"}, []string{"", ""}},
+		{"multiple named", "CPLD1: 1.02; CPLD2: 2.01", []string{"1.02", "2.01"}, []string{"CPLD1", "CPLD2"}},
+		{"placeholder N/A", "N/A", []string{}, []string{}},
+		{"placeholder empty", "", []string{}, []string{}},
+		{"placeholder zero", "0.00", []string{}, []string{}},
+		{"multiple with placeholder", "1.02|0.00", []string{"1.02"}, []string{""}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := cplds(&device, tc.version)
+
+			versions := []string{}
+			names := []string{}
+			for _, cpld := range got {
+				assert.Equal(t, device.Vendor, cpld.Vendor)
+				versions = append(versions, cpld.Firmware.Installed)
+				names = append(names, cpld.Description)
+			}
+
+			assert.Equal(t, tc.expected, versions)
+			assert.Equal(t, tc.names, names)
+		})
+	}
+}
+
+func Test_InventoryDualCPLD(t *testing.T) {
+	fwInfo, err := os.ReadFile("./fixtures/ROMED8-2T/firmware-info-dual-cpld.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inventory, err := os.ReadFile("./fixtures/ROMED8-2T/inventory-info-dual-cpld.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse("api/asrr/fw-info", http.StatusOK, fwInfo),
+		asrockracktest.WithResponse("api/asrr/inventory_info", http.StatusOK, inventory),
+	)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the main board CPLD is correlated by name, the backplane CPLD by order
+	assert.Equal(t, []*common.CPLD{
+		{
+			Common: common.Common{
+				Description: "MB_CPLD",
+				Vendor:      "Lattice",
+				Model:       "LCMXO3LF-4300C",
+				Firmware:    &common.Firmware{Installed: "1.02"},
+			},
+		},
+		{
+			Common: common.Common{
+				Description: "BPB_CPLD",
+				Vendor:      "Lattice",
+				Model:       "LCMXO2-1200HC",
+				Firmware:    &common.Firmware{Installed: "0.05"},
+			},
+		},
+	}, device.CPLDs)
+}
+
+func Test_cpldAttributes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		version    string
+		components []*component
+		expected   []*common.CPLD
+	}{
+		{
+			"single unnamed",
+			"1.02",
+			[]*component{
+				{DeviceName: "CPLD", DeviceType: "CPLD", ProductManufacturerName: "Lattice", ProductName: "LCMXO2", ProductVersion: "1.02", ProductSerialNumber: "N/A"},
+			},
+			[]*common.CPLD{
+				{Common: common.Common{Description: "CPLD", Vendor: "Lattice", Model: "LCMXO2", Firmware: &common.Firmware{Installed: "1.02"}}},
+			},
+		},
+		{
+			"component without firmware info",
+			"N/A",
+			[]*component{
+				{DeviceName: "CPLD", DeviceType: "CPLD", ProductManufacturerName: "Lattice", ProductName: "LCMXO2", ProductVersion: "2.01"},
+				{DeviceName: "CPLD2", DeviceType: "CPLD", ProductManufacturerName: "N/A", ProductName: "N/A", ProductVersion: "N/A"},
+			},
+			[]*common.CPLD{
+				{Common: common.Common{Description: "CPLD", Vendor: "Lattice", Model: "LCMXO2", Firmware: &common.Firmware{Installed: "2.01"}}},
+			},
+		},
+		{
+			"firmware info without component",
+			"CPLD1: 1.02; CPLD2: 2.01",
+			[]*component{
+				{DeviceName: "CPLD 2", DeviceType: "CPLD", ProductManufacturerName: "Lattice", ProductName: "LCMXO2", ProductSerialNumber: "L123"},
+			},
+			[]*common.CPLD{
+				{Common: common.Common{Description: "CPLD1", Vendor: "ASRockRack", Model: "ROMED8-2T", Firmware: &common.Firmware{Installed: "1.02"}}},
+				{Common: common.Common{Description: "CPLD2", Vendor: "Lattice", Model: "LCMXO2", Serial: "L123", Firmware: &common.Firmware{Installed: "2.01"}}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := common.NewDevice()
+			device.Vendor = "ASRockRack"
+			device.Model = "ROMED8-2T"
+			device.CPLDs = cplds(&device, tc.version)
+
+			componentAttributes(&device, &firmwareInfo{}, tc.components)
+
+			assert.Equal(t, tc.expected, device.CPLDs)
+		})
+	}
+}
+
+func Test_driveFromComponent(t *testing.T) {
+	testCases := []struct {
+		name             string
+		partNumber       string
+		assetTag         string
+		extra            string
+		expectedProtocol string
+		expectedType     string
+		expectedCapacity int64
+	}{
+		{
+			"Intel SATA SSD",
+			"INTEL SSDSC2KB480G8",
+			"SATA_4",
+			"N/A",
+			"SATA",
+			common.SlugDriveTypeSATASSD,
+			480000000000,
+		},
+		{
+			"Intel NVMe SSD",
+			"INTEL SSDPE2KX019T8",
+			"N/A",
+			"N/A",
+			"NVMe",
+			common.SlugDriveTypePCIeNVMEeSSD,
+			1900000000000,
+		},
+		{
+			"Samsung NVMe SSD in an M.2 port",
+			"SAMSUNG MZ1LB960HAJQ-00007",
+			"M2_1",
+			"960GB",
+			"NVMe",
+			common.SlugDriveTypePCIeNVMEeSSD,
+			960000000000,
+		},
+		{
+			"Seagate SATA HDD",
+			"ST4000NM0035-1V4107",
+			"SATA_0",
+			"4 TB",
+			"SATA",
+			common.SlugDriveTypeSATAHDD,
+			4000000000000,
+		},
+		{
+			"Seagate Exos SAS HDD in a SAS port",
+			"ST4000NM0025",
+			"SAS_1",
+			"4 TB",
+			"SAS",
+			"HDD",
+			4000000000000,
+		},
+		{
+			"Seagate Exos HDD without a port",
+			"ST4000NM0025",
+			"N/A",
+			"4 TB",
+			"",
+			"HDD",
+			4000000000000,
+		},
+		{
+			"Toshiba MG SAS HDD in a SAS port",
+			"MG04SCA400E",
+			"SAS_2",
+			"4 TB",
+			"SAS",
+			"HDD",
+			4000000000000,
+		},
+		{
+			"Seagate Nytro SAS SSD in a SAS port",
+			"ST480FN0021",
+			"SAS_0",
+			"480GB",
+			"SAS",
+			"",
+			480000000000,
+		},
+		{
+			"HGST SAS HDD",
+			"HUC101212CSS600",
+			"N/A",
+			"1.2TB",
+			"SAS",
+			"HDD",
+			1200000000000,
+		},
+		{
+			"unknown drive in a SATA port",
+			"ACME DRIVE",
+			"SATA_1",
+			"N/A",
+			"SATA",
+			"",
+			0,
+		},
+		{
+			"unknown drive",
+			"ACME DRIVE",
+			"N/A",
+			"N/A",
+			"",
+			"",
+			0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drive := driveFromComponent(&component{
+				DeviceType:              "Storage device",
+				ProductManufacturerName: "N/A",
+				ProductPartNumber:       tc.partNumber,
+				ProductAssetTag:         tc.assetTag,
+				ProductExtra:            tc.extra,
+			})
+
+			assert.Equal(t, tc.expectedProtocol, drive.Protocol)
+			assert.Equal(t, tc.expectedType, drive.Type)
+			assert.Equal(t, tc.expectedCapacity, drive.CapacityBytes)
+		})
+	}
+}
+
+// BenchmarkInventory collects the inventory from a BMC mock with a 20ms response latency
+func BenchmarkInventory(b *testing.B) {
+	mock := mockASRockBMC()
+	defer mock.Close()
+
+	slowServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		mock.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer slowServer.Close()
+
+	slowURL, _ := url.Parse(slowServer.URL)
+	client := NewWithOptions(slowURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.Inventory(context.TODO()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// mockConnCountingBMC returns the mock BMC server along with a func returning the number of client connections accepted
+func mockConnCountingBMC() (server *httptest.Server, conns func() int64) {
+	// only the mock handler is served
+	mock := mockASRockBMC()
+	mock.Close()
+
+	var count int64
+
+	server = httptest.NewUnstartedServer(mock.Config.Handler)
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(&count, 1)
+		}
+	}
+
+	server.StartTLS()
+
+	return server, func() int64 { return atomic.LoadInt64(&count) }
+}
+
+// inFlightTransport counts the requests in flight, holding each request to overlap the concurrent requests
+type inFlightTransport struct {
+	transport http.RoundTripper
+	inFlight  int64
+	max       int64
+}
+
+func (t *inFlightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := atomic.AddInt64(&t.inFlight, 1)
+	defer atomic.AddInt64(&t.inFlight, -1)
+
+	for {
+		max := atomic.LoadInt64(&t.max)
+		if n <= max || atomic.CompareAndSwapInt64(&t.max, max, n) {
+			break
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	return t.transport.RoundTrip(req)
+}
+
+func Test_InventoryConcurrency(t *testing.T) {
+	testCases := []struct {
+		name  string
+		opts  []ASRockOption
+		limit int64
+	}{
+		{"default", nil, defaultInventoryConcurrency},
+		{"limited", []ASRockOption{WithInventoryConcurrency(2)}, 2},
+		{"single", []ASRockOption{WithInventoryConcurrency(1)}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := asrockracktest.NewServer()
+			defer server.Close()
+
+			transport := &inFlightTransport{transport: server.Client().Transport}
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(),
+				append(tc.opts, WithHTTPClient(&http.Client{Transport: transport}))...)
+
+			if _, err := client.Inventory(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.LessOrEqual(t, atomic.LoadInt64(&transport.max), tc.limit)
+		})
+	}
+
+	// without a limit, the requests of the concurrently collected sections are in flight at once
+	server := asrockracktest.NewServer()
+	defer server.Close()
+
+	transport := &inFlightTransport{transport: server.Client().Transport}
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(),
+		WithInventoryConcurrency(0), WithHTTPClient(&http.Client{Transport: transport}))
+
+	if _, err := client.Inventory(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Greater(t, atomic.LoadInt64(&transport.max), int64(defaultInventoryConcurrency))
+}
+
+func Test_InventoryConnectionReuse(t *testing.T) {
+	// without connection reuse, each request is made over a new connection
+	server, conns := mockConnCountingBMC()
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithConnectionPool(0, 0))
+
+	if _, err := client.Inventory(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	withoutReuse := conns()
+	assert.Greater(t, withoutReuse, int64(defaultMaxIdleConns))
+
+	server, conns = mockConnCountingBMC()
+	defer server.Close()
+
+	serverURL, _ = url.Parse(server.URL)
+	client = NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Inventory(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the connections of the concurrently collected sections are reused across collections,
+	// a connection may be dialed while another is being returned to the pool, hence the loose bound
+	assert.Less(t, conns(), withoutReuse)
+}
+
+// BenchmarkInventoryConnections reports the connections made to the BMC per inventory collection
+func BenchmarkInventoryConnections(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts []ASRockOption
+	}{
+		{"keep-alive disabled", []ASRockOption{WithConnectionPool(0, 0)}},
+		{"connection pool", nil},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			server, conns := mockConnCountingBMC()
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			opts := append([]ASRockOption{WithInsecureSkipVerify()}, bm.opts...)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), opts...)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := client.Inventory(context.TODO()); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(conns())/float64(b.N), "conns/op")
+		})
+	}
+}
+
+func Test_InventoryPowerState(t *testing.T) {
+	for on, expected := range map[bool]string{true: "On", false: "Off"} {
+		server := asrockracktest.NewServer(asrockracktest.WithPowerState(on))
+
+		serverURL, _ := url.Parse(server.URL)
+		client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+		device, err := client.Inventory(context.TODO())
+		server.Close()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the inventory is collected from a powered off node
+		assert.Equal(t, "E3C246D4I-NL", device.Model)
+		assert.Equal(t, expected, device.Metadata["power.state"])
+	}
+}
+
+func Test_InventoryFirmwareBanks(t *testing.T) {
+	fwInfo := []byte(`{
+		"BMC_fw_version": "1.20.00",
+		"BIOS_fw_version": "L2.07B",
+		"CPLD_version": "N/A",
+		"Node_id": "2",
+		"BMC_build_date": "2022-04-19 15:32:10",
+		"BMC_active_image": 2,
+		"BMC_primary_fw_version": "1.21.00",
+		"BMC_backup_fw_version": "1.20.00",
+		"BIOS_build_date": "01/12/2022",
+		"BIOS_active_image": 1,
+		"BIOS_primary_fw_version": "L2.07B",
+		"BIOS_backup_fw_version": "L2.06"
+	}`)
+
+	server := asrockracktest.NewServer(asrockracktest.WithResponse("api/asrr/fw-info", http.StatusOK, fwInfo))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the BMC is running from the backup bank
+	assert.Equal(t, &common.Firmware{
+		Installed: "1.20.00",
+		Metadata: map[string]string{
+			"build_date":      "2022-04-19 15:32:10",
+			"active_bank":     "backup",
+			"primary_version": "1.21.00",
+			"backup_version":  "1.20.00",
+		},
+	}, device.BMC.Firmware)
+
+	assert.Equal(t, &common.Firmware{
+		Installed: "L2.07B",
+		Metadata: map[string]string{
+			"build_date":      "01/12/2022",
+			"active_bank":     "primary",
+			"primary_version": "L2.07B",
+			"backup_version":  "L2.06",
+		},
+	}, device.BIOS.Firmware)
+
+	// firmware without the dual image details
+	assert.Equal(t, &common.Firmware{Installed: "0.01.00"}, dualImageFirmware("0.01.00", "", 0, "N/A", ""))
+}
+
+func Test_InventoryFirmwareBanksMismatch(t *testing.T) {
+	fwInfo := func(bmcPrimary, bmcBackup string) []byte {
+		return []byte(fmt.Sprintf(`{
+			"BMC_fw_version": "1.21.00",
+			"BIOS_fw_version": "L2.07B",
+			"CPLD_version": "N/A",
+			"Node_id": "2",
+			"BMC_active_image": 1,
+			"BMC_primary_fw_version": %q,
+			"BMC_backup_fw_version": %q
+		}`, bmcPrimary, bmcBackup))
+	}
+
+	testCases := []struct {
+		name           string
+		bmcPrimary     string
+		bmcBackup      string
+		opts           []ASRockOption
+		expectMismatch bool
+		expectHealth   string
+	}{
+		{"matched", "1.21.00", "1.21.00", nil, false, "OK"},
+		{"matched with warning", "1.21.00", "1.21.00", []ASRockOption{WithFirmwareBankWarning(nil)}, false, "OK"},
+		{"mismatched", "1.21.00", "1.20.00", nil, true, "OK"},
+		{"mismatched with warning", "1.21.00", "1.20.00", []ASRockOption{WithFirmwareBankWarning(nil)}, true, "WARNING"},
+		{
+			"matched not the desired version",
+			"1.21.00", "1.21.00",
+			[]ASRockOption{WithFirmwareBankWarning(map[string]string{common.SlugBMC: "1.22.00"})},
+			true, "WARNING",
+		},
+		{
+			"matched the desired version",
+			"1.21.00", "1.21.00",
+			[]ASRockOption{WithFirmwareBankWarning(map[string]string{common.SlugBMC: "1.21.00"})},
+			false, "OK",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := asrockracktest.NewServer(asrockracktest.WithResponse("api/asrr/fw-info", http.StatusOK, fwInfo(tc.bmcPrimary, tc.bmcBackup)))
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			opts := append([]ASRockOption{WithInsecureSkipVerify()}, tc.opts...)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), opts...)
+
+			device, err := client.Inventory(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, mismatch := device.Metadata["bmc.firmware.bank_mismatch"]
+			assert.Equal(t, tc.expectMismatch, mismatch)
+			assert.Equal(t, tc.expectHealth, device.Status.Health)
+
+			// the BIOS firmware does not report the bank versions
+			assert.NotContains(t, device.Metadata, "bios.firmware.bank_mismatch")
+		})
+	}
 }
@@ -0,0 +1,212 @@
+package asrockrack
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// jsonUnmarshalerType is the type of values decoding themselves, which are not coerced
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unmarshalTolerant decodes the BMC response as json.Unmarshal does, tolerating numbers encoded as JSON strings
+// and strings encoded as JSON numbers - "Node_id": 2, "reading": "31.5", which vary across BMC firmware revisions.
+//
+// The response is decoded as is, when the decoding fails on a mismatched value type the JSON numbers and strings
+// are converted to the type of their destination field and the response is decoded again.
+// Values which cannot be converted fail the decoding with the original error.
+func unmarshalTolerant(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+
+	var typeErr *json.UnmarshalTypeError
+	if err == nil || !errors.As(err, &typeErr) {
+		return err
+	}
+
+	var value interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if decodeErr := decoder.Decode(&value); decodeErr != nil {
+		return err
+	}
+
+	coerced, marshalErr := json.Marshal(coerceJSON(value, reflect.TypeOf(v)))
+	if marshalErr != nil {
+		return err
+	}
+
+	if retryErr := json.Unmarshal(coerced, v); retryErr != nil {
+		return err
+	}
+
+	return nil
+}
+
+// coerceJSON returns the decoded JSON value with the numbers and strings converted to the kind of the destination type,
+// values of types implementing json.Unmarshaler are returned as is.
+func coerceJSON(value interface{}, t reflect.Type) interface{} {
+	if value == nil || t.Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return coerceJSON(value, t.Elem())
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+
+		fields := jsonFields(t)
+		for key, v := range object {
+			if fieldType, exists := fields[strings.ToLower(key)]; exists {
+				object[key] = coerceJSON(v, fieldType)
+			}
+		}
+
+		return object
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+
+		for key, v := range object {
+			object[key] = coerceJSON(v, t.Elem())
+		}
+
+		return object
+	case reflect.Slice, reflect.Array:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+
+		for idx, v := range list {
+			list[idx] = coerceJSON(v, t.Elem())
+		}
+
+		return list
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return coerceNumber(value, t.Kind())
+	case reflect.String:
+		if number, ok := value.(json.Number); ok {
+			return number.String()
+		}
+	case reflect.Bool:
+		return coerceBool(value)
+	}
+
+	return value
+}
+
+// coerceNumber returns the number encoded in a string - "2", "31.5", an empty string is decoded as zero.
+// Integral floats are accepted for integer kinds - 2.0.
+func coerceNumber(value interface{}, kind reflect.Kind) interface{} {
+	var s string
+
+	switch v := value.(type) {
+	case string:
+		s = strings.TrimSpace(v)
+		if s == "" {
+			return json.Number("0")
+		}
+	case json.Number:
+		s = v.String()
+	default:
+		return value
+	}
+
+	if kind == reflect.Float32 || kind == reflect.Float64 {
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(s)
+		}
+
+		return value
+	}
+
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(s)
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f == float64(int64(f)) {
+		return json.Number(strconv.FormatInt(int64(f), 10))
+	}
+
+	return value
+}
+
+// coerceBool returns the boolean encoded in a string or number - "true", "1", 0
+func coerceBool(value interface{}) interface{} {
+	var s string
+
+	switch v := value.(type) {
+	case string:
+		s = strings.TrimSpace(v)
+	case json.Number:
+		s = v.String()
+	default:
+		return value
+	}
+
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b
+	}
+
+	return value
+}
+
+// jsonFields returns the types of the struct fields decoded from JSON, keyed by the lower case JSON field name,
+// including the fields of embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range jsonFields(embedded) {
+					if _, exists := fields[k]; !exists {
+						fields[k] = v
+					}
+				}
+
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[strings.ToLower(name)] = field.Type
+	}
+
+	return fields
+}
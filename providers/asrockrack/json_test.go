@@ -0,0 +1,136 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_unmarshalTolerant(t *testing.T) {
+	testCases := []struct {
+		name     string
+		payloads []string
+		decode   func([]byte) (interface{}, error)
+		expected interface{}
+	}{
+		{
+			"firmware info",
+			[]string{
+				`{ "BMC_fw_version": "1.20.00", "Node_id": "2", "BMC_active_image": 2 }`,
+				`{ "BMC_fw_version": "1.20.00", "Node_id": 2, "BMC_active_image": "2" }`,
+			},
+			func(b []byte) (interface{}, error) { v := &firmwareInfo{}; return v, unmarshalTolerant(b, v) },
+			&firmwareInfo{BMCVersion: "1.20.00", NodeID: "2", BMCActiveImage: 2},
+		},
+		{
+			"sensors",
+			[]string{
+				`[ { "id": 1, "sensor_number": 5, "name": "CPU_TEMP", "reading": 31.5, "sensor_state": 1, "accessible": 0 } ]`,
+				`[ { "id": "1", "sensor_number": "5", "name": "CPU_TEMP", "reading": "31.5", "sensor_state": 1, "accessible": "" } ]`,
+			},
+			func(b []byte) (interface{}, error) { v := []*Sensor{}; return v, unmarshalTolerant(b, &v) },
+			[]*Sensor{{ID: 1, SensorNumber: 5, Name: "CPU_TEMP", Reading: 31.5, SensorState: 1}},
+		},
+		{
+			"inventory components",
+			[]string{
+				`[ { "device_id": 105, "device_type": "Storage device", "product_part_number": "1572" } ]`,
+				`[ { "device_id": "105", "device_type": "Storage device", "product_part_number": 1572 } ]`,
+			},
+			func(b []byte) (interface{}, error) { v := []*component{}; return v, unmarshalTolerant(b, &v) },
+			[]*component{{DeviceID: 105, DeviceType: "Storage device", ProductPartNumber: "1572"}},
+		},
+		{
+			"chassis status",
+			[]string{
+				`{ "power_status": 1, "led_status": 0 }`,
+				`{ "power_status": "1", "led_status": 0.0 }`,
+			},
+			func(b []byte) (interface{}, error) { v := &chassisStatus{}; return v, unmarshalTolerant(b, v) },
+			&chassisStatus{PowerStatus: 1},
+		},
+		{
+			"session",
+			[]string{
+				`{ "ok": 0, "privilege": 4, "csrftoken": "token" }`,
+				`{ "ok": 0, "privilege": "4", "csrftoken": "token" }`,
+			},
+			func(b []byte) (interface{}, error) { v := &loginSession{}; return v, unmarshalTolerant(b, v) },
+			&loginSession{Privilege: 4, CSRFToken: "token"},
+		},
+		{
+			"backplane bays",
+			[]string{
+				`[ { "id": 1, "bays": [ { "bay": 7, "present": true, "drive_serial": "S4YN" } ] } ]`,
+				`[ { "id": "1", "bays": [ { "bay": "7", "present": "true", "drive_serial": "S4YN" } ] } ]`,
+			},
+			func(b []byte) (interface{}, error) { v := []*backplane{}; return v, unmarshalTolerant(b, &v) },
+			[]*backplane{{ID: 1, Bays: []*backplaneBay{{Bay: 7, Present: true, DriveSerial: "S4YN"}}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, payload := range tc.payloads {
+				decoded, err := tc.decode([]byte(payload))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, tc.expected, decoded, payload)
+			}
+		})
+	}
+}
+
+func Test_unmarshalTolerantInvalid(t *testing.T) {
+	// values which cannot be converted fail with the original error
+	err := unmarshalTolerant([]byte(`{ "power_status": "on", "led_status": 0 }`), &chassisStatus{})
+
+	typeErr := &json.UnmarshalTypeError{}
+	assert.ErrorAs(t, err, &typeErr)
+	assert.Equal(t, "power_status", typeErr.Field)
+
+	// syntax errors are returned as is
+	err = unmarshalTolerant([]byte(`{ "power_status": `), &chassisStatus{})
+	syntaxErr := &json.SyntaxError{}
+	assert.ErrorAs(t, err, &syntaxErr)
+}
+
+func Test_InventoryStringEncodedNumbers(t *testing.T) {
+	fwInfo := []byte(`{
+		"BMC_fw_version": "0.01.00",
+		"BIOS_fw_version": "L2.07B",
+		"CPLD_version": "N/A",
+		"Node_id": 2,
+		"BMC_active_image": "1",
+		"BMC_primary_fw_version": "0.01.00",
+		"BMC_backup_fw_version": "0.01.00"
+	}`)
+
+	chassisStatus := []byte(`{ "power_status": "1", "led_status": "0" }`)
+
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse("api/asrr/fw-info", http.StatusOK, fwInfo),
+		asrockracktest.WithResponse("api/chassis-status", http.StatusOK, chassisStatus),
+	)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "2", device.Metadata["node_id"])
+	assert.Equal(t, "primary", device.BMC.Firmware.Metadata["active_bank"])
+	assert.Equal(t, "On", device.Metadata["power.state"])
+}
@@ -0,0 +1,86 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	chassisIdentifyEndpoint = "api/actions/chassis-identify"
+
+	// maxLocatorLEDDuration is the longest identify interval supported by the IPMI chassis identify command
+	maxLocatorLEDDuration = 255 * time.Second
+)
+
+// chassisIdentify is the chassis identify payload
+//
+// ForceOn turns the identify LED on until turned off, else the LED is turned on for the Interval in seconds,
+// an Interval of 0 turns the LED off.
+type chassisIdentify struct {
+	Interval int `json:"identify_interval"`
+	ForceOn  int `json:"force_on"`
+}
+
+// GetLocatorLED returns true if the chassis identify LED is on
+func (a *ASRockRack) GetLocatorLED(ctx context.Context) (on bool, err error) {
+	status, err := a.chassisStatusInfo(ctx)
+	if err != nil {
+		return false, errors.Wrap(ErrLocatorLED, err.Error())
+	}
+
+	// 0 - off, 1 - on for the identify interval, 2 - on until turned off
+	return status.LEDStatus != 0, nil
+}
+
+// SetLocatorLED turns the chassis identify LED on until turned off, or turns it off.
+//
+// ErrLocatorLEDUnsupported is returned when the BMC firmware does not expose the chassis identify control.
+func (a *ASRockRack) SetLocatorLED(ctx context.Context, on bool) error {
+	identify := &chassisIdentify{}
+	if on {
+		identify.ForceOn = 1
+	}
+
+	return a.chassisIdentify(ctx, identify)
+}
+
+// SetLocatorLEDDuration turns the chassis identify LED on for the given duration, rounded up to the second,
+// the BMC turns the LED off once the duration elapses. The duration is limited to 255 seconds by the BMC.
+func (a *ASRockRack) SetLocatorLEDDuration(ctx context.Context, d time.Duration) error {
+	if d <= 0 || d > maxLocatorLEDDuration {
+		return errors.Wrap(ErrLocatorLED, fmt.Sprintf("duration %s not within 1s - %s", d, maxLocatorLEDDuration))
+	}
+
+	interval := int((d + time.Second - 1) / time.Second)
+
+	return a.chassisIdentify(ctx, &chassisIdentify{Interval: interval})
+}
+
+func (a *ASRockRack) chassisIdentify(ctx context.Context, identify *chassisIdentify) error {
+	payload, err := json.Marshal(identify)
+	if err != nil {
+		return errors.Wrap(ErrLocatorLED, err.Error())
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	_, statusCode, err := a.queryHTTPS(ctx, chassisIdentifyEndpoint, "POST", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return errors.Wrap(ErrLocatorLED, err.Error())
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return wrapStatusCodeError(ErrLocatorLEDUnsupported, statusCode)
+	default:
+		return wrapStatusCodeError(ErrLocatorLED, statusCode)
+	}
+}
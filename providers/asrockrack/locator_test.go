@@ -0,0 +1,119 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLocatorBMC returns a BMC mock reporting the chassis identify state set with the chassis identify requests
+func mockLocatorBMC() (*httptest.Server, func() *chassisIdentify) {
+	var mu sync.Mutex
+	var last *chassisIdentify
+	ledStatus := 0
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, _ = w.Write([]byte(fmt.Sprintf(`{ "power_status": 1, "led_status": %d }`, ledStatus)))
+	})
+
+	handler.HandleFunc("/api/actions/chassis-identify", func(w http.ResponseWriter, r *http.Request) {
+		identify := &chassisIdentify{}
+		if r.Method != "POST" || json.NewDecoder(r.Body).Decode(identify) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		last = identify
+
+		switch {
+		case identify.ForceOn == 1:
+			ledStatus = 2
+		case identify.Interval > 0:
+			ledStatus = 1
+		default:
+			ledStatus = 0
+		}
+	})
+
+	lastRequest := func() *chassisIdentify {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return last
+	}
+
+	return httptest.NewTLSServer(handler), lastRequest
+}
+
+func Test_LocatorLED(t *testing.T) {
+	server, lastRequest := mockLocatorBMC()
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	on, err := client.GetLocatorLED(context.TODO())
+	assert.Nil(t, err)
+	assert.False(t, on)
+
+	// on
+	err = client.SetLocatorLED(context.TODO(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, &chassisIdentify{ForceOn: 1}, lastRequest())
+
+	on, err = client.GetLocatorLED(context.TODO())
+	assert.Nil(t, err)
+	assert.True(t, on)
+
+	// off
+	err = client.SetLocatorLED(context.TODO(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, &chassisIdentify{}, lastRequest())
+
+	on, err = client.GetLocatorLED(context.TODO())
+	assert.Nil(t, err)
+	assert.False(t, on)
+
+	// on for a duration, rounded up to the second
+	err = client.SetLocatorLEDDuration(context.TODO(), 1500*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, &chassisIdentify{Interval: 2}, lastRequest())
+
+	on, err = client.GetLocatorLED(context.TODO())
+	assert.Nil(t, err)
+	assert.True(t, on)
+
+	err = client.SetLocatorLEDDuration(context.TODO(), 5*time.Minute)
+	assert.ErrorIs(t, err, ErrLocatorLED)
+}
+
+func Test_LocatorLEDUnsupported(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	err := client.SetLocatorLED(context.TODO(), true)
+	assert.ErrorIs(t, err, ErrLocatorLEDUnsupported)
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	err = client.SetLocatorLEDDuration(context.TODO(), 10*time.Second)
+	assert.ErrorIs(t, err, ErrLocatorLEDUnsupported)
+}
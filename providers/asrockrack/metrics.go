@@ -0,0 +1,66 @@
+package asrockrack
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetricsRecorder is implemented by metrics collectors to observe the BMC requests made by the provider,
+// which allows exporting request counts, latencies and error rates without a dependency on a metrics library.
+type MetricsRecorder interface {
+	// ObserveDuration is invoked once each BMC request completes, with the operation -
+	// the request method and endpoint "GET api/sensors", the request duration and the request error.
+	// The query string is left out of the endpoint and the numeric path segments are replaced with "{id}" -
+	// "PUT api/settings/users/{id}", to keep the number of distinct operations bounded.
+	//
+	// The error is the status code error for responses with a 4xx or 5xx status code.
+	ObserveDuration(op string, d time.Duration, err error)
+}
+
+// noopMetrics is the default MetricsRecorder discarding all observations
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveDuration(string, time.Duration, error) {}
+
+// WithMetrics sets the metrics recorder to observe the BMC requests made by the provider,
+// a nil recorder leaves the default recorder which discards the observations.
+func WithMetrics(recorder MetricsRecorder) ASRockOption {
+	return func(ar *ASRockRack) {
+		if recorder != nil {
+			ar.metrics = recorder
+		}
+	}
+}
+
+// observeRequest reports the BMC request to the metrics recorder
+func (a *ASRockRack) observeRequest(method, endpoint string, d time.Duration, statusCode int, err error) {
+	if err == nil && statusCode >= http.StatusBadRequest {
+		err = statusCodeError(statusCode)
+	}
+
+	a.metrics.ObserveDuration(requestOp(method, endpoint), d, err)
+}
+
+// requestOp returns the operation name for the BMC request - the method and the endpoint without the query string,
+// the numeric path segments are replaced with "{id}" - "api/settings/users/3" -> "api/settings/users/{id}".
+func requestOp(method, endpoint string) string {
+	endpoint = strings.TrimPrefix(endpoint, "/")
+
+	if idx := strings.Index(endpoint, "?"); idx >= 0 {
+		endpoint = endpoint[:idx]
+	}
+
+	segments := strings.Split(endpoint, "/")
+	for idx, segment := range segments {
+		if segment == "" {
+			continue
+		}
+
+		if strings.Trim(segment, "0123456789") == "" {
+			segments[idx] = "{id}"
+		}
+	}
+
+	return method + " " + strings.Join(segments, "/")
+}
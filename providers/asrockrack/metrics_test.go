@@ -0,0 +1,114 @@
+package asrockrack
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+type observation struct {
+	op  string
+	d   time.Duration
+	err error
+}
+
+type recordingMetrics struct {
+	mu           sync.Mutex
+	observations []observation
+}
+
+func (r *recordingMetrics) ObserveDuration(op string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.observations = append(r.observations, observation{op, d, err})
+}
+
+func Test_WithMetrics(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		_, _ = w.Write([]byte(`{ "power_status": 1, "led_status": 0 }`))
+	})
+	handler.HandleFunc("/api/asrr/fw-info", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	recorder := &recordingMetrics{}
+	client := NewWithOptions(
+		serverURL.Host,
+		"foo",
+		"bar",
+		logr.Discard(),
+		WithInsecureSkipVerify(),
+		WithMetrics(recorder),
+		WithRetryPolicy(RetryPolicy{}),
+	)
+
+	// success
+	_, err := client.PowerStateGet(context.TODO())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(recorder.observations))
+	assert.Equal(t, "GET api/chassis-status", recorder.observations[0].op)
+	assert.GreaterOrEqual(t, recorder.observations[0].d, 5*time.Millisecond)
+	assert.Nil(t, recorder.observations[0].err)
+
+	// non 200 response
+	_, err = client.firmwareInfo(context.TODO())
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 2, len(recorder.observations))
+	assert.Equal(t, "GET api/asrr/fw-info", recorder.observations[1].op)
+	assert.ErrorIs(t, recorder.observations[1].err, ErrBMCBusy)
+
+	// request error
+	server.Close()
+
+	_, err = client.PowerStateGet(context.TODO())
+	assert.NotNil(t, err)
+
+	last := recorder.observations[len(recorder.observations)-1]
+	assert.Equal(t, "GET api/chassis-status", last.op)
+	assert.NotNil(t, last.err)
+	assert.False(t, errors.Is(last.err, ErrBMCBusy))
+}
+
+func Test_WithMetricsNil(t *testing.T) {
+	client := NewWithOptions("127.0.0.1", "foo", "bar", logr.Discard(), WithMetrics(nil))
+	assert.Equal(t, noopMetrics{}, client.metrics)
+}
+
+func Test_requestOp(t *testing.T) {
+	testCases := []struct {
+		method   string
+		endpoint string
+		expected string
+	}{
+		{"GET", "api/sensors", "GET api/sensors"},
+		{"GET", "/api/chassis-status", "GET api/chassis-status"},
+		{"GET", "api/logs/event?start=100&count=100", "GET api/logs/event"},
+		{"PUT", "api/settings/users/3", "PUT api/settings/users/{id}"},
+		{"DELETE", "/api/settings/users/12?force=1", "DELETE api/settings/users/{id}"},
+		{"GET", "api/asrr/fw-info", "GET api/asrr/fw-info"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			assert.Equal(t, tc.expected, requestOp(tc.method, tc.endpoint))
+		})
+	}
+}
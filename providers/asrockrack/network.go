@@ -0,0 +1,77 @@
+package asrockrack
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NetworkConfig is the BMC network configuration
+type NetworkConfig struct {
+	Interfaces []*NetworkInterfaceConfig `json:"interfaces"`
+}
+
+// NetworkInterfaceConfig is the IP configuration of a BMC network interface
+//
+// The IPv6 attributes are populated only when IPv6 is enabled on the interface.
+type NetworkInterfaceConfig struct {
+	Name        string `json:"name"`
+	MACAddress  string `json:"mac_address"`
+	Enabled     bool   `json:"enabled"`
+	IPv4Enabled bool   `json:"ipv4_enabled"`
+	IPv4DHCP    bool   `json:"ipv4_dhcp"`
+	IPv4Address string `json:"ipv4_address,omitempty"`
+	IPv4Netmask string `json:"ipv4_netmask,omitempty"`
+	IPv4Gateway string `json:"ipv4_gateway,omitempty"`
+	IPv6Enabled bool   `json:"ipv6_enabled"`
+	IPv6DHCP    bool   `json:"ipv6_dhcp"`
+	IPv6Address string `json:"ipv6_address,omitempty"`
+	IPv6Prefix  int    `json:"ipv6_prefix,omitempty"`
+	IPv6Gateway string `json:"ipv6_gateway,omitempty"`
+	VLANEnabled bool   `json:"vlan_enabled"`
+	VLANID      int    `json:"vlan_id,omitempty"`
+}
+
+// GetBMCNetworkConfig returns the BMC network interfaces IP configuration
+func (a *ASRockRack) GetBMCNetworkConfig(ctx context.Context) (*NetworkConfig, error) {
+	interfaces, err := a.networkInterfaces(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ErrNetworkConfigRead, err.Error())
+	}
+
+	if len(interfaces) == 0 {
+		return nil, errors.Wrap(ErrNetworkConfigRead, "no network interfaces returned")
+	}
+
+	config := &NetworkConfig{}
+	for _, iface := range interfaces {
+		ifaceConfig := &NetworkInterfaceConfig{
+			Name:        iface.InterfaceName,
+			MACAddress:  strings.ToLower(iface.MACAddress),
+			Enabled:     iface.LanEnable == 1,
+			IPv4Enabled: iface.IPv4Enable == 1,
+			IPv4DHCP:    iface.IPv4DHCP == 1,
+			IPv4Address: iface.IPv4Address,
+			IPv4Netmask: iface.IPv4Subnet,
+			IPv4Gateway: iface.IPv4Gateway,
+			IPv6Enabled: iface.IPv6Enable == 1,
+			VLANEnabled: iface.VLANEnable == 1,
+		}
+
+		if ifaceConfig.IPv6Enabled {
+			ifaceConfig.IPv6DHCP = iface.IPv6DHCP == 1
+			ifaceConfig.IPv6Address = iface.IPv6Address
+			ifaceConfig.IPv6Prefix = iface.IPv6Prefix
+			ifaceConfig.IPv6Gateway = iface.IPv6Gateway
+		}
+
+		if ifaceConfig.VLANEnabled {
+			ifaceConfig.VLANID = iface.VLANID
+		}
+
+		config.Interfaces = append(config.Interfaces, ifaceConfig)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,41 @@
+package asrockrack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetBMCNetworkConfig(t *testing.T) {
+	config, err := aClient.GetBMCNetworkConfig(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 2, len(config.Interfaces))
+
+	// DHCP IPv4, static IPv6 with VLAN
+	eth0 := config.Interfaces[0]
+	assert.Equal(t, "eth0", eth0.Name)
+	assert.Equal(t, "d0:50:99:f7:84:35", eth0.MACAddress)
+	assert.True(t, eth0.IPv4DHCP)
+	assert.Equal(t, "10.230.148.171", eth0.IPv4Address)
+	assert.Equal(t, "255.255.255.0", eth0.IPv4Netmask)
+	assert.Equal(t, "10.230.148.1", eth0.IPv4Gateway)
+	assert.True(t, eth0.IPv6Enabled)
+	assert.False(t, eth0.IPv6DHCP)
+	assert.Equal(t, "2001:db8::171", eth0.IPv6Address)
+	assert.Equal(t, 64, eth0.IPv6Prefix)
+	assert.True(t, eth0.VLANEnabled)
+	assert.Equal(t, 100, eth0.VLANID)
+
+	// static IPv4, IPv6 disabled
+	eth1 := config.Interfaces[1]
+	assert.False(t, eth1.IPv4DHCP)
+	assert.Equal(t, "192.168.0.10", eth1.IPv4Address)
+	assert.False(t, eth1.IPv6Enabled)
+	assert.Equal(t, "", eth1.IPv6Address)
+	assert.False(t, eth1.VLANEnabled)
+	assert.Equal(t, 0, eth1.VLANID)
+}
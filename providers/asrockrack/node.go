@@ -0,0 +1,78 @@
+package asrockrack
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/bmclib/v2/constants"
+	"github.com/bmc-toolbox/common"
+)
+
+// NodeIdentity identifies the BMC node in the chassis topology
+type NodeIdentity struct {
+	// NodeID is the node identifier reported by the BMC
+	NodeID string
+	// ChassisSlot is the chassis slot occupied by the node, zero when not reported
+	ChassisSlot int
+	// Role is the role of the BMC in a multi-BMC chassis
+	Role constants.BMCRole
+}
+
+// GetNodeIdentity returns the node identifier, chassis slot and BMC role.
+//
+// The chassis slot is reported by the BMC of multi-node chassis, on firmware not reporting the slot
+// the slot is the numeric node identifier. The role is BMCRoleUnknown when not reported by the firmware.
+func (a *ASRockRack) GetNodeIdentity(ctx context.Context) (*NodeIdentity, error) {
+	fwInfo, err := a.firmwareInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nodeIdentity(fwInfo), nil
+}
+
+// nodeIdentity returns the node identity from the firmware info
+func nodeIdentity(fwInfo *firmwareInfo) *NodeIdentity {
+	node := &NodeIdentity{
+		NodeID: normalizeNA(fwInfo.NodeID),
+		Role:   bmcRole(fwInfo.BMCRole),
+	}
+
+	for _, slot := range []string{fwInfo.ChassisSlot, node.NodeID} {
+		if n, err := strconv.Atoi(strings.TrimSpace(slot)); err == nil && n > 0 {
+			node.ChassisSlot = n
+			break
+		}
+	}
+
+	return node
+}
+
+// bmcRole returns the BMC role for the role reported by the firmware - master, slave
+func bmcRole(role string) constants.BMCRole {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "master", "primary", "active":
+		return constants.BMCRolePrimary
+	case "slave", "secondary", "standby":
+		return constants.BMCRoleSecondary
+	case "standalone", "single":
+		return constants.BMCRoleStandalone
+	}
+
+	return constants.BMCRoleUnknown
+}
+
+// nodeIdentityMetadata includes the node identity in the device metadata,
+// the chassis slot and BMC role are included when reported.
+func nodeIdentityMetadata(device *common.Device, node *NodeIdentity) {
+	device.Metadata["node_id"] = node.NodeID
+
+	if node.ChassisSlot > 0 {
+		device.Metadata["chassis.slot"] = strconv.Itoa(node.ChassisSlot)
+	}
+
+	if node.Role != constants.BMCRoleUnknown {
+		device.Metadata["bmc.role"] = string(node.Role)
+	}
+}
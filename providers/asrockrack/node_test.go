@@ -0,0 +1,85 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/bmclib/v2/constants"
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetNodeIdentity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fixture  string
+		expected *NodeIdentity
+		metadata map[string]string
+	}{
+		{
+			"two node chassis, node 1",
+			"./fixtures/2U2N-F/firmware-info-node1.json",
+			&NodeIdentity{NodeID: "1", ChassisSlot: 1, Role: constants.BMCRolePrimary},
+			map[string]string{"node_id": "1", "chassis.slot": "1", "bmc.role": "primary"},
+		},
+		{
+			"two node chassis, node 2",
+			"./fixtures/2U2N-F/firmware-info-node2.json",
+			&NodeIdentity{NodeID: "2", ChassisSlot: 2, Role: constants.BMCRoleSecondary},
+			map[string]string{"node_id": "2", "chassis.slot": "2", "bmc.role": "secondary"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fwInfo, err := os.ReadFile(tc.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/asrr/fw-info", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(fwInfo)
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			node, err := client.GetNodeIdentity(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.expected, node)
+
+			device := common.NewDevice()
+			device.Metadata = map[string]string{}
+
+			nodeIdentityMetadata(&device, node)
+			assert.Equal(t, tc.metadata, device.Metadata)
+		})
+	}
+}
+
+func Test_nodeIdentity(t *testing.T) {
+	// the slot is the node identifier on firmware not reporting the slot, the role is not reported
+	node := nodeIdentity(&firmwareInfo{NodeID: "3"})
+	assert.Equal(t, &NodeIdentity{NodeID: "3", ChassisSlot: 3, Role: constants.BMCRoleUnknown}, node)
+
+	device := common.NewDevice()
+	device.Metadata = map[string]string{}
+
+	nodeIdentityMetadata(&device, node)
+	assert.Equal(t, map[string]string{"node_id": "3", "chassis.slot": "3"}, device.Metadata)
+
+	node = nodeIdentity(&firmwareInfo{NodeID: "N/A", BMCRole: "standalone"})
+	assert.Equal(t, &NodeIdentity{Role: constants.BMCRoleStandalone}, node)
+}
@@ -0,0 +1,132 @@
+package asrockrack
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+)
+
+// matches the PCI identifier and name the BMC lists PCIe cards with - "8086(Intel Corporation)", "020000(Ethernet controller)"
+var pciIDRegexp = regexp.MustCompile(`^\s*([0-9A-Fa-f]{4,6})\s*\((.*)\)\s*$`)
+
+// PCIeSlot is a PCIe or OCP slot and the card installed in the slot
+type PCIeSlot struct {
+	// Slot is the slot identifier - PCIE7, OCP1
+	Slot      string
+	Populated bool
+
+	// the card attributes, empty when the slot is not populated or the attribute is not reported
+	VendorID  string // the PCI vendor ID - 8086
+	Vendor    string // Intel Corporation
+	DeviceID  string // the PCI device ID - 1572
+	ClassCode string // the PCI class code - 020000
+	Class     string // Ethernet controller
+}
+
+// PCIeSlots returns the PCIe and OCP slots listed in the inventory, ordered as listed by the BMC,
+// empty slots are included with Populated false.
+func (a *ASRockRack) PCIeSlots(ctx context.Context) ([]*PCIeSlot, error) {
+	components, err := a.inventoryInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pcieSlots(components), nil
+}
+
+// pcieSlots returns the PCIe slots from the inventory info components
+func pcieSlots(components []*component) []*PCIeSlot {
+	slots := []*PCIeSlot{}
+
+	for _, component := range components {
+		if component.DeviceType != "PCIe & OCP Card" {
+			continue
+		}
+
+		slot := &PCIeSlot{Slot: strings.TrimSpace(component.ProductAssetTag)}
+		if valuePlaceholder(slot.Slot) {
+			slot.Slot = strings.TrimSpace(component.DeviceName)
+		}
+
+		slot.VendorID, slot.Vendor = pciID(component.ProductManufacturerName)
+		slot.ClassCode, slot.Class = pciID(component.ProductName)
+
+		if deviceID := strings.TrimSpace(component.ProductPartNumber); !valuePlaceholder(deviceID) {
+			slot.DeviceID = strings.ToLower(deviceID)
+		}
+
+		// empty slots are listed with N/A values
+		slot.Populated = slot.VendorID != "" || slot.Vendor != "" || slot.DeviceID != ""
+
+		slots = append(slots, slot)
+	}
+
+	return slots
+}
+
+// pciID returns the lowercased PCI identifier and the name from the value - "8086(Intel Corporation)",
+// a value without an identifier is returned as the name.
+func pciID(value string) (id, name string) {
+	value = strings.TrimSpace(value)
+	if valuePlaceholder(value) {
+		return "", ""
+	}
+
+	if matches := pciIDRegexp.FindStringSubmatch(value); matches != nil {
+		return strings.ToLower(matches[1]), strings.TrimSpace(matches[2])
+	}
+
+	return "", value
+}
+
+// pcieAttributes includes the PCIe slots in the device metadata, keyed by the slot
+//
+//	pcie.<slot>.populated    - true, false
+//	pcie.<slot>.vendor_id    - 8086
+//	pcie.<slot>.vendor       - Intel Corporation
+//	pcie.<slot>.device_id    - 1572
+//	pcie.<slot>.class_code   - 020000
+//	pcie.<slot>.class        - Ethernet controller
+//
+// and the number of slots and populated slots - pcie.slots.total, pcie.slots.populated.
+func pcieAttributes(device *common.Device, components []*component) {
+	slots := pcieSlots(components)
+	if len(slots) == 0 {
+		return
+	}
+
+	if device.Metadata == nil {
+		device.Metadata = map[string]string{}
+	}
+
+	var populated int
+
+	for _, slot := range slots {
+		key := "pcie." + sensorKey(slot.Slot)
+
+		device.Metadata[key+".populated"] = strconv.FormatBool(slot.Populated)
+		if !slot.Populated {
+			continue
+		}
+
+		populated++
+
+		for attribute, value := range map[string]string{
+			"vendor_id":  slot.VendorID,
+			"vendor":     slot.Vendor,
+			"device_id":  slot.DeviceID,
+			"class_code": slot.ClassCode,
+			"class":      slot.Class,
+		} {
+			if value != "" {
+				device.Metadata[key+"."+attribute] = value
+			}
+		}
+	}
+
+	device.Metadata["pcie.slots.total"] = strconv.Itoa(len(slots))
+	device.Metadata["pcie.slots.populated"] = strconv.Itoa(populated)
+}
@@ -0,0 +1,110 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PCIeSlots(t *testing.T) {
+	inventory, err := os.ReadFile("./fixtures/ROMED8-2T/inventory-info.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/inventory_info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(inventory)
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	slots, err := client.PCIeSlots(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*PCIeSlot{
+		{
+			Slot:      "PCIE1",
+			Populated: true,
+			VendorID:  "15b3",
+			Vendor:    "Mellanox Technologies",
+			DeviceID:  "1017",
+			ClassCode: "020000",
+			Class:     "Ethernet controller",
+		},
+		{Slot: "PCIE2"},
+		{
+			Slot:      "PCIE3",
+			Populated: true,
+			VendorID:  "1000",
+			Vendor:    "Broadcom / LSI",
+			DeviceID:  "00af",
+			ClassCode: "010700",
+			Class:     "Serial Attached SCSI controller",
+		},
+		// the slot is identified by the device name without an asset tag
+		{Slot: "OCP card 1"},
+	}
+
+	assert.Equal(t, expected, slots)
+}
+
+func Test_pcieAttributes(t *testing.T) {
+	device := common.NewDevice()
+	device.Metadata = map[string]string{}
+
+	pcieAttributes(&device, []*component{
+		{
+			DeviceName:              "PCIe card 1",
+			DeviceType:              "PCIe & OCP Card",
+			ProductManufacturerName: "8086(Intel Corporation)",
+			ProductName:             "020000(Ethernet controller)",
+			ProductPartNumber:       "1572",
+			ProductAssetTag:         "PCIE7",
+		},
+		{
+			DeviceName:              "PCIe card 2",
+			DeviceType:              "PCIe & OCP Card",
+			ProductManufacturerName: "N/A",
+			ProductName:             "N/A",
+			ProductPartNumber:       "N/A",
+			ProductAssetTag:         "PCIE8",
+		},
+		{
+			DeviceName: "CPU1",
+			DeviceType: "CPU",
+		},
+	})
+
+	assert.Equal(t, map[string]string{
+		"pcie.pcie7.populated":  "true",
+		"pcie.pcie7.vendor_id":  "8086",
+		"pcie.pcie7.vendor":     "Intel Corporation",
+		"pcie.pcie7.device_id":  "1572",
+		"pcie.pcie7.class_code": "020000",
+		"pcie.pcie7.class":      "Ethernet controller",
+		"pcie.pcie8.populated":  "false",
+		"pcie.slots.total":      "2",
+		"pcie.slots.populated":  "1",
+	}, device.Metadata)
+
+	// boards without PCIe slots listed have no PCIe metadata
+	device = common.NewDevice()
+	device.Metadata = map[string]string{}
+
+	pcieAttributes(&device, []*component{{DeviceName: "CPU1", DeviceType: "CPU"}})
+	assert.Empty(t, device.Metadata)
+}
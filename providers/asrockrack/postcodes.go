@@ -0,0 +1,81 @@
+package asrockrack
+
+import (
+	"github.com/bmc-toolbox/bmclib/v2/constants"
+)
+
+// postCodeRange maps a range of POST codes to the BIOS phase description
+type postCodeRange struct {
+	from, to    int
+	description string
+}
+
+// postCodeDescriptions are the AMI Aptio POST code ranges reported by ASRockRack BIOSes,
+// specific codes are listed ahead of the range they are part of.
+var postCodeDescriptions = []postCodeRange{
+	// SEC phase
+	{0x01, 0x01, "power on, reset type detection"},
+	{0x02, 0x02, "BIOS initialization, PXE boot"}, // no differentiation between BIOS init and PXE boot
+	{0x03, 0x0a, "CPU, chipset initialization"},
+	{0x0b, 0x0b, "cache initialization"},
+
+	// PEI phase
+	{0x10, 0x10, "PEI core started"},
+	{0x11, 0x2a, "CPU, chipset pre-memory initialization"},
+	{0x2b, 0x2f, "memory initialization"},
+	{0x31, 0x31, "memory installed"},
+	{0x32, 0x4e, "CPU, chipset post-memory initialization"},
+	{0x4f, 0x4f, "DXE IPL started"},
+	{0x53, 0x53, "memory initialization error, no usable memory detected"},
+	{0x50, 0x5f, "memory initialization error"},
+
+	// DXE phase
+	{0x60, 0x60, "DXE core started"},
+	{0x61, 0x61, "NVRAM initialization"},
+	{0x62, 0x67, "CPU DXE initialization"},
+	{0x68, 0x68, "PCI host bridge initialization"},
+	{0x69, 0x77, "chipset DXE initialization"},
+	{0x78, 0x78, "ACPI module initialization"},
+	{0x79, 0x79, "CSM initialization"},
+
+	// BDS phase
+	{0x90, 0x90, "boot device selection phase started"},
+	{0x91, 0x91, "driver connecting started"},
+	{0x92, 0x92, "PCIe bus initialization"},
+	{0x93, 0x93, "PCIe bus hot plug controller initialization"},
+	{0x94, 0x96, "PCIe bus enumeration, resource allocation"},
+	{0x97, 0x98, "console devices connect"},
+	{0x99, 0x99, "super IO initialization"},
+	{0x9a, 0x9d, "USB initialization"},
+	{0xa0, 0xa0, "OS boot, control handed to the OS loader"},
+	{0xa1, 0xa7, "storage initialization"},
+	{0xa8, 0xa9, "BIOS setup started"},
+	{0xab, 0xab, "BIOS setup, waiting for input"},
+	{0xad, 0xad, "ready to boot, boot device search"},
+	{0xae, 0xae, "legacy boot"},
+	{0xaf, 0xaf, "exit boot services"},
+	{0xb0, 0xb1, "runtime virtual address map"},
+	{0xb2, 0xb2, "legacy option ROM initialization"},
+	{0xb3, 0xb3, "system reset"},
+	{0xb4, 0xb5, "USB, PCIe hot plug"},
+	{0xb6, 0xb7, "NVRAM clean up, configuration reset"},
+	{0xd0, 0xdf, "DXE initialization error"},
+
+	// S3 resume, recovery
+	{0xe0, 0xe7, "S3 resume"},
+	{0xe8, 0xef, "S3 resume error"},
+	{0xf0, 0xf7, "firmware recovery"},
+	{0xf8, 0xff, "firmware recovery error"},
+}
+
+// PostCodeDescription returns the BIOS phase description for the POST code,
+// for codes without a known description "unknown" is returned.
+func PostCodeDescription(code int) string {
+	for _, r := range postCodeDescriptions {
+		if code >= r.from && code <= r.to {
+			return r.description
+		}
+	}
+
+	return constants.POSTCodeUnknown
+}
@@ -0,0 +1,28 @@
+package asrockrack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PostCodeDescription(t *testing.T) {
+	testCases := []struct {
+		code     int
+		expected string
+	}{
+		{0x02, "BIOS initialization, PXE boot"},
+		{0x2c, "memory initialization"},
+		{0x53, "memory initialization error, no usable memory detected"},
+		{0x55, "memory initialization error"},
+		{0x94, "PCIe bus enumeration, resource allocation"},
+		{0xa0, "OS boot, control handed to the OS loader"},
+		{0xad, "ready to boot, boot device search"},
+		{0x00, "unknown"},
+		{0x100, "unknown"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, PostCodeDescription(tc.code), tc.code)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"syscall"
+	"time"
 
 	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
 	"github.com/pkg/errors"
@@ -36,7 +39,10 @@ func (a *ASRockRack) PowerStateGet(ctx context.Context) (state string, err error
 	}
 }
 
-// PowerSet sets the hardware power state of a machine
+// PowerSet sets the hardware power state of a machine - on, off (hard), soft (ACPI graceful shutdown), reset, cycle.
+//
+// A soft power off waits for the host to power off, returning false along with ErrSoftPowerOffTimeout
+// when the host is still powered on once the soft power off timeout elapses.
 func (a *ASRockRack) PowerSet(ctx context.Context, state string) (ok bool, err error) {
 	switch strings.ToLower(state) {
 	case "on":
@@ -44,7 +50,7 @@ func (a *ASRockRack) PowerSet(ctx context.Context, state string) (ok bool, err e
 	case "off":
 		return a.powerAction(ctx, 0)
 	case "soft":
-		return a.powerAction(ctx, 5)
+		return a.softPowerOff(ctx)
 	case "reset":
 		return a.powerAction(ctx, 3)
 	case "cycle":
@@ -54,8 +60,110 @@ func (a *ASRockRack) PowerSet(ctx context.Context, state string) (ok bool, err e
 	}
 }
 
+// softPowerOff requests an ACPI graceful shutdown and polls the power state until the host is powered off.
+func (a *ASRockRack) softPowerOff(ctx context.Context) (ok bool, err error) {
+	if _, err := a.powerAction(ctx, 5); err != nil {
+		return false, err
+	}
+
+	// the power state does not change in dry run mode
+	if a.dryRun {
+		return true, nil
+	}
+
+	timeout := time.NewTimer(a.softPowerOffTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(a.powerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, errors.Wrap(ErrSoftPowerOffTimeout, ctx.Err().Error())
+		case <-timeout.C:
+			return false, errors.Wrap(ErrSoftPowerOffTimeout, a.softPowerOffTimeout.String())
+		case <-ticker.C:
+			state, err := a.PowerStateGet(ctx)
+			if err != nil {
+				a.log.V(2).Info("power state query failed", "error", err.Error())
+				continue
+			}
+
+			if state == "Off" {
+				return true, nil
+			}
+		}
+	}
+}
+
+// PowerCycle powers off the host, waits for the host to be powered off and the off delay to elapse, then powers on the host.
+//
+// Unlike the BMC power cycle action, the host is powered on only once the power off is confirmed and the rails
+// had the off delay to discharge. ErrPowerStateTimeout is returned when the power off or power on is not
+// confirmed within the power state timeout, configured with the WithPowerStateTimeout option.
+func (a *ASRockRack) PowerCycle(ctx context.Context, offDelay time.Duration) error {
+	if _, err := a.powerAction(ctx, 0); err != nil {
+		return err
+	}
+
+	// the power state does not change in dry run mode
+	if a.dryRun {
+		return nil
+	}
+
+	if err := a.waitPowerState(ctx, "Off"); err != nil {
+		return err
+	}
+
+	a.log.V(2).Info("host powered off, waiting before power on", "delay", offDelay.String())
+
+	delay := time.NewTimer(offDelay)
+	defer delay.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-delay.C:
+	}
+
+	if _, err := a.powerAction(ctx, 1); err != nil {
+		return err
+	}
+
+	return a.waitPowerState(ctx, "On")
+}
+
+// waitPowerState polls the power state until the host is in the given power state - On, Off
+func (a *ASRockRack) waitPowerState(ctx context.Context, want string) error {
+	timeout := time.NewTimer(a.powerStateTimeout)
+	defer timeout.Stop()
+
+	ticker := time.NewTicker(a.powerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		state, err := a.PowerStateGet(ctx)
+		if err != nil {
+			a.log.V(2).Info("power state query failed", "error", err.Error())
+		}
+
+		if state == want {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ErrPowerStateTimeout, ctx.Err().Error())
+		case <-timeout.C:
+			return errors.Wrap(ErrPowerStateTimeout, fmt.Sprintf("power state %s not reached within %s", want, a.powerStateTimeout))
+		case <-ticker.C:
+		}
+	}
+}
+
 func (a *ASRockRack) powerAction(ctx context.Context, action int) (ok bool, err error) {
-	endpoint := "/api/actions/power"
+	endpoint := "api/actions/power"
 
 	p := power{Command: action}
 	payload, err := json.Marshal(p)
@@ -77,22 +185,41 @@ func (a *ASRockRack) powerAction(ctx context.Context, action int) (ok bool, err
 	}
 
 	if statusCode != http.StatusOK {
-		return false, errors.Wrap(
-			bmclibErrs.ErrNon200Response,
-			fmt.Errorf("%d", statusCode).Error(),
-		)
+		return false, statusCodeError(statusCode)
 	}
 
 	return true, nil
 }
 
-// BmcReset will reset the BMC - ASRR BMCs only support a cold reset.
+// BmcReset will reset the BMC - ASRR BMCs only support a cold reset, the host power state is not affected.
+//
+// The BMC is unavailable for 2 to 3 minutes while it reboots, the connection dropped by the BMC on the reset request
+// is not considered a failure. With the WithBMCResetWait option, BmcReset returns once the BMC is reachable again
+// or returns ErrBMCResetTimeout when the wait duration elapses.
 func (a *ASRockRack) BmcReset(ctx context.Context, resetType string) (ok bool, err error) {
+	switch strings.ToLower(resetType) {
+	case "", "cold":
+	case "warm":
+		return false, errors.Wrap(bmclibErrs.ErrNotImplemented, "ASRR BMCs do not support a warm reset")
+	default:
+		return false, errors.New("requested BMC reset type unknown: " + resetType)
+	}
+
 	err = a.resetBMC(ctx)
 	if err != nil {
 		return false, err
 	}
 
+	a.log.V(2).Info("BMC cold reset initiated, the BMC is unavailable for 2 to 3 minutes")
+
+	if a.bmcResetWait == 0 || a.dryRun {
+		return true, nil
+	}
+
+	if err := a.waitBMCReachable(ctx); err != nil {
+		return false, err
+	}
+
 	return true, nil
 }
 
@@ -102,12 +229,78 @@ func (a *ASRockRack) resetBMC(ctx context.Context) error {
 
 	_, statusCode, err := a.queryHTTPS(ctx, endpoint, "POST", nil, nil, 0)
 	if err != nil {
+		// the BMC may drop the connection before responding as it resets
+		if connectionDropped(err) {
+			return nil
+		}
+
 		return err
 	}
 
 	if statusCode != http.StatusOK {
-		return fmt.Errorf("non 200 response: %d", statusCode)
+		return statusCodeError(statusCode)
 	}
 
 	return nil
 }
+
+// waitBMCReachable polls the BMC until it responds after a reset
+func (a *ASRockRack) waitBMCReachable(ctx context.Context) error {
+	waitCtx, cancel := context.WithTimeout(ctx, a.bmcResetWait)
+	defer cancel()
+
+	// the BMC may still respond right after the reset request, the first query is sent after the poll interval
+	timer := time.NewTimer(a.bmcResetPollInterval)
+	defer timer.Stop()
+
+	select {
+	case <-waitCtx.Done():
+	case <-timer.C:
+		if err := a.WaitForReady(waitCtx, a.bmcResetPollInterval); err == nil {
+			return nil
+		}
+	}
+
+	if ctx.Err() != nil {
+		return errors.Wrap(ErrBMCResetTimeout, ctx.Err().Error())
+	}
+
+	return errors.Wrap(ErrBMCResetTimeout, a.bmcResetWait.String())
+}
+
+// WaitForReady polls the BMC every pollInterval until it serves its web interface, for use after a BMC reset
+// or a BMC firmware install. ErrBMCNotReady is returned when the context is done before the BMC is ready.
+//
+// Connections refused or reset while the BMC reboots are not considered failures.
+// A pollInterval of zero or less polls at the BMC reset poll interval, 10 seconds by default.
+func (a *ASRockRack) WaitForReady(ctx context.Context, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		pollInterval = a.bmcResetPollInterval
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if a.Compatible(ctx) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ErrBMCNotReady, ctx.Err().Error())
+		case <-ticker.C:
+		}
+	}
+}
+
+// connectionDropped returns true if the error indicates the connection was closed by the remote end
+func connectionDropped(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// the http client does not always wrap the underlying error
+	return strings.Contains(err.Error(), "connection reset by peer") ||
+		strings.Contains(err.Error(), "server closed idle connection")
+}
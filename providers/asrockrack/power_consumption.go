@@ -0,0 +1,143 @@
+package asrockrack
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	common "github.com/bmc-toolbox/common"
+	"github.com/pkg/errors"
+)
+
+// psuPowerSensorRegexp matches the PSU identifier in the PSU power sensor name - PSU1_PIN, PSU2 Input Power
+var psuPowerSensorRegexp = regexp.MustCompile(`(?i)^(PSU\d+)[_ ]`)
+
+// PowerConsumption returns the host power consumption in watts read from the BMC power sensors,
+// on nodes with multiple PSUs the input power readings of all PSUs are summed.
+//
+// ErrPowerConsumptionUnsupported is returned when the BMC firmware exposes no power sensors.
+func (a *ASRockRack) PowerConsumption(ctx context.Context) (watts float64, err error) {
+	sensors, err := a.Sensors(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	watts, _, err = powerConsumption(sensors)
+
+	return watts, err
+}
+
+// PSUPowerConsumption returns the power consumption in watts of each PSU keyed by the PSU identifier - PSU1, PSU2.
+//
+// ErrPowerConsumptionUnsupported is returned when the BMC firmware exposes no PSU power sensors.
+func (a *ASRockRack) PSUPowerConsumption(ctx context.Context) (map[string]float64, error) {
+	sensors, err := a.Sensors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, psus, err := powerConsumption(sensors)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(psus) == 0 {
+		return nil, errors.Wrap(ErrPowerConsumptionUnsupported, "no PSU power sensors")
+	}
+
+	return psus, nil
+}
+
+// powerConsumption returns the total and per PSU power consumption from the power sensors
+//
+// The PSU input power sensor is preferred over the output power sensor, the total is the sum of the PSU readings,
+// on nodes without PSU power sensors the total is read from the system power sensor.
+func powerConsumption(sensors []*Sensor) (float64, map[string]float64, error) {
+	var system *Sensor
+
+	found := false
+	psus := map[string]*Sensor{}
+
+	for _, sensor := range sensors {
+		if !isPowerSensor(sensor) {
+			continue
+		}
+
+		found = true
+
+		// the sensor is not present or its reading is not available
+		if sensor.Accessible != 0 {
+			continue
+		}
+
+		matches := psuPowerSensorRegexp.FindStringSubmatch(sensor.Name)
+		if len(matches) < 2 {
+			if system == nil {
+				system = sensor
+			}
+
+			continue
+		}
+
+		id := strings.ToUpper(matches[1])
+		if current, exists := psus[id]; !exists || (!isInputPowerSensor(current) && isInputPowerSensor(sensor)) {
+			psus[id] = sensor
+		}
+	}
+
+	if !found {
+		return 0, nil, ErrPowerConsumptionUnsupported
+	}
+
+	if len(psus) == 0 && system == nil {
+		return 0, nil, ErrPowerConsumptionUnavailable
+	}
+
+	if len(psus) == 0 {
+		return system.Reading, map[string]float64{}, nil
+	}
+
+	var total float64
+
+	readings := make(map[string]float64, len(psus))
+	for id, sensor := range psus {
+		readings[id] = sensor.Reading
+		total += sensor.Reading
+	}
+
+	return total, readings, nil
+}
+
+// isPowerSensor returns true if the sensor reports a power reading in watts
+func isPowerSensor(sensor *Sensor) bool {
+	switch strings.ToLower(sensor.Unit) {
+	case "w", "watt", "watts":
+		return true
+	}
+
+	return strings.EqualFold(sensor.Type, "power")
+}
+
+// isInputPowerSensor returns true if the PSU power sensor reports the input power - PSU1_PIN, PSU1 Input Power
+func isInputPowerSensor(sensor *Sensor) bool {
+	name := strings.ToUpper(sensor.Name)
+
+	return strings.Contains(name, "PIN") || strings.Contains(name, "INPUT")
+}
+
+// powerConsumptionMetadata sets the power consumption in the device metadata when the BMC exposes power sensors
+//
+// power.consumed_watts = <total>, power.<psu>.consumed_watts = <reading>
+func powerConsumptionMetadata(device *common.Device, sensors []*Sensor) {
+	total, psus, err := powerConsumption(sensors)
+	if err != nil {
+		return
+	}
+
+	device.Metadata["power.consumed_watts"] = strconv.FormatFloat(total, 'f', -1, 64)
+
+	for id, watts := range psus {
+		device.Metadata["power."+strings.ToLower(id)+".consumed_watts"] = strconv.FormatFloat(watts, 'f', -1, 64)
+	}
+}
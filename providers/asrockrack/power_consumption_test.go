@@ -0,0 +1,118 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PowerConsumption(t *testing.T) {
+	testcases := []struct {
+		name     string
+		sensors  string
+		expected float64
+		psus     map[string]float64
+		err      error
+	}{
+		{
+			"single PSU",
+			`[{"name": "PSU1_PIN", "unit": "W", "reading": 96, "accessible": 0},
+			  {"name": "PSU1_POUT", "unit": "W", "reading": 88, "accessible": 0}]`,
+			96,
+			map[string]float64{"PSU1": 96},
+			nil,
+		},
+		{
+			"dual PSU summed",
+			`[{"name": "PSU1 POUT", "unit": "Watts", "reading": 110, "accessible": 0},
+			  {"name": "PSU1 Input Power", "unit": "Watts", "reading": 120, "accessible": 0},
+			  {"name": "PSU2 Input Power", "unit": "Watts", "reading": 115.5, "accessible": 0},
+			  {"name": "CPU_TEMP", "unit": "°C", "reading": 40, "accessible": 0}]`,
+			235.5,
+			map[string]float64{"PSU1": 120, "PSU2": 115.5},
+			nil,
+		},
+		{
+			"system power sensor",
+			`[{"name": "Total_Power", "type": "power", "unit": "W", "reading": 74, "accessible": 0}]`,
+			74,
+			map[string]float64{},
+			nil,
+		},
+		{
+			"power sensors not readable",
+			`[{"name": "PSU1_PIN", "unit": "W", "reading": 0, "accessible": 213}]`,
+			0,
+			nil,
+			ErrPowerConsumptionUnavailable,
+		},
+		{
+			"no power sensors",
+			`[{"name": "CPU_TEMP", "unit": "°C", "reading": 40, "accessible": 0}]`,
+			0,
+			nil,
+			ErrPowerConsumptionUnsupported,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/sensors", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tc.sensors))
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			watts, err := client.PowerConsumption(context.TODO())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, watts)
+
+			psus, err := client.PSUPowerConsumption(context.TODO())
+			if len(tc.psus) == 0 {
+				assert.ErrorIs(t, err, ErrPowerConsumptionUnsupported)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.psus, psus)
+		})
+	}
+}
+
+func Test_powerConsumptionMetadata(t *testing.T) {
+	sensors := []*Sensor{
+		{Name: "PSU1_PIN", Unit: "W", Reading: 96},
+		{Name: "PSU2_PIN", Unit: "W", Reading: 101},
+	}
+
+	device := common.NewDevice()
+	device.Metadata = map[string]string{}
+	powerConsumptionMetadata(&device, sensors)
+
+	assert.Equal(t, "197", device.Metadata["power.consumed_watts"])
+	assert.Equal(t, "96", device.Metadata["power.psu1.consumed_watts"])
+	assert.Equal(t, "101", device.Metadata["power.psu2.consumed_watts"])
+
+	// the metadata is not set without power sensors
+	device = common.NewDevice()
+	device.Metadata = map[string]string{}
+	powerConsumptionMetadata(&device, []*Sensor{{Name: "CPU_TEMP", Unit: "°C", Reading: 40}})
+
+	assert.NotContains(t, device.Metadata, "power.consumed_watts")
+}
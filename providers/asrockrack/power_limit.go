@@ -0,0 +1,104 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const powerLimitEndpoint = "api/settings/dcmi/power-limit"
+
+// powerLimit is the DCMI power limit configuration
+type powerLimit struct {
+	Enable          int `json:"enable"`
+	Limit           int `json:"power_limit"`
+	MinLimit        int `json:"min_power_limit"`
+	MaxLimit        int `json:"max_power_limit"`
+	CorrectionTime  int `json:"correction_time"`
+	SamplingPeriod  int `json:"sampling_period"`
+	ExceptionAction int `json:"exception_action"`
+}
+
+// GetPowerCap returns the node power cap in watts and if the power cap is enforced
+func (a *ASRockRack) GetPowerCap(ctx context.Context) (watts int, enabled bool, err error) {
+	limit, err := a.powerLimit(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return limit.Limit, limit.Enable == 1, nil
+}
+
+// SetPowerCap sets the node power cap in watts and enables or disables its enforcement,
+// the other power limit settings - correction time, sampling period and exception action are preserved.
+//
+// The power cap is validated against the minimum and maximum power cap reported by the BMC,
+// ErrPowerCapOutOfRange is returned when it is out of range. When disabling the power cap
+// a watts value of 0 leaves the configured power cap unchanged.
+func (a *ASRockRack) SetPowerCap(ctx context.Context, watts int, enabled bool) error {
+	limit, err := a.powerLimit(ctx)
+	if err != nil {
+		return err
+	}
+
+	if enabled || watts != 0 {
+		if watts < limit.MinLimit || (limit.MaxLimit > 0 && watts > limit.MaxLimit) {
+			return errors.Wrap(
+				ErrPowerCapOutOfRange,
+				fmt.Sprintf("%dW not within %dW - %dW", watts, limit.MinLimit, limit.MaxLimit),
+			)
+		}
+
+		limit.Limit = watts
+	}
+
+	limit.Enable = 0
+	if enabled {
+		limit.Enable = 1
+	}
+
+	payload, err := json.Marshal(limit)
+	if err != nil {
+		return errors.Wrap(ErrPowerCapWrite, err.Error())
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	_, statusCode, err := a.queryHTTPS(ctx, powerLimitEndpoint, "PUT", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return errors.Wrap(ErrPowerCapWrite, err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		return wrapStatusCodeError(ErrPowerCapWrite, statusCode)
+	}
+
+	return nil
+}
+
+// powerLimit returns the DCMI power limit configuration
+func (a *ASRockRack) powerLimit(ctx context.Context) (*powerLimit, error) {
+	resp, statusCode, err := a.getWithRetry(ctx, powerLimitEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(ErrPowerCapRead, err.Error())
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, wrapStatusCodeError(ErrPowerCapUnsupported, statusCode)
+	default:
+		return nil, wrapStatusCodeError(ErrPowerCapRead, statusCode)
+	}
+
+	limit := &powerLimit{}
+	if err := json.Unmarshal(resp, limit); err != nil {
+		return nil, errors.Wrap(ErrPowerCapRead, err.Error())
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,125 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPowerLimitBMC returns a BMC serving the power limit settings, PUT requests update the settings
+func mockPowerLimitBMC(limit *powerLimit) (*httptest.Server, func() powerLimit) {
+	var mu sync.Mutex
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/settings/dcmi/power-limit", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(limit)
+		case http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(limit); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	current := func() powerLimit {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return *limit
+	}
+
+	return httptest.NewTLSServer(handler), current
+}
+
+func Test_PowerCap(t *testing.T) {
+	testcases := []struct {
+		name     string
+		watts    int
+		enabled  bool
+		expected powerLimit
+		err      error
+	}{
+		{
+			"enable",
+			250,
+			true,
+			powerLimit{Enable: 1, Limit: 250, MinLimit: 100, MaxLimit: 450, CorrectionTime: 6000, SamplingPeriod: 5},
+			nil,
+		},
+		{
+			"disable keeps the power cap",
+			0,
+			false,
+			powerLimit{Enable: 0, Limit: 300, MinLimit: 100, MaxLimit: 450, CorrectionTime: 6000, SamplingPeriod: 5},
+			nil,
+		},
+		{
+			"above range",
+			500,
+			true,
+			powerLimit{Enable: 1, Limit: 300, MinLimit: 100, MaxLimit: 450, CorrectionTime: 6000, SamplingPeriod: 5},
+			ErrPowerCapOutOfRange,
+		},
+		{
+			"below range",
+			50,
+			false,
+			powerLimit{Enable: 1, Limit: 300, MinLimit: 100, MaxLimit: 450, CorrectionTime: 6000, SamplingPeriod: 5},
+			ErrPowerCapOutOfRange,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, current := mockPowerLimitBMC(
+				&powerLimit{Enable: 1, Limit: 300, MinLimit: 100, MaxLimit: 450, CorrectionTime: 6000, SamplingPeriod: 5},
+			)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			err := client.SetPowerCap(context.TODO(), tc.watts, tc.enabled)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+			} else {
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, tc.expected, current())
+
+			watts, enabled, err := client.GetPowerCap(context.TODO())
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected.Limit, watts)
+			assert.Equal(t, tc.expected.Enable == 1, enabled)
+		})
+	}
+}
+
+func Test_PowerCapUnsupported(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, _, err := client.GetPowerCap(context.TODO())
+	assert.ErrorIs(t, err, ErrPowerCapUnsupported)
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	err = client.SetPowerCap(context.TODO(), 200, true)
+	assert.ErrorIs(t, err, ErrPowerCapUnsupported)
+}
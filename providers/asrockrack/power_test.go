@@ -0,0 +1,333 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPowerBMC returns a BMC mock which powers off on a soft power off request unless ignoreACPI is set
+func mockPowerBMC(ignoreACPI bool) *httptest.Server {
+	var mu sync.Mutex
+	powerStatus := 1
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, _ = w.Write([]byte(fmt.Sprintf(`{ "power_status": %d, "led_status": 0 }`, powerStatus)))
+	})
+
+	handler.HandleFunc("/api/actions/power", func(w http.ResponseWriter, r *http.Request) {
+		p := &power{}
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch p.Command {
+		case 0:
+			powerStatus = 0
+		case 1:
+			powerStatus = 1
+		case 5:
+			if !ignoreACPI {
+				powerStatus = 0
+			}
+		}
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_PowerSet(t *testing.T) {
+	server := mockPowerBMC(false)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+	client.powerPollInterval = 10 * time.Millisecond
+
+	state, err := client.PowerStateGet(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "On", state)
+
+	ok, err := client.PowerSet(context.TODO(), "soft")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	state, err = client.PowerStateGet(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "Off", state)
+
+	ok, err = client.PowerSet(context.TODO(), "on")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, err = client.PowerSet(context.TODO(), "sleep")
+	assert.NotNil(t, err)
+}
+
+func Test_PowerSetSoftTimeout(t *testing.T) {
+	server := mockPowerBMC(true)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithSoftPowerOffTimeout(50*time.Millisecond))
+	client.powerPollInterval = 10 * time.Millisecond
+
+	ok, err := client.PowerSet(context.TODO(), "soft")
+	assert.ErrorIs(t, err, ErrSoftPowerOffTimeout)
+	assert.False(t, ok)
+
+	state, err := client.PowerStateGet(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "On", state)
+}
+
+// mockResetBMC returns a BMC mock which drops the connection on a reset request,
+// and is unreachable for the given number of queries after the reset.
+func mockResetBMC(t *testing.T, unreachable int32) *httptest.Server {
+	var queries int32
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&queries, 1) <= unreachable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`ASRockRack`))
+	})
+
+	handler.HandleFunc("/api/maintenance/reset", func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("expected a hijackable connection")
+		}
+
+		conn, _, err := hijacker.Hijack()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_ = conn.Close()
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_BmcReset(t *testing.T) {
+	server := mockResetBMC(t, 2)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	// the dropped connection is tolerated
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+	ok, err := client.BmcReset(context.TODO(), "cold")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, err = client.BmcReset(context.TODO(), "warm")
+	assert.ErrorIs(t, err, bmclibErrs.ErrNotImplemented)
+
+	// wait for the BMC to be reachable
+	client = NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithBMCResetWait(time.Second))
+	client.bmcResetPollInterval = 10 * time.Millisecond
+
+	ok, err = client.BmcReset(context.TODO(), "")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func Test_BmcResetWaitTimeout(t *testing.T) {
+	server := mockResetBMC(t, 1000)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithBMCResetWait(50*time.Millisecond))
+	client.bmcResetPollInterval = 10 * time.Millisecond
+
+	ok, err := client.BmcReset(context.TODO(), "cold")
+	assert.ErrorIs(t, err, ErrBMCResetTimeout)
+	assert.False(t, ok)
+}
+
+func Test_WaitForReady(t *testing.T) {
+	// reserve an address the BMC is unreachable on, connections are refused until the mock is started
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	// the mock drops the first connections, as the BMC web server starts, then serves the web interface
+	var queries int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&queries, 1) <= 2 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`ASRockRack`))
+	})
+
+	server := httptest.NewUnstartedServer(handler)
+	started := make(chan struct{})
+
+	defer func() {
+		<-started
+		server.Close()
+	}()
+
+	time.AfterFunc(100*time.Millisecond, func() {
+		defer close(started)
+
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		_ = server.Listener.Close()
+		server.Listener = l
+		server.StartTLS()
+	})
+
+	client := NewWithOptions(addr, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+
+	err = client.WaitForReady(ctx, 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&queries))
+
+	// the context expires before the BMC is ready
+	unreachable := mockResetBMC(t, 1000)
+	defer unreachable.Close()
+
+	serverURL, _ := url.Parse(unreachable.URL)
+	client = NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	ctx, cancel = context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+
+	err = client.WaitForReady(ctx, 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrBMCNotReady)
+}
+
+// mockPowerTransitionBMC returns a BMC mock which reaches the requested power state after the given number of
+// power state queries, the time the power off was first reported and the power on requested are recorded.
+func mockPowerTransitionBMC(transitionQueries int, offReported, onRequested *time.Time) *httptest.Server {
+	var mu sync.Mutex
+	powerStatus, target, pending := 1, 1, 0
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if powerStatus != target {
+			if pending == 0 {
+				powerStatus = target
+			} else {
+				pending--
+			}
+		}
+
+		if powerStatus == 0 && offReported.IsZero() {
+			*offReported = time.Now()
+		}
+
+		_, _ = w.Write([]byte(fmt.Sprintf(`{ "power_status": %d, "led_status": 0 }`, powerStatus)))
+	})
+
+	handler.HandleFunc("/api/actions/power", func(w http.ResponseWriter, r *http.Request) {
+		p := &power{}
+		if err := json.NewDecoder(r.Body).Decode(p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch p.Command {
+		case 0:
+			target = 0
+		case 1:
+			target = 1
+			*onRequested = time.Now()
+		}
+
+		pending = transitionQueries
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_PowerCycle(t *testing.T) {
+	var offReported, onRequested time.Time
+
+	server := mockPowerTransitionBMC(2, &offReported, &onRequested)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+	client.powerPollInterval = 10 * time.Millisecond
+
+	offDelay := 100 * time.Millisecond
+
+	err := client.PowerCycle(context.TODO(), offDelay)
+	assert.Nil(t, err)
+
+	state, err := client.PowerStateGet(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "On", state)
+
+	// the host is powered on only once the power off was confirmed and the off delay elapsed
+	assert.False(t, offReported.IsZero())
+	assert.GreaterOrEqual(t, onRequested.Sub(offReported), offDelay)
+}
+
+func Test_PowerCycleTimeout(t *testing.T) {
+	var offReported, onRequested time.Time
+
+	// the host does not power off within the power state timeout
+	server := mockPowerTransitionBMC(1000, &offReported, &onRequested)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithPowerStateTimeout(50*time.Millisecond))
+	client.powerPollInterval = 10 * time.Millisecond
+
+	err := client.PowerCycle(context.TODO(), time.Millisecond)
+	assert.ErrorIs(t, err, ErrPowerStateTimeout)
+	assert.True(t, onRequested.IsZero(), "host powered on before the power off was confirmed")
+}
@@ -0,0 +1,57 @@
+package asrockrack
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// The IPMI privilege levels reported for the session user at login
+const (
+	privilegeCallback      = 1
+	privilegeUser          = 2
+	privilegeOperator      = 3
+	privilegeAdministrator = 4
+)
+
+// privilegeNames maps the IPMI privilege level to the privilege name
+var privilegeNames = map[int]string{
+	privilegeCallback:      "callback",
+	privilegeUser:          "user",
+	privilegeOperator:      "operator",
+	privilegeAdministrator: "administrator",
+}
+
+// sessionPrivilege returns the privilege level of the session user,
+// the level is reported by the BMC at login and cached for the lifetime of the session.
+//
+// Zero is returned when no session is open or the BMC did not report the privilege level.
+func (a *ASRockRack) sessionPrivilege() int {
+	a.sessionMu.RLock()
+	defer a.sessionMu.RUnlock()
+
+	return a.loginSession.Privilege
+}
+
+// requirePrivilege returns ErrInsufficientPrivilege when the session user privilege level is below the required level,
+// the operation is left to the BMC to permit or deny when the privilege level is not known.
+func (a *ASRockRack) requirePrivilege(operation string, required int) error {
+	privilege := a.sessionPrivilege()
+	if privilege == 0 || privilege >= required {
+		return nil
+	}
+
+	return errors.Wrap(
+		ErrInsufficientPrivilege,
+		fmt.Sprintf("%s requires the %s privilege, user %q has the %s privilege", operation, privilegeName(required), a.username, privilegeName(privilege)),
+	)
+}
+
+// privilegeName returns the name of the IPMI privilege level
+func privilegeName(privilege int) string {
+	if name, exists := privilegeNames[privilege]; exists {
+		return name
+	}
+
+	return fmt.Sprintf("level %d", privilege)
+}
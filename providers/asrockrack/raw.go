@@ -0,0 +1,58 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RawGet sends an authenticated GET request to the BMC API endpoint and returns the response body and status code,
+// as an escape hatch for the endpoints the provider does not implement - "api/asrr/board-specific".
+//
+// The path is relative to the BMC base URL. The response is returned as is, without the typed parsing
+// of the provider methods, and any status code is returned without an error.
+func (a *ASRockRack) RawGet(ctx context.Context, path string) (body []byte, statusCode int, err error) {
+	endpoint, err := rawEndpoint(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return a.queryHTTPS(ctx, endpoint, "GET", nil, nil, 0)
+}
+
+// RawPost sends an authenticated POST request with the JSON payload to the BMC API endpoint
+// and returns the response body and status code, like RawGet.
+//
+// In dry run mode the request is logged and not sent, like the provider requests changing the BMC state.
+func (a *ASRockRack) RawPost(ctx context.Context, path string, payload []byte) (body []byte, statusCode int, err error) {
+	endpoint, err := rawEndpoint(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var reader io.Reader
+	if len(payload) > 0 {
+		reader = bytes.NewReader(payload)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	return a.queryHTTPS(ctx, endpoint, "POST", reader, headers, 0)
+}
+
+// rawEndpoint returns the endpoint for the path relative to the BMC base URL, absolute URLs are not accepted
+func rawEndpoint(path string) (string, error) {
+	endpoint := strings.TrimLeft(strings.TrimSpace(path), "/")
+	if endpoint == "" {
+		return "", errors.New("empty BMC API endpoint path")
+	}
+
+	if strings.Contains(endpoint, "://") {
+		return "", errors.New("BMC API endpoint path is not relative to the BMC base URL: " + path)
+	}
+
+	return endpoint, nil
+}
@@ -0,0 +1,84 @@
+package asrockrack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RawGetPost(t *testing.T) {
+	var posted []byte
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/board-specific", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{ "fan_mode": "full" }`))
+		case http.MethodPost:
+			if r.Header.Get("Content-Type") != "application/json" {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+
+			posted, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	body, statusCode, err := client.RawGet(context.TODO(), "/api/asrr/board-specific")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, `{ "fan_mode": "full" }`, string(body))
+
+	_, statusCode, err = client.RawPost(context.TODO(), "api/asrr/board-specific", []byte(`{ "fan_mode": "standard" }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.Equal(t, `{ "fan_mode": "standard" }`, string(posted))
+
+	// the status code is returned without an error
+	_, statusCode, err = client.RawGet(context.TODO(), "api/asrr/unknown")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// absolute URLs are not requested
+	_, _, err = client.RawGet(context.TODO(), "https://example.com/api/fru")
+	assert.NotNil(t, err)
+}
+
+func Test_RawPostDryRun(t *testing.T) {
+	var posted bool
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/board-specific", func(w http.ResponseWriter, r *http.Request) {
+		posted = true
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithDryRun())
+
+	_, statusCode, err := client.RawPost(context.TODO(), "api/asrr/board-specific", []byte(`{}`))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, posted)
+}
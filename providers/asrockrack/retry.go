@@ -0,0 +1,127 @@
+package asrockrack
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy configures the retries of idempotent BMC queries on transient errors -
+// network timeouts, connection resets and refusals, truncated responses and 5xx responses.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first, a value of 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled for every subsequent retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy applied unless configured with WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// delay returns the exponential backoff delay for the retry attempt with jitter,
+// the delay is randomized between half and the full backoff duration.
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff <= 0 || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+
+	if backoff <= 0 {
+		return 0
+	}
+
+	half := backoff / 2
+
+	// nolint:gosec // jitter does not require a secure random number
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// getWithRetry performs a GET request on the endpoint, retrying on network and transport errors and 5xx responses
+// as configured by the retry policy, unless the retry delay exceeds the context deadline.
+func (a *ASRockRack) getWithRetry(ctx context.Context, endpoint string) ([]byte, int, error) {
+	var (
+		resp       []byte
+		statusCode int
+		err        error
+	)
+
+	for attempt := 1; ; attempt++ {
+		resp, statusCode, err = a.queryHTTPS(ctx, endpoint, "GET", nil, nil, 0)
+		if !retryable(ctx, statusCode, err) || attempt >= a.retryPolicy.MaxAttempts {
+			return resp, statusCode, err
+		}
+
+		delay := a.retryPolicy.delay(attempt)
+
+		// the query is not retried when the context deadline, set by a Budget, would expire before the retry
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return resp, statusCode, err
+		}
+
+		a.log.V(2).Info("retrying BMC query", "endpoint", endpoint, "attempt", attempt, "statusCode", statusCode, "delay", delay.String())
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, statusCode, err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable returns true when the query failed with a transient error - a network or transport error, or a 5xx response
+func retryable(ctx context.Context, statusCode int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return transientError(err)
+	}
+
+	return statusCode >= http.StatusInternalServerError
+}
+
+// transientError returns true for the transient network errors - timeouts, connection resets and refusals
+// and truncated responses.
+//
+// TLS and certificate verification failures, configuration errors, session refresh failures and response decoding
+// errors are not transient, the query is not retried.
+func transientError(err error) bool {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		certInvalid      x509.CertificateInvalidError
+		hostname         x509.HostnameError
+		recordHeader     tls.RecordHeaderError
+	)
+
+	if errors.As(err, &unknownAuthority) || errors.As(err, &certInvalid) || errors.As(err, &hostname) || errors.As(err, &recordHeader) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// the request timeout error does not wrap the network timeout error
+	return errors.Is(err, ErrRequestTimeout) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
@@ -0,0 +1,206 @@
+package asrockrack
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockFlakyBMC returns a BMC mock which responds to the firmware info query with the given status code
+// for the number of failures before responding successfully.
+func mockFlakyBMC(failures int32, statusCode int, attempts *int32) *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/fw-info", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) <= failures {
+			w.WriteHeader(statusCode)
+			return
+		}
+
+		_, _ = w.Write(fwinfoResponse)
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_getWithRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	testCases := []struct {
+		name             string
+		failures         int32
+		statusCode       int
+		expectedAttempts int32
+		err              bool
+	}{
+		{"no failures", 0, http.StatusInternalServerError, 1, false},
+		{"recovers after transient failures", 2, http.StatusInternalServerError, 3, false},
+		{"attempts exhausted", 5, http.StatusBadGateway, 3, true},
+		{"client errors are not retried", 5, http.StatusNotFound, 1, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+
+			server := mockFlakyBMC(tc.failures, tc.statusCode, &attempts)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithRetryPolicy(policy))
+
+			fwInfo, err := client.firmwareInfo(context.TODO())
+			if tc.err {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, "L2.07B", fwInfo.BIOSVersion)
+			}
+
+			assert.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func Test_getWithRetryNonTransient(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+	testCases := []struct {
+		name             string
+		handler          http.HandlerFunc
+		session          bool
+		expectedAttempts int32
+		err              string
+	}{
+		{
+			"response decode error",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Encoding", "gzip")
+				_, _ = w.Write([]byte("this is not a gzip stream"))
+			},
+			false,
+			1,
+			"gzip: invalid header",
+		},
+		{
+			// the session expired and the login response cannot be decoded
+			"session refresh failure",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+			},
+			true,
+			1,
+			"error refreshing session",
+		},
+		{
+			"connection reset",
+			func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					return
+				}
+
+				// reset the connection, closing the TCP connection without lingering sends a RST
+				tcpConn := conn.(*tls.Conn).NetConn().(*net.TCPConn)
+				_ = tcpConn.SetLinger(0)
+				tcpConn.Close()
+			},
+			false,
+			3,
+			"connection reset by peer",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var attempts int32
+
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/asrr/fw-info", func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				tc.handler(w, r)
+			})
+			handler.HandleFunc("/api/session", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("<html>"))
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithRetryPolicy(policy))
+
+			if tc.session {
+				client.loginSession = &loginSession{CSRFToken: "expired"}
+			}
+
+			_, err := client.firmwareInfo(context.TODO())
+			assert.ErrorContains(t, err, tc.err)
+			assert.Equal(t, tc.expectedAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+func Test_getWithRetryTLSError(t *testing.T) {
+	var connections int32
+
+	server := httptest.NewUnstartedServer(http.NewServeMux())
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+
+	server.StartTLS()
+	defer server.Close()
+
+	// the self-signed server certificate is not trusted
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}),
+	)
+
+	_, err := client.firmwareInfo(context.TODO())
+	assert.ErrorContains(t, err, "x509")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections))
+}
+
+func Test_getWithRetryContextCanceled(t *testing.T) {
+	var attempts int32
+
+	server := mockFlakyBMC(5, http.StatusInternalServerError, &attempts)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(),
+		WithInsecureSkipVerify(),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Second, MaxDelay: time.Second}),
+	)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.firmwareInfo(ctx)
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func Test_RetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for attempt, limit := range []time.Duration{100, 200, 300, 300} {
+		limit *= time.Millisecond
+
+		delay := policy.delay(attempt + 1)
+		assert.GreaterOrEqual(t, delay, limit/2)
+		assert.LessOrEqual(t, delay, limit)
+	}
+}
@@ -0,0 +1,91 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+
+	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
+	"github.com/pkg/errors"
+)
+
+var (
+	// the BMC responds with a placeholder message when the host has no video output
+	noSignalMarkers = [][]byte{[]byte("no signal"), []byte("no video")}
+)
+
+// Screenshot captures the host screen through the BMC, returning the image and its file type - png or jpg.
+//
+// ErrScreenshotNoSignal is returned when the host has no video output to capture.
+func (a *ASRockRack) Screenshot(ctx context.Context) (image []byte, fileType string, err error) {
+	// request the BMC to capture the screen
+	if err := a.captureScreen(ctx); err != nil {
+		return nil, "", err
+	}
+
+	image, err = a.fetchScreenCapture(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch http.DetectContentType(image) {
+	case "image/png":
+		fileType = "png"
+	case "image/jpeg":
+		fileType = "jpg"
+	default:
+		if isNoSignal(image) {
+			return nil, "", ErrScreenshotNoSignal
+		}
+
+		return nil, "", errors.Wrap(bmclibErrs.ErrScreenshot, "unexpected content type: "+http.DetectContentType(image))
+	}
+
+	return image, fileType, nil
+}
+
+func (a *ASRockRack) captureScreen(ctx context.Context) error {
+	body, statusCode, err := a.queryHTTPS(ctx, "api/kvm/screenshot", "POST", nil, nil, 0)
+	if err != nil {
+		return errors.Wrap(bmclibErrs.ErrScreenshot, err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		if isNoSignal(body) {
+			return ErrScreenshotNoSignal
+		}
+
+		return errors.Wrap(bmclibErrs.ErrScreenshot, strconv.Itoa(statusCode))
+	}
+
+	return nil
+}
+
+func (a *ASRockRack) fetchScreenCapture(ctx context.Context) ([]byte, error) {
+	body, statusCode, err := a.queryHTTPS(ctx, "api/kvm/screenshot", "GET", nil, nil, 0)
+	if err != nil {
+		return nil, errors.Wrap(bmclibErrs.ErrScreenshot, err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, errors.Wrap(bmclibErrs.ErrScreenshot, strconv.Itoa(statusCode))
+	}
+
+	if len(body) == 0 {
+		return nil, ErrScreenshotNoSignal
+	}
+
+	return body, nil
+}
+
+func isNoSignal(body []byte) bool {
+	body = bytes.ToLower(body)
+	for _, marker := range noSignalMarkers {
+		if bytes.Contains(body, marker) {
+			return true
+		}
+	}
+
+	return len(bytes.TrimSpace(body)) == 0
+}
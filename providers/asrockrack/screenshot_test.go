@@ -0,0 +1,101 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// a 1x1 pixel PNG image
+var screenshotPNG, _ = base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+
+func Test_Screenshot(t *testing.T) {
+	testCases := []struct {
+		name         string
+		captureCode  int
+		captureBody  []byte
+		image        []byte
+		expectedType string
+		err          error
+	}{
+		{
+			"png",
+			http.StatusOK,
+			nil,
+			screenshotPNG,
+			"png",
+			nil,
+		},
+		{
+			"jpeg",
+			http.StatusOK,
+			nil,
+			[]byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"),
+			"jpg",
+			nil,
+		},
+		{
+			"no signal on capture",
+			http.StatusServiceUnavailable,
+			[]byte(`{ "code": 1, "error": "No Signal" }`),
+			nil,
+			"",
+			ErrScreenshotNoSignal,
+		},
+		{
+			"empty image",
+			http.StatusOK,
+			nil,
+			[]byte{},
+			"",
+			ErrScreenshotNoSignal,
+		},
+		{
+			"unexpected content",
+			http.StatusOK,
+			nil,
+			[]byte(`<html>404</html>`),
+			"",
+			bmclibErrs.ErrScreenshot,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/kvm/screenshot", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "POST" {
+					w.WriteHeader(tc.captureCode)
+					_, _ = w.Write(tc.captureBody)
+					return
+				}
+
+				_, _ = w.Write(tc.image)
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			image, fileType, err := client.Screenshot(context.TODO())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, image)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedType, fileType)
+			assert.Equal(t, tc.image, image)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package asrockrack
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// secureBootAttributes are the BIOS attribute names of the UEFI Secure Boot control, by BIOS revision
+var secureBootAttributes = []string{"Secure Boot", "Secure Boot Control", "SecureBoot"}
+
+// GetSecureBootState returns true when UEFI Secure Boot is enabled, as read from the BIOS settings.
+//
+// ErrSecureBootUnsupported is returned when the BMC firmware does not expose the BIOS settings
+// or the BIOS has no Secure Boot control.
+func (a *ASRockRack) GetSecureBootState(ctx context.Context) (enabled bool, err error) {
+	attribute, err := a.secureBootAttribute(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(attribute.CurrentValue, "Enabled"), nil
+}
+
+// SetSecureBootState enables or disables UEFI Secure Boot, the setting is applied on the next boot.
+//
+// rebootRequired is true when the setting is pending and the host has to be rebooted to apply it,
+// ErrSecureBootUnsupported is returned when the BIOS has no Secure Boot control.
+func (a *ASRockRack) SetSecureBootState(ctx context.Context, enabled bool) (rebootRequired bool, err error) {
+	attribute, err := a.secureBootAttribute(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	value := "Disabled"
+	if enabled {
+		value = "Enabled"
+	}
+
+	// the allowed values are matched case insensitively
+	for _, allowed := range attribute.AllowedValues {
+		if strings.EqualFold(allowed, value) {
+			value = allowed
+		}
+	}
+
+	return a.applyBiosSettings(ctx, []*biosAttribute{attribute}, map[string]string{attribute.Name: value})
+}
+
+// secureBootAttribute returns the Secure Boot BIOS attribute
+func (a *ASRockRack) secureBootAttribute(ctx context.Context) (*biosAttribute, error) {
+	attributes, err := a.biosAttributes(ctx)
+	if err != nil {
+		if errors.Is(err, ErrBIOSConfigUnsupported) {
+			return nil, errors.Wrap(ErrSecureBootUnsupported, err.Error())
+		}
+
+		return nil, err
+	}
+
+	attribute := findSecureBootAttribute(attributes)
+	if attribute == nil {
+		return nil, ErrSecureBootUnsupported
+	}
+
+	return attribute, nil
+}
+
+// findSecureBootAttribute returns the Secure Boot BIOS attribute, nil when the BIOS has no Secure Boot control
+func findSecureBootAttribute(attributes []*biosAttribute) *biosAttribute {
+	for _, name := range secureBootAttributes {
+		for _, attribute := range attributes {
+			if strings.EqualFold(attribute.Name, name) {
+				return attribute
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSecureBootBMC returns a BMC mock serving a BIOS attribute registry with the Secure Boot control
+// in the given state, the staged settings are recorded in updates.
+func mockSecureBootBMC(state string, updates *[]*biosAttributeValue) *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/bios/attributes", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			update := &biosAttributesUpdate{}
+			if err := json.NewDecoder(r.Body).Decode(update); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			*updates = append(*updates, update.Attributes...)
+			_, _ = w.Write([]byte(`{"reboot_required": true, "errors": []}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"attributes": [
+			{ "name": "Boot Mode", "current_value": "UEFI", "type": "enumeration", "allowed_values": ["UEFI", "Legacy"] },
+			{ "name": "Secure Boot", "current_value": %q, "type": "enumeration", "allowed_values": ["Enabled", "Disabled"] }
+		]}`, state)))
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_GetSecureBootState(t *testing.T) {
+	testCases := []struct {
+		state    string
+		expected bool
+	}{
+		{"Enabled", true},
+		{"Disabled", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.state, func(t *testing.T) {
+			server := mockSecureBootBMC(tc.state, nil)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			enabled, err := client.GetSecureBootState(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.expected, enabled)
+		})
+	}
+}
+
+func Test_GetSecureBootStateUnsupported(t *testing.T) {
+	// the BIOS settings are not exposed
+	server := mockBIOSBMC(t, true, nil)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err := client.GetSecureBootState(context.TODO())
+	assert.True(t, errors.Is(err, ErrSecureBootUnsupported))
+
+	// the BIOS has no Secure Boot control
+	server = mockBIOSBMC(t, false, nil)
+	defer server.Close()
+
+	serverURL, _ = url.Parse(server.URL)
+	client = NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err = client.GetSecureBootState(context.TODO())
+	assert.True(t, errors.Is(err, ErrSecureBootUnsupported))
+
+	_, err = client.SetSecureBootState(context.TODO(), true)
+	assert.True(t, errors.Is(err, ErrSecureBootUnsupported))
+}
+
+func Test_SetSecureBootState(t *testing.T) {
+	testCases := []struct {
+		name     string
+		state    string
+		enabled  bool
+		expected []*biosAttributeValue
+	}{
+		{"enable", "Disabled", true, []*biosAttributeValue{{Name: "Secure Boot", Value: "Enabled"}}},
+		{"disable", "Enabled", false, []*biosAttributeValue{{Name: "Secure Boot", Value: "Disabled"}}},
+		{"unchanged", "Enabled", true, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var updates []*biosAttributeValue
+
+			server := mockSecureBootBMC(tc.state, &updates)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			rebootRequired, err := client.SetSecureBootState(context.TODO(), tc.enabled)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.expected, updates)
+			assert.Equal(t, tc.expected != nil, rebootRequired)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// selPageSize is the number of SEL entries requested per query
+	selPageSize = 100
+)
+
+const (
+	// SEL entry severities
+	selSeverityInfo     = "Info"
+	selSeverityWarning  = "Warning"
+	selSeverityCritical = "Critical"
+)
+
+// selEntry is part of the payload returned by the event log endpoint
+type selEntry struct {
+	ID               int    `json:"id"`
+	RecordType       string `json:"record_type"`
+	Timestamp        int64  `json:"timestamp"`
+	SensorName       string `json:"sensor_name"`
+	SensorType       string `json:"sensor_type"`
+	EventDirection   string `json:"event_direction"`
+	EventDescription string `json:"event_description"`
+}
+
+// GetSystemEventLog returns the System Event Log entries ordered by the entry ID,
+// each entry is a list of the id, timestamp (RFC3339), sensor, description and severity.
+func (a *ASRockRack) GetSystemEventLog(ctx context.Context) (entries [][]string, err error) {
+	records, _, err := a.systemEventLog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries = [][]string{}
+	for _, record := range records {
+		entries = append(entries, []string{
+			strconv.Itoa(record.ID),
+			time.Unix(record.Timestamp, 0).UTC().Format(time.RFC3339),
+			record.SensorName,
+			record.description(),
+			record.severity(),
+		})
+	}
+
+	return entries, nil
+}
+
+// GetSystemEventLogRaw returns the System Event Log entries as a JSON array of the entries returned by the BMC.
+func (a *ASRockRack) GetSystemEventLogRaw(ctx context.Context) (eventlog string, err error) {
+	_, raw, err := a.systemEventLog(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// ClearSystemEventLog clears the System Event Log and verifies the log is empty afterwards.
+//
+// ErrInsufficientPrivilege is returned without clearing the log when the session user is not an administrator,
+// ErrSELClearNotPermitted when the BMC rejects the request as the user privilege does not permit clearing the log.
+func (a *ASRockRack) ClearSystemEventLog(ctx context.Context) (err error) {
+	if err := a.requirePrivilege("system event log clear", privilegeAdministrator); err != nil {
+		return err
+	}
+
+	_, statusCode, err := a.queryHTTPS(ctx, "api/logs/event", "DELETE", nil, nil, 0)
+	if err != nil {
+		return wrapCause(ErrSELClear, err)
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return wrapStatusCodeError(ErrSELClearNotPermitted, statusCode)
+	default:
+		return wrapStatusCodeError(ErrSELClear, statusCode)
+	}
+
+	records, _, err := a.systemEventLog(ctx)
+	if err != nil {
+		return wrapCause(ErrSELClear, err)
+	}
+
+	// the BMC records the log being cleared as the first entry of the new log
+	for _, record := range records {
+		if !record.logCleared() {
+			return errors.Wrap(ErrSELClear, fmt.Sprintf("%d entries remain after clearing the log", len(records)))
+		}
+	}
+
+	return nil
+}
+
+// systemEventLog queries the event log endpoint a page at a time, returning the entries ordered by ID
+// along with the raw entries in the same order.
+//
+// The SEL is a rolling log, entries are deduplicated by ID in case the log rolled over between queries.
+func (a *ASRockRack) systemEventLog(ctx context.Context) ([]*selEntry, []json.RawMessage, error) {
+	byID := map[int]json.RawMessage{}
+	records := []*selEntry{}
+
+	for start := 0; ; start += selPageSize {
+		endpoint := fmt.Sprintf("api/logs/event?start=%d&count=%d", start, selPageSize)
+
+		resp, statusCode, err := a.getWithRetry(ctx, endpoint)
+		if err != nil {
+			return nil, nil, wrapCause(ErrSELRead, err)
+		}
+
+		if statusCode != http.StatusOK {
+			return nil, nil, wrapStatusCodeError(ErrSELRead, statusCode)
+		}
+
+		// an empty log is returned as an empty body by some firmware revisions
+		if strings.TrimSpace(string(resp)) == "" {
+			break
+		}
+
+		page := []json.RawMessage{}
+		if err := json.Unmarshal(resp, &page); err != nil {
+			return nil, nil, wrapCause(ErrSELRead, err)
+		}
+
+		for _, raw := range page {
+			record := &selEntry{}
+			if err := json.Unmarshal(raw, record); err != nil {
+				return nil, nil, wrapCause(ErrSELRead, err)
+			}
+
+			if _, exists := byID[record.ID]; exists {
+				continue
+			}
+
+			byID[record.ID] = raw
+			records = append(records, record)
+		}
+
+		if len(page) < selPageSize {
+			break
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+
+	raw := make([]json.RawMessage, 0, len(records))
+	for _, record := range records {
+		raw = append(raw, byID[record.ID])
+	}
+
+	return records, raw, nil
+}
+
+// logCleared returns true for the entry recorded by the BMC when the log is cleared
+func (e *selEntry) logCleared() bool {
+	description := strings.ToLower(e.EventDescription)
+
+	return strings.Contains(description, "log area reset") || strings.Contains(description, "cleared")
+}
+
+// description returns the event description including the event direction - "Upper Critical - going high (Asserted)"
+func (e *selEntry) description() string {
+	if e.EventDirection == "" {
+		return e.EventDescription
+	}
+
+	return e.EventDescription + " (" + e.EventDirection + ")"
+}
+
+// severity returns the entry severity based on the event description, deasserted events are informational
+func (e *selEntry) severity() string {
+	if strings.Contains(strings.ToLower(e.EventDirection), "deassert") {
+		return selSeverityInfo
+	}
+
+	description := strings.ToLower(e.EventDescription)
+
+	switch {
+	case strings.Contains(description, "non-critical"):
+		return selSeverityWarning
+	case strings.Contains(description, "critical"),
+		strings.Contains(description, "non-recoverable"),
+		strings.Contains(description, "failure"),
+		strings.Contains(description, "uncorrectable"):
+		return selSeverityCritical
+	case strings.Contains(description, "correctable"),
+		strings.Contains(description, "predictive"):
+		return selSeverityWarning
+	default:
+		return selSeverityInfo
+	}
+}
@@ -0,0 +1,213 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSELBMC returns a BMC mock serving the given SEL entries a page at a time,
+// when overlap is set each page begins with the last entry of the previous page - as seen when the log rolls over.
+func mockSELBMC(t *testing.T, entries []json.RawMessage, overlap bool) *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/logs/event", func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		count, _ := strconv.Atoi(r.URL.Query().Get("count"))
+
+		if overlap && start > 0 {
+			start--
+		}
+
+		page := []json.RawMessage{}
+		for i := start; i < start+count && i < len(entries); i++ {
+			page = append(page, entries[i])
+		}
+
+		b, err := json.Marshal(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = w.Write(b)
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_GetSystemEventLog(t *testing.T) {
+	b, err := os.ReadFile("./fixtures/E3C246D4I-NL/sel.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []json.RawMessage{}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	pageSize := selPageSize
+	selPageSize = 10
+	defer func() { selPageSize = pageSize }()
+
+	for _, overlap := range []bool{false, true} {
+		server := mockSELBMC(t, entries, overlap)
+		defer server.Close()
+
+		serverURL, _ := url.Parse(server.URL)
+		client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+		sel, err := client.GetSystemEventLog(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 36, len(sel))
+
+		// entries are ordered by ID, the fixture log has rolled over
+		for i, entry := range sel {
+			assert.Equal(t, strconv.Itoa(i+1), entry[0])
+		}
+
+		assert.Equal(t, []string{"1", "2023-01-01T01:00:00Z", "CPU_TEMP", "Upper Non-critical - going high (Asserted)", "Warning"}, sel[0])
+		assert.Equal(t, "Info", sel[1][4])
+		assert.Equal(t, "Critical", sel[2][4])
+		assert.Equal(t, "Critical", sel[3][4])
+		assert.Equal(t, "Warning", sel[5][4])
+		assert.Equal(t, "Critical", sel[6][4])
+		assert.Equal(t, "Info", sel[8][4])
+		assert.Equal(t, "Info", sel[9][4])
+
+		raw, err := client.GetSystemEventLogRaw(context.TODO())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rawEntries := []*selEntry{}
+		if err := json.Unmarshal([]byte(raw), &rawEntries); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 36, len(rawEntries))
+		assert.Equal(t, 1, rawEntries[0].ID)
+		assert.Equal(t, 36, rawEntries[35].ID)
+	}
+}
+
+func Test_GetSystemEventLogEmpty(t *testing.T) {
+	for _, body := range []string{`[]`, ``} {
+		handler := http.NewServeMux()
+		handler.HandleFunc("/api/logs/event", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		})
+
+		server := httptest.NewTLSServer(handler)
+		defer server.Close()
+
+		serverURL, _ := url.Parse(server.URL)
+		client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+		sel, err := client.GetSystemEventLog(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, [][]string{}, sel)
+
+		raw, err := client.GetSystemEventLogRaw(context.TODO())
+		assert.Nil(t, err)
+		assert.Equal(t, "[]", raw)
+	}
+}
+
+func Test_GetSystemEventLogMalformed(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/logs/event", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[ { "id": 1, `))
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err := client.GetSystemEventLog(context.TODO())
+	assert.ErrorIs(t, err, ErrSELRead)
+
+	// the decode error remains in the error chain
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+}
+
+func Test_ClearSystemEventLog(t *testing.T) {
+	testCases := []struct {
+		name       string
+		statusCode int
+		remaining  string
+		err        error
+	}{
+		{
+			"cleared",
+			http.StatusOK,
+			`[ { "id": 1, "timestamp": 1672531200, "sensor_name": "Event_Log", "sensor_type": "event_logging_disabled", "event_direction": "Asserted", "event_description": "Log Area Reset/Cleared" } ]`,
+			nil,
+		},
+		{
+			"cleared without a log entry",
+			http.StatusOK,
+			`[]`,
+			nil,
+		},
+		{
+			"entries remain",
+			http.StatusOK,
+			`[ { "id": 1, "timestamp": 1672531200, "sensor_name": "CPU_TEMP", "event_direction": "Asserted", "event_description": "Upper Critical - going high" } ]`,
+			ErrSELClear,
+		},
+		{
+			"permission denied",
+			http.StatusForbidden,
+			``,
+			ErrSELClearNotPermitted,
+		},
+		{
+			"bmc error",
+			http.StatusInternalServerError,
+			``,
+			ErrSELClear,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/logs/event", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodDelete {
+					w.WriteHeader(tc.statusCode)
+					return
+				}
+
+				_, _ = w.Write([]byte(tc.remaining))
+			})
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+			err := client.ClearSystemEventLog(context.TODO())
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SensorState is the threshold state bitmask reported by the BMC for a sensor,
+// it is serialized as the comma separated state names - "upper_non_critical,upper_critical".
+type SensorState int
+
+// sensorStateNames lists the sensor state bits and their names
+var sensorStateNames = []struct {
+	bit  SensorState
+	name string
+}{
+	{sensorStateNormal, "normal"},
+	{sensorStateLowerNonCritical, "lower_non_critical"},
+	{sensorStateLowerCritical, "lower_critical"},
+	{sensorStateLowerNonRecoverable, "lower_non_recoverable"},
+	{sensorStateUpperNonCritical, "upper_non_critical"},
+	{sensorStateUpperCritical, "upper_critical"},
+	{sensorStateUpperNonRecoverable, "upper_non_recoverable"},
+}
+
+// String returns the comma separated names of the states set in the bitmask,
+// "none" when no state is set and bits without a name are formatted in hex - 0x80.
+func (s SensorState) String() string {
+	if s == 0 {
+		return "none"
+	}
+
+	names := []string{}
+	remaining := s
+
+	for _, state := range sensorStateNames {
+		if s&state.bit != 0 {
+			names = append(names, state.name)
+			remaining &^= state.bit
+		}
+	}
+
+	if remaining != 0 {
+		names = append(names, fmt.Sprintf("%#x", int(remaining)))
+	}
+
+	return strings.Join(names, ",")
+}
+
+// MarshalJSON encodes the sensor state as its names
+func (s SensorState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes the sensor state from the bitmask reported by the BMC or the state names
+func (s *SensorState) UnmarshalJSON(data []byte) error {
+	var bitmask int
+	if err := json.Unmarshal(data, &bitmask); err == nil {
+		*s = SensorState(bitmask)
+		return nil
+	}
+
+	var names string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return errors.Wrap(err, "sensor state")
+	}
+
+	state, err := parseSensorState(names)
+	if err != nil {
+		return err
+	}
+
+	*s = state
+
+	return nil
+}
+
+// parseSensorState returns the sensor state for the comma separated state names
+func parseSensorState(names string) (SensorState, error) {
+	var state SensorState
+
+	if names == "none" || names == "" {
+		return state, nil
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+
+		if bit, exists := sensorStateBit(name); exists {
+			state |= bit
+			continue
+		}
+
+		bits, err := strconv.ParseInt(name, 0, 64)
+		if err != nil {
+			return 0, errors.New("unknown sensor state: " + name)
+		}
+
+		state |= SensorState(bits)
+	}
+
+	return state, nil
+}
+
+// sensorStateBit returns the sensor state bit for the state name
+func sensorStateBit(name string) (SensorState, bool) {
+	for _, state := range sensorStateNames {
+		if state.name == name {
+			return state.bit, true
+		}
+	}
+
+	return 0, false
+}
+
+// Sensors returns the BMC sensor readings, states and thresholds
+func (a *ASRockRack) Sensors(ctx context.Context) (_ []*Sensor, err error) {
+	defer func() { err = a.endpointError("sensors", "api/sensors", err) }()
+
+	resp, statusCode, err := a.getWithRetry(ctx, "api/sensors")
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCodeError(statusCode)
+	}
+
+	sensors := []*Sensor{}
+	err = unmarshalTolerant(resp, &sensors)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sensor := range sensors {
+		sensor.healthIgnored = a.sensorHealthIgnored(sensor.Name)
+		sensor.unknownStateIsInfo = a.unknownSensorsInfo
+		sensor.intrusionIsWarning = a.intrusionWarning
+	}
+
+	return sensors, nil
+}
+
+// sensorHealthIgnored returns true if the sensor is excluded from the health rollup by the WithSensorHealthIgnore option
+func (a *ASRockRack) sensorHealthIgnored(name string) bool {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	for _, ignored := range a.sensorHealthIgnore {
+		ignored = strings.ToUpper(strings.TrimSpace(ignored))
+
+		if strings.HasSuffix(ignored, "*") {
+			if strings.HasPrefix(name, strings.TrimSuffix(ignored, "*")) {
+				return true
+			}
+
+			continue
+		}
+
+		if name == ignored {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IPMI physical security sensor type number and the general chassis intrusion state offset
+const (
+	sensorTypePhysicalSecurity   = 0x05
+	sensorOffsetChassisIntrusion = 0
+)
+
+// physicalSecurity returns true if the sensor is a physical security (chassis intrusion) sensor
+func (s *Sensor) physicalSecurity() bool {
+	return s.TypeNumber == sensorTypePhysicalSecurity || s.Type == "physical_security"
+}
+
+// ChassisIntrusion returns true if the sensor is a chassis intrusion sensor with the general chassis intrusion asserted,
+// the BMC keeps the intrusion asserted until it is cleared, so the chassis has been opened since it was last cleared.
+//
+// The discrete sensor reading is the asserted state offsets bitmask, with the 0x8000 bit set for discrete readings.
+func (s *Sensor) ChassisIntrusion() bool {
+	if !s.physicalSecurity() || s.Accessible != 0 {
+		return false
+	}
+
+	return int(s.Reading)&(1<<sensorOffsetChassisIntrusion) != 0
+}
+
+// ReadableThresholds returns the sensor thresholds readable on the BMC, keyed by the threshold name -
+// lower_non_recoverable, lower_critical, lower_non_critical, upper_non_critical, upper_critical, upper_non_recoverable.
+func (s *Sensor) ReadableThresholds() map[string]float64 {
+	thresholds := map[string]float64{}
+
+	for _, t := range []struct {
+		name  string
+		bit   int
+		value float64
+	}{
+		{"lower_non_recoverable", thresholdLowerNonRecoverable, s.LowerNonRecoverableThreshold},
+		{"lower_critical", thresholdLowerCritical, s.LowerCriticalThreshold},
+		{"lower_non_critical", thresholdLowerNonCritical, s.LowerNonCriticalThreshold},
+		{"upper_non_critical", thresholdUpperNonCritical, s.HigherNonCriticalThreshold},
+		{"upper_critical", thresholdUpperCritical, s.HigherCriticalThreshold},
+		{"upper_non_recoverable", thresholdUpperNonRecoverable, s.HigherNonRecoverableThreshold},
+	} {
+		if s.thresholdReadable(t.bit) {
+			thresholds[t.name] = t.value
+		}
+	}
+
+	return thresholds
+}
@@ -0,0 +1,228 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReadableThresholds(t *testing.T) {
+	sensor := &Sensor{
+		Name:                          "CPU_TEMP",
+		SettableReadableThreshMask:    0x3838, // upper thresholds readable
+		LowerCriticalThreshold:        5,
+		HigherNonCriticalThreshold:    85,
+		HigherCriticalThreshold:       95,
+		HigherNonRecoverableThreshold: 100,
+	}
+
+	expected := map[string]float64{
+		"upper_non_critical":    85,
+		"upper_critical":        95,
+		"upper_non_recoverable": 100,
+	}
+
+	assert.Equal(t, expected, sensor.ReadableThresholds())
+
+	// no readable thresholds
+	sensor.SettableReadableThreshMask = 0
+	assert.Equal(t, map[string]float64{}, sensor.ReadableThresholds())
+}
+
+func Test_SensorState(t *testing.T) {
+	testcases := []struct {
+		state    SensorState
+		expected string
+	}{
+		{0, "none"},
+		{sensorStateNormal, "normal"},
+		{sensorStateUpperNonCritical | sensorStateUpperCritical, "upper_non_critical,upper_critical"},
+		{sensorStateLowerCritical | 0x80, "lower_critical,0x80"},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, tc.state.String())
+
+		parsed, err := parseSensorState(tc.expected)
+		assert.Nil(t, err)
+		assert.Equal(t, tc.state, parsed)
+	}
+
+	_, err := parseSensorState("upper_bogus")
+	assert.NotNil(t, err)
+}
+
+func Test_SensorJSONRoundTrip(t *testing.T) {
+	fixture, err := os.ReadFile("./fixtures/E3C246D4I-NL/sensors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the sensor states are decoded from the bitmask reported by the BMC
+	sensors := []*Sensor{}
+	if err := json.Unmarshal(fixture, &sensors); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotEmpty(t, sensors)
+
+	serialized, err := json.Marshal(sensors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the serialized sensor states are self describing
+	assert.Contains(t, string(serialized), `"sensor_state":"normal"`)
+
+	roundTripped := []*Sensor{}
+	if err := json.Unmarshal(serialized, &roundTripped); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, sensors, roundTripped)
+}
+
+func Test_SensorHealthOptions(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/sensors", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"name": "CPU_TEMP", "type": "temperature", "sensor_state": 1},
+			{"name": "PSU2_Status", "type": "power_supply", "sensor_state": 32},
+			{"name": "PSU2 PIN", "type": "power_supply", "sensor_state": 4},
+			{"name": "BMC_Watchdog", "type": "watchdog_2", "sensor_state": 128}
+		]`))
+	})
+	handler.HandleFunc("/api/chassis-status", chassisStatusInfo)
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	testcases := []struct {
+		name    string
+		options []ASRockOption
+		health  string
+		failing string
+	}{
+		{
+			"defaults",
+			nil,
+			"CRITICAL",
+			"PSU2_Status,PSU2 PIN,BMC_Watchdog",
+		},
+		{
+			"ignored sensors",
+			[]ASRockOption{WithSensorHealthIgnore("psu2_*", "PSU2 PIN")},
+			"CRITICAL",
+			"BMC_Watchdog",
+		},
+		{
+			"ignored sensors and unknown states informational",
+			[]ASRockOption{WithSensorHealthIgnore("PSU2_*", "psu2 pin"), WithUnknownSensorsInformational()},
+			"OK",
+			"",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := append([]ASRockOption{WithInsecureSkipVerify()}, tc.options...)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), options...)
+
+			device := common.NewDevice()
+			device.Status = &common.Status{}
+			device.Metadata = map[string]string{}
+
+			err := client.systemHealth(context.TODO(), &device)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.health, device.Status.Health)
+			assert.Equal(t, tc.failing, device.Metadata["health.failing_sensors"])
+		})
+	}
+}
+
+func Test_ChassisIntrusion(t *testing.T) {
+	sensors := []byte(`[
+		{"name": "CPU_TEMP", "type": "temperature", "type_number": 1, "sensor_state": 1, "accessible": 0},
+		{"name": "Chassis_Intru", "type": "physical_security", "type_number": 5, "reading": 32769, "sensor_state": 0, "discrete_state": 111, "accessible": 0}
+	]`)
+
+	testcases := []struct {
+		name      string
+		sensors   []byte
+		options   []ASRockOption
+		intrusion string
+		health    string
+		failing   string
+	}{
+		{
+			"intrusion asserted",
+			sensors,
+			nil,
+			"true",
+			"OK",
+			"",
+		},
+		{
+			"intrusion asserted with warning",
+			sensors,
+			[]ASRockOption{WithChassisIntrusionWarning()},
+			"true",
+			"WARNING",
+			"Chassis_Intru",
+		},
+		{
+			"intrusion not asserted",
+			bytes.Replace(sensors, []byte(`"reading": 32769`), []byte(`"reading": 32768`), 1),
+			[]ASRockOption{WithChassisIntrusionWarning()},
+			"false",
+			"OK",
+			"",
+		},
+		{
+			"no intrusion sensor",
+			[]byte(`[{"name": "CPU_TEMP", "type": "temperature", "type_number": 1, "sensor_state": 1, "accessible": 0}]`),
+			nil,
+			"",
+			"OK",
+			"",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := http.NewServeMux()
+			handler.HandleFunc("/api/sensors", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(tc.sensors)
+			})
+			handler.HandleFunc("/api/chassis-status", chassisStatusInfo)
+
+			server := httptest.NewTLSServer(handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			options := append([]ASRockOption{WithInsecureSkipVerify()}, tc.options...)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), options...)
+
+			device := common.NewDevice()
+			device.Status = &common.Status{}
+			device.Metadata = map[string]string{}
+
+			err := client.systemHealth(context.TODO(), &device)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.intrusion, device.Metadata["chassis.intrusion"])
+			assert.Equal(t, tc.health, device.Status.Health)
+			assert.Equal(t, tc.failing, device.Metadata["health.failing_sensors"])
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	snmpCommunityEndpoint   = "api/settings/pef/snmp_community"
+	lanDestinationsEndpoint = "api/settings/pef/lan_destinations"
+
+	// maxSNMPCommunityLength is the IPMI limit of the community string length
+	maxSNMPCommunityLength = 18
+
+	// lanDestinationSNMP is the LAN alert destination type of SNMP trap destinations
+	lanDestinationSNMP = "snmp"
+)
+
+// snmpCommunity is the SNMP v2c community the BMC sends traps with
+// { "community": "public" }
+type snmpCommunity struct {
+	Community string `json:"community"`
+}
+
+// lanDestination is a BMC LAN alert destination
+// { "id": 1, "channel_id": 1, "lan_channel": "eth0", "destination_type": "snmp", "destination_address": "10.0.0.1" }
+type lanDestination struct {
+	ID                 int    `json:"id"`
+	ChannelID          int    `json:"channel_id"`
+	LanChannel         string `json:"lan_channel"`
+	DestinationType    string `json:"destination_type"`
+	DestinationAddress string `json:"destination_address"`
+}
+
+// SNMPConfig is the BMC SNMP trap configuration
+type SNMPConfig struct {
+	Community        string   `json:"community"`
+	TrapDestinations []string `json:"trap_destinations"`
+}
+
+// GetSNMPConfig returns the SNMP v2c community and the IP addresses the BMC sends SNMP traps to.
+//
+// ErrSNMPUnsupported is returned when the BMC firmware does not expose the SNMP trap configuration.
+func (a *ASRockRack) GetSNMPConfig(ctx context.Context) (*SNMPConfig, error) {
+	community := &snmpCommunity{}
+	if err := a.snmpQuery(ctx, snmpCommunityEndpoint, "GET", nil, community); err != nil {
+		return nil, err
+	}
+
+	destinations, err := a.lanDestinations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &SNMPConfig{Community: community.Community, TrapDestinations: []string{}}
+
+	for _, destination := range destinations {
+		if destination.DestinationType == lanDestinationSNMP && strings.TrimSpace(destination.DestinationAddress) != "" {
+			config.TrapDestinations = append(config.TrapDestinations, strings.TrimSpace(destination.DestinationAddress))
+		}
+	}
+
+	return config, nil
+}
+
+// SetSNMPConfig sets the SNMP v2c community and the IP addresses the BMC sends SNMP traps to,
+// replacing the configured SNMP trap destinations. Alert destinations of other types are left unchanged.
+//
+// ErrSNMPConfigInvalid is returned when the community is empty or longer than 18 characters, a trap destination
+// is not an IP address or the BMC has fewer alert destination slots than trap destinations given.
+func (a *ASRockRack) SetSNMPConfig(ctx context.Context, community string, trapDestinations []string) error {
+	if community == "" || len(community) > maxSNMPCommunityLength {
+		return errors.Wrap(ErrSNMPConfigInvalid, fmt.Sprintf("community length not within 1 - %d characters", maxSNMPCommunityLength))
+	}
+
+	addresses := make([]string, 0, len(trapDestinations))
+	for _, destination := range trapDestinations {
+		ip := net.ParseIP(strings.TrimSpace(destination))
+		if ip == nil {
+			return errors.Wrap(ErrSNMPConfigInvalid, fmt.Sprintf("trap destination %q is not an IP address", destination))
+		}
+
+		addresses = append(addresses, ip.String())
+	}
+
+	destinations, err := a.lanDestinations(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the SNMP and unused destination slots are available for the trap destinations
+	slots := []*lanDestination{}
+	for _, destination := range destinations {
+		if destination.DestinationType == lanDestinationSNMP || strings.TrimSpace(destination.DestinationAddress) == "" {
+			slots = append(slots, destination)
+		}
+	}
+
+	if len(addresses) > len(slots) {
+		return errors.Wrap(ErrSNMPConfigInvalid, fmt.Sprintf("%d trap destinations given, %d alert destinations available", len(addresses), len(slots)))
+	}
+
+	if err := a.snmpQuery(ctx, snmpCommunityEndpoint, "PUT", &snmpCommunity{Community: community}, nil); err != nil {
+		return err
+	}
+
+	for idx, slot := range slots {
+		// the slots beyond the trap destinations given are cleared, unused slots are left as is
+		if idx >= len(addresses) && slot.DestinationType != lanDestinationSNMP {
+			continue
+		}
+
+		slot.DestinationType, slot.DestinationAddress = lanDestinationSNMP, ""
+		if idx < len(addresses) {
+			slot.DestinationAddress = addresses[idx]
+		}
+
+		endpoint := lanDestinationsEndpoint + "/" + strconv.Itoa(slot.ID)
+		if err := a.snmpQuery(ctx, endpoint, "PUT", slot, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *ASRockRack) lanDestinations(ctx context.Context) ([]*lanDestination, error) {
+	destinations := []*lanDestination{}
+	if err := a.snmpQuery(ctx, lanDestinationsEndpoint, "GET", nil, &destinations); err != nil {
+		return nil, err
+	}
+
+	return destinations, nil
+}
+
+// snmpQuery sends the payload to the SNMP configuration endpoint, decoding the response into the given value if any
+func (a *ASRockRack) snmpQuery(ctx context.Context, endpoint, method string, payload, response interface{}) error {
+	var body io.Reader
+	var headers map[string]string
+
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrap(ErrSNMPConfig, err.Error())
+		}
+
+		body = bytes.NewReader(b)
+		headers = map[string]string{"Content-Type": "application/json"}
+	}
+
+	resp, statusCode, err := a.queryHTTPS(ctx, endpoint, method, body, headers, 0)
+	if err != nil {
+		return errors.Wrap(ErrSNMPConfig, err.Error())
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return wrapStatusCodeError(ErrSNMPUnsupported, statusCode)
+	default:
+		return wrapStatusCodeError(ErrSNMPConfig, statusCode)
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp, response); err != nil {
+		return errors.Wrap(ErrSNMPConfig, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSNMPBMC returns a BMC serving the SNMP community and LAN alert destinations, PUT requests update the settings
+func mockSNMPBMC(community *snmpCommunity, destinations []*lanDestination) *httptest.Server {
+	var mu sync.Mutex
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/settings/pef/snmp_community", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(community)
+		case http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(community); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}
+	})
+
+	handler.HandleFunc("/api/settings/pef/lan_destinations", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(destinations)
+	})
+
+	handler.HandleFunc("/api/settings/pef/lan_destinations/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/settings/pef/lan_destinations/"))
+		if err != nil || id < 1 || id > len(destinations) || r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(destinations[id-1]); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_SNMPConfig(t *testing.T) {
+	destinations := []*lanDestination{
+		{ID: 1, ChannelID: 1, LanChannel: "eth0", DestinationType: "snmp", DestinationAddress: "10.0.0.1"},
+		{ID: 2, ChannelID: 1, LanChannel: "eth0", DestinationType: "email", DestinationAddress: "ops@example.com"},
+		{ID: 3, ChannelID: 1, LanChannel: "eth0", DestinationType: "snmp"},
+		{ID: 4, ChannelID: 1, LanChannel: "eth0", DestinationType: "snmp", DestinationAddress: "10.0.0.4"},
+	}
+
+	server := mockSNMPBMC(&snmpCommunity{Community: "public"}, destinations)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	config, err := client.GetSNMPConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, &SNMPConfig{Community: "public", TrapDestinations: []string{"10.0.0.1", "10.0.0.4"}}, config)
+
+	err = client.SetSNMPConfig(context.TODO(), "monitoring", []string{"192.168.1.10", "2001:db8::10"})
+	assert.Nil(t, err)
+
+	config, err = client.GetSNMPConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, &SNMPConfig{Community: "monitoring", TrapDestinations: []string{"192.168.1.10", "2001:db8::10"}}, config)
+
+	// the email alert destination is left unchanged, the remaining SNMP destination is cleared
+	assert.Equal(t, "ops@example.com", destinations[1].DestinationAddress)
+	assert.Equal(t, "", destinations[3].DestinationAddress)
+
+	// invalid configurations are not applied
+	for _, tc := range []struct {
+		community    string
+		destinations []string
+	}{
+		{"", []string{"192.168.1.10"}},
+		{"a community string too long", []string{"192.168.1.10"}},
+		{"monitoring", []string{"traps.example.com"}},
+		{"monitoring", []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}},
+	} {
+		err = client.SetSNMPConfig(context.TODO(), tc.community, tc.destinations)
+		assert.ErrorIs(t, err, ErrSNMPConfigInvalid)
+	}
+
+	config, err = client.GetSNMPConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, &SNMPConfig{Community: "monitoring", TrapDestinations: []string{"192.168.1.10", "2001:db8::10"}}, config)
+}
+
+func Test_SNMPConfigUnsupported(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err := client.GetSNMPConfig(context.TODO())
+	assert.ErrorIs(t, err, ErrSNMPUnsupported)
+
+	err = client.SetSNMPConfig(context.TODO(), "public", []string{"10.0.0.1"})
+	assert.ErrorIs(t, err, ErrSNMPUnsupported)
+}
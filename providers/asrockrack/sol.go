@@ -0,0 +1,161 @@
+package asrockrack
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+// solSettings is the payload returned by the SOL settings endpoint
+type solSettings struct {
+	Enable   int `json:"enable"`
+	BaudRate int `json:"baud_rate"`
+}
+
+// SerialConsole establishes a Serial-over-LAN session with the BMC and returns the console stream.
+//
+// The session is torn down when the returned stream is closed or the given context is canceled.
+// ErrSOLDisabled is returned when SOL is disabled in the BMC settings.
+func (a *ASRockRack) SerialConsole(ctx context.Context) (io.ReadWriteCloser, error) {
+	settings, err := a.solSettings(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ErrSOLSession, err.Error())
+	}
+
+	if settings.Enable != 1 {
+		return nil, ErrSOLDisabled
+	}
+
+	conn, err := a.dialSOL(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ErrSOLSession, err.Error())
+	}
+
+	return newSOLConsole(ctx, conn), nil
+}
+
+// Query the SOL settings endpoint
+func (a *ASRockRack) solSettings(ctx context.Context) (*solSettings, error) {
+	resp, statusCode, err := a.queryHTTPS(ctx, "api/settings/sol", "GET", nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCodeError(statusCode)
+	}
+
+	settings := &solSettings{}
+	err = json.Unmarshal(resp, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// dialSOL opens the SOL websocket, authenticated with the current session cookie and CSRF token
+func (a *ASRockRack) dialSOL(ctx context.Context) (*websocket.Conn, error) {
+	if a.configErr != nil {
+		return nil, a.configErr
+	}
+
+	origin := a.baseURL
+
+	// wss://<host>/sol, ws:// when the BMC is reached over HTTP
+	config, err := websocket.NewConfig("ws"+strings.TrimPrefix(a.baseURL, "http")+"sol", origin)
+	if err != nil {
+		return nil, err
+	}
+
+	a.setRequestHeaders(ctx, config.Header)
+	config.Header.Add("X-CSRFTOKEN", a.csrfToken())
+
+	// include session cookies
+	if a.httpClient.Jar != nil {
+		originURL, err := url.Parse(origin)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cookie := range a.httpClient.Jar.Cookies(originURL) {
+			config.Header.Add("Cookie", cookie.String())
+		}
+	}
+
+	// use the TLS configuration of the provider HTTP client
+	if transport, ok := a.httpClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		config.TlsConfig = transport.TLSClientConfig.Clone()
+	} else {
+		config.TlsConfig = &tls.Config{}
+	}
+
+	dialer := &net.Dialer{}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+
+	config.Dialer = dialer
+
+	conn, err := websocket.DialConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.PayloadType = websocket.BinaryFrame
+
+	return conn, nil
+}
+
+// solConsole is the SOL session console stream
+type solConsole struct {
+	conn      *websocket.Conn
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newSOLConsole(ctx context.Context, conn *websocket.Conn) *solConsole {
+	console := &solConsole{conn: conn, closed: make(chan struct{})}
+
+	// tear down the session on context cancellation
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = console.Close()
+		case <-console.closed:
+		}
+	}()
+
+	return console
+}
+
+// Read reads console output from the SOL session
+func (c *solConsole) Read(p []byte) (int, error) {
+	return c.conn.Read(p)
+}
+
+// Write writes console input to the SOL session
+func (c *solConsole) Write(p []byte) (int, error) {
+	return c.conn.Write(p)
+}
+
+// Close ends the SOL session
+func (c *solConsole) Close() error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		err = c.conn.Close()
+	})
+
+	return err
+}
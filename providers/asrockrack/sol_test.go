@@ -0,0 +1,95 @@
+package asrockrack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func mockSOLBMC(solEnabled bool) *httptest.Server {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/session", session)
+	handler.HandleFunc("/api/settings/sol", func(w http.ResponseWriter, r *http.Request) {
+		if solEnabled {
+			_, _ = w.Write([]byte(`{ "enable": 1, "baud_rate": 115200 }`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{ "enable": 0, "baud_rate": 115200 }`))
+	})
+
+	// console echo
+	handler.Handle("/sol", websocket.Handler(func(conn *websocket.Conn) {
+		if conn.Request().Header.Get("X-Csrftoken") != "l5L29IP7" {
+			return
+		}
+
+		_, _ = io.Copy(conn, conn)
+	}))
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_SerialConsole(t *testing.T) {
+	solServer := mockSOLBMC(true)
+	defer solServer.Close()
+
+	solURL, _ := url.Parse(solServer.URL)
+	client := NewWithOptions(solURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	err := client.Open(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	console, err := client.SerialConsole(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = console.Write([]byte("root\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 16)
+	n, err := console.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "root\n", string(buf[:n]))
+
+	// context cancellation tears down the session
+	cancel()
+
+	_, err = console.Read(buf)
+	assert.NotNil(t, err)
+	assert.Nil(t, console.Close())
+}
+
+func Test_SerialConsoleDisabled(t *testing.T) {
+	solServer := mockSOLBMC(false)
+	defer solServer.Close()
+
+	solURL, _ := url.Parse(solServer.URL)
+	client := NewWithOptions(solURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	err := client.Open(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.SerialConsole(context.TODO())
+	assert.ErrorIs(t, err, ErrSOLDisabled)
+}
@@ -0,0 +1,283 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/pkg/errors"
+)
+
+// storageController is a RAID/storage controller as listed by the BMC
+type storageController struct {
+	ID                  int            `json:"id"`
+	Name                string         `json:"name"`
+	Manufacturer        string         `json:"manufacturer"`
+	Model               string         `json:"model"`
+	FirmwareVersion     string         `json:"firmware_version"`
+	SerialNumber        string         `json:"serial_number"`
+	RAIDMode            string         `json:"raid_mode"`
+	SupportedRAIDLevels string         `json:"supported_raid_levels"`
+	MaxPhysicalDisks    int            `json:"max_physical_disks"`
+	MaxVirtualDisks     int            `json:"max_virtual_disks"`
+	VirtualDisks        []*virtualDisk `json:"virtual_disks"`
+}
+
+// virtualDisk is a logical volume configured on a storage controller,
+// the member drives are listed by their serial number.
+type virtualDisk struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	RAIDLevel string   `json:"raid_level"`
+	State     string   `json:"state"`
+	Members   []string `json:"members"`
+}
+
+// storageControllers returns the storage controllers,
+// no controllers are returned when the board has no RAID controller or the firmware does not list them.
+func (a *ASRockRack) storageControllers(ctx context.Context) (_ []*storageController, err error) {
+	defer func() { err = a.endpointError("storage controllers", "api/asrr/storage/controllers", err) }()
+
+	resp, statusCode, err := a.getWithRetry(ctx, "api/asrr/storage/controllers")
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		err = statusCodeError(statusCode)
+		if errors.Is(err, ErrUnsupported) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	controllers := []*storageController{}
+	if len(strings.TrimSpace(string(resp))) == 0 {
+		return controllers, nil
+	}
+
+	if err := unmarshalTolerant(resp, &controllers); err != nil {
+		return nil, err
+	}
+
+	return controllers, nil
+}
+
+// storageControllerAttributes collects the storage controllers and links the device drives to their controller
+// and virtual disk, the controllers are returned to link drives collected separately.
+func (a *ASRockRack) storageControllerAttributes(ctx context.Context, device *common.Device) ([]*storageController, error) {
+	controllers, err := a.storageControllers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, controller := range controllers {
+		device.StorageControllers = append(device.StorageControllers, storageControllerFromInfo(controller))
+	}
+
+	linkStorageDrives(device, controllers)
+
+	return controllers, nil
+}
+
+// storageControllerFromInfo returns the storage controller attributes
+func storageControllerFromInfo(controller *storageController) *common.StorageController {
+	sc := &common.StorageController{
+		Common: common.Common{
+			Description: controller.Name,
+			Vendor:      controller.Manufacturer,
+			Model:       controller.Model,
+			Metadata:    map[string]string{},
+		},
+		ID:                 strconv.Itoa(controller.ID),
+		SupportedRAIDTypes: controller.SupportedRAIDLevels,
+		MaxPhysicalDisks:   controller.MaxPhysicalDisks,
+		MaxVirtualDisks:    controller.MaxVirtualDisks,
+	}
+
+	if !valuePlaceholder(controller.SerialNumber) {
+		sc.Serial = controller.SerialNumber
+	}
+
+	if !versionPlaceholder(controller.FirmwareVersion) {
+		sc.Firmware = &common.Firmware{Installed: controller.FirmwareVersion}
+	}
+
+	if controller.RAIDMode != "" {
+		sc.Metadata["raid_mode"] = controller.RAIDMode
+	}
+
+	sc.Metadata["virtual_disks"] = strconv.Itoa(len(controller.VirtualDisks))
+
+	return sc
+}
+
+// linkStorageDrives sets the storage controller and virtual disk membership on the device drives,
+// the drives are matched with the virtual disk members by serial number.
+//
+// storage.virtual_disk = <name>, storage.raid_level = <RAID1>
+func linkStorageDrives(device *common.Device, controllers []*storageController) {
+	for _, controller := range controllers {
+		for _, vd := range controller.VirtualDisks {
+			for _, member := range vd.Members {
+				for _, drive := range device.Drives {
+					if drive.Serial == "" || !strings.EqualFold(drive.Serial, member) {
+						continue
+					}
+
+					drive.StorageController = strconv.Itoa(controller.ID)
+
+					if drive.Metadata == nil {
+						drive.Metadata = map[string]string{}
+					}
+
+					drive.Metadata["storage.virtual_disk"] = vd.Name
+					drive.Metadata["storage.raid_level"] = vd.RAIDLevel
+				}
+			}
+		}
+	}
+}
+
+// driveSMART is the SMART data of a drive, identified by its serial number
+type driveSMART struct {
+	SerialNumber   string                 `json:"serial_number"`
+	SmartStatus    string                 `json:"smart_status"`
+	Temperature    int                    `json:"temperature"`
+	PercentageUsed int                    `json:"percentage_used"`
+	Attributes     []*driveSMARTAttribute `json:"attributes"`
+}
+
+type driveSMARTAttribute struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	Value         int    `json:"value"`
+	Worst         int    `json:"worst"`
+	Threshold     int    `json:"threshold"`
+	Raw           string `json:"raw"`
+	PreFailure    bool   `json:"prefailure"`
+	UpdatedOnline bool   `json:"updated_online"`
+}
+
+const (
+	smartAttributeReallocatedSectors = 5
+
+	// drives are reported in a WARNING state once the wear level reaches this percentage
+	driveWearLevelWarning = 90
+)
+
+// drivesSMART returns the drive SMART data,
+// none is returned when the firmware does not expose the drive SMART data.
+func (a *ASRockRack) drivesSMART(ctx context.Context) (_ []*driveSMART, err error) {
+	defer func() { err = a.endpointError("drive SMART", "api/asrr/storage/smart", err) }()
+
+	resp, statusCode, err := a.getWithRetry(ctx, "api/asrr/storage/smart")
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		err = statusCodeError(statusCode)
+		if errors.Is(err, ErrUnsupported) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	smart := []*driveSMART{}
+	if len(strings.TrimSpace(string(resp))) == 0 {
+		return smart, nil
+	}
+
+	if err := unmarshalTolerant(resp, &smart); err != nil {
+		return nil, err
+	}
+
+	return smart, nil
+}
+
+// drivesSMARTAttributes sets the SMART status, attributes and health on the device drives,
+// the SMART data is matched with the drives by serial number.
+//
+// smart.temperature = <°C>, smart.wear_level_percent = <percentage used>, smart.reallocated_sectors = <count>
+func drivesSMARTAttributes(device *common.Device, smart []*driveSMART) {
+	for _, s := range smart {
+		for _, drive := range device.Drives {
+			if drive.Serial == "" || !strings.EqualFold(drive.Serial, s.SerialNumber) {
+				continue
+			}
+
+			driveSMARTAttributes(drive, s)
+		}
+	}
+}
+
+// driveSMARTAttributes sets the SMART status, attributes and health on the drive
+//
+// the drive health is CRITICAL when the SMART status failed or a pre-failure attribute reached its threshold,
+// WARNING when any other attribute reached its threshold or the drive wear level is above 90%.
+func driveSMARTAttributes(drive *common.Drive, smart *driveSMART) {
+	if drive.Metadata == nil {
+		drive.Metadata = map[string]string{}
+	}
+
+	drive.SmartStatus = smart.SmartStatus
+	drive.Metadata["smart.temperature"] = strconv.Itoa(smart.Temperature)
+	drive.Metadata["smart.wear_level_percent"] = strconv.Itoa(smart.PercentageUsed)
+
+	health := healthOK
+
+	switch strings.ToUpper(smart.SmartStatus) {
+	case "PASSED", "OK", "":
+	default:
+		health = healthCritical
+	}
+
+	if smart.PercentageUsed >= driveWearLevelWarning && healthSeverity(health) < healthSeverity(healthWarning) {
+		health = healthWarning
+	}
+
+	for _, attr := range smart.Attributes {
+		drive.SmartAttributes = append(drive.SmartAttributes, &common.DriveSmartAttributes{
+			Name:            attr.Name,
+			NormalizedValue: attr.Value,
+			Worst:           attr.Worst,
+			Threshold:       attr.Threshold,
+			PreFailure:      attr.PreFailure,
+			UpdatedOnline:   attr.UpdatedOnline,
+		})
+
+		if attr.ID == smartAttributeReallocatedSectors {
+			drive.Metadata["smart.reallocated_sectors"] = attr.Raw
+		}
+
+		// a zero threshold indicates the attribute has no failure threshold
+		if attr.Threshold == 0 || attr.Value > attr.Threshold {
+			continue
+		}
+
+		drive.SmartErrors = append(drive.SmartErrors, attr.Name)
+
+		attrHealth := healthWarning
+		if attr.PreFailure {
+			attrHealth = healthCritical
+		}
+
+		if healthSeverity(attrHealth) > healthSeverity(health) {
+			health = attrHealth
+		}
+	}
+
+	drive.Status = &common.Status{Health: health}
+
+	switch {
+	case len(drive.SmartErrors) > 0:
+		drive.Status.State = strings.Join(drive.SmartErrors, ",")
+	case health == healthCritical:
+		drive.Status.State = "SMART status " + smart.SmartStatus
+	}
+}
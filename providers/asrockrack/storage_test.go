@@ -0,0 +1,162 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_storageControllerAttributes(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/storage/controllers", storageControllersInfo)
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device := common.NewDevice()
+	device.Drives = []*common.Drive{
+		{Common: common.Common{Serial: "PHYF001303ED480BGN"}},
+		{Common: common.Common{Serial: "BTYF01940L38480BGN"}},
+		{Common: common.Common{Serial: "S4EVNF0M123456"}},
+	}
+
+	controllers, err := client.storageControllerAttributes(context.TODO(), &device)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, len(controllers))
+	assert.Equal(t, 1, len(device.StorageControllers))
+
+	sc := device.StorageControllers[0]
+	assert.Equal(t, "0", sc.ID)
+	assert.Equal(t, "Intel", sc.Vendor)
+	assert.Equal(t, "C246 RSTe", sc.Model)
+	assert.Equal(t, "", sc.Serial)
+	assert.Equal(t, "5.1.0.1006", sc.Firmware.Installed)
+	assert.Equal(t, "RAID0,RAID1,RAID5,RAID10", sc.SupportedRAIDTypes)
+	assert.Equal(t, "RAID", sc.Metadata["raid_mode"])
+	assert.Equal(t, "1", sc.Metadata["virtual_disks"])
+
+	for _, drive := range device.Drives[:2] {
+		assert.Equal(t, "0", drive.StorageController)
+		assert.Equal(t, "Volume0", drive.Metadata["storage.virtual_disk"])
+		assert.Equal(t, "RAID1", drive.Metadata["storage.raid_level"])
+	}
+
+	// drives which are not a virtual disk member are not linked
+	assert.Equal(t, "", device.Drives[2].StorageController)
+	assert.Nil(t, device.Drives[2].Metadata)
+}
+
+func Test_storageControllerAttributesNoController(t *testing.T) {
+	// the endpoint is not available on boards without a RAID controller
+	server := httptest.NewTLSServer(http.NewServeMux())
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device := common.NewDevice()
+
+	controllers, err := client.storageControllerAttributes(context.TODO(), &device)
+	assert.Nil(t, err)
+	assert.Empty(t, controllers)
+	assert.Empty(t, device.StorageControllers)
+}
+
+func Test_drivesSMARTAttributes(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/asrr/storage/smart", drivesSMARTInfo)
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	smart, err := client.drivesSMART(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	device := common.NewDevice()
+	device.Drives = []*common.Drive{
+		{Common: common.Common{Serial: "PHYF001303ED480BGN"}},
+		{Common: common.Common{Serial: "BTYF01940L38480BGN"}},
+		{Common: common.Common{Serial: "S4EVNF0M123456"}},
+	}
+
+	drivesSMARTAttributes(&device, smart)
+
+	healthy := device.Drives[0]
+	assert.Equal(t, "OK", healthy.Status.Health)
+	assert.Equal(t, "PASSED", healthy.SmartStatus)
+	assert.Equal(t, 3, len(healthy.SmartAttributes))
+	assert.Empty(t, healthy.SmartErrors)
+	assert.Equal(t, "31", healthy.Metadata["smart.temperature"])
+	assert.Equal(t, "3", healthy.Metadata["smart.wear_level_percent"])
+	assert.Equal(t, "0", healthy.Metadata["smart.reallocated_sectors"])
+
+	failing := device.Drives[1]
+	assert.NotEqual(t, "OK", failing.Status.Health)
+	assert.Equal(t, "CRITICAL", failing.Status.Health)
+	assert.Equal(t, "FAILED", failing.SmartStatus)
+	assert.Equal(t, []string{"Reallocated_Sector_Ct"}, failing.SmartErrors)
+	assert.Equal(t, "Reallocated_Sector_Ct", failing.Status.State)
+	assert.Equal(t, "1928", failing.Metadata["smart.reallocated_sectors"])
+	assert.Equal(t, "97", failing.Metadata["smart.wear_level_percent"])
+
+	// no SMART data for the drive
+	assert.Nil(t, device.Drives[2].Status)
+}
+
+func Test_driveSMARTAttributesHealth(t *testing.T) {
+	testCases := []struct {
+		name   string
+		smart  *driveSMART
+		health string
+		state  string
+	}{
+		{
+			"worn out",
+			&driveSMART{SmartStatus: "PASSED", PercentageUsed: 92},
+			"WARNING",
+			"",
+		},
+		{
+			"old age attribute below threshold",
+			&driveSMART{
+				SmartStatus: "PASSED",
+				Attributes:  []*driveSMARTAttribute{{ID: 190, Name: "Airflow_Temperature_Cel", Value: 40, Threshold: 45}},
+			},
+			"WARNING",
+			"Airflow_Temperature_Cel",
+		},
+		{
+			"SMART status failed",
+			&driveSMART{SmartStatus: "FAILED"},
+			"CRITICAL",
+			"SMART status FAILED",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drive := &common.Drive{}
+			driveSMARTAttributes(drive, tc.smart)
+
+			assert.Equal(t, tc.health, drive.Status.Health)
+			assert.Equal(t, tc.state, drive.Status.State)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+const (
+	syslogServersEndpoint = "api/settings/log/remote-servers"
+
+	// defaultSyslogPort is the port used for syslog servers configured without a port
+	defaultSyslogPort = 514
+)
+
+// SyslogServer is a remote syslog server the BMC forwards its logs to
+type SyslogServer struct {
+	// Host is the syslog server IP address or host name
+	Host string `json:"host"`
+	// Port defaults to 514 when not set
+	Port int `json:"port"`
+	// Protocol is either udp or tcp, defaults to udp when not set
+	Protocol string `json:"protocol"`
+}
+
+// remoteSyslogServer is a BMC remote syslog server slot
+// { "id": 1, "enable": 1, "server_address": "10.0.0.1", "port": 514, "protocol": "udp" }
+type remoteSyslogServer struct {
+	ID            int    `json:"id"`
+	Enable        int    `json:"enable"`
+	ServerAddress string `json:"server_address"`
+	Port          int    `json:"port"`
+	Protocol      string `json:"protocol"`
+}
+
+// GetSyslogConfig returns the remote syslog servers the BMC forwards its logs to.
+//
+// ErrSyslogUnsupported is returned when the BMC firmware does not expose the remote syslog configuration.
+func (a *ASRockRack) GetSyslogConfig(ctx context.Context) ([]SyslogServer, error) {
+	slots, err := a.remoteSyslogServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	servers := []SyslogServer{}
+	for _, slot := range slots {
+		if slot.Enable == 1 && strings.TrimSpace(slot.ServerAddress) != "" {
+			servers = append(servers, SyslogServer{Host: slot.ServerAddress, Port: slot.Port, Protocol: slot.Protocol})
+		}
+	}
+
+	return servers, nil
+}
+
+// SetSyslogConfig configures the BMC to forward its logs to the remote syslog servers, replacing the configured servers,
+// no servers disables the log forwarding.
+//
+// The servers are validated before any is configured, the invalid servers are returned in a multierror
+// of ErrSyslogServerInvalid errors - one for each invalid server. ErrSyslogServerInvalid is also returned
+// when more servers are given than the BMC supports.
+func (a *ASRockRack) SetSyslogConfig(ctx context.Context, servers []SyslogServer) error {
+	var invalid *multierror.Error
+
+	normalized := make([]SyslogServer, 0, len(servers))
+	for idx, server := range servers {
+		server, err := normalizeSyslogServer(server)
+		if err != nil {
+			invalid = multierror.Append(invalid, errors.Wrap(err, fmt.Sprintf("syslog server %d", idx+1)))
+			continue
+		}
+
+		normalized = append(normalized, server)
+	}
+
+	if invalid != nil {
+		return invalid.ErrorOrNil()
+	}
+
+	slots, err := a.remoteSyslogServers(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(normalized) > len(slots) {
+		return errors.Wrap(ErrSyslogServerInvalid, fmt.Sprintf("%d servers given, up to %d are supported", len(normalized), len(slots)))
+	}
+
+	for idx, slot := range slots {
+		update := &remoteSyslogServer{ID: slot.ID, Port: defaultSyslogPort, Protocol: "udp"}
+		if idx < len(normalized) {
+			update.Enable = 1
+			update.ServerAddress = normalized[idx].Host
+			update.Port = normalized[idx].Port
+			update.Protocol = normalized[idx].Protocol
+		}
+
+		if err := a.setRemoteSyslogServer(ctx, update); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeSyslogServer returns the syslog server with the default port and protocol set,
+// ErrSyslogServerInvalid is returned when the host, port or protocol is not valid.
+func normalizeSyslogServer(server SyslogServer) (SyslogServer, error) {
+	server.Host = strings.TrimSpace(server.Host)
+	if !validHost(server.Host) {
+		return server, errors.Wrap(ErrSyslogServerInvalid, fmt.Sprintf("host %q is not an IP address or host name", server.Host))
+	}
+
+	if server.Port == 0 {
+		server.Port = defaultSyslogPort
+	}
+
+	if server.Port < 1 || server.Port > 65535 {
+		return server, errors.Wrap(ErrSyslogServerInvalid, fmt.Sprintf("port %d not within 1 - 65535", server.Port))
+	}
+
+	server.Protocol = strings.ToLower(strings.TrimSpace(server.Protocol))
+	switch server.Protocol {
+	case "":
+		server.Protocol = "udp"
+	case "udp", "tcp":
+	default:
+		return server, errors.Wrap(ErrSyslogServerInvalid, fmt.Sprintf("protocol %q is not udp or tcp", server.Protocol))
+	}
+
+	return server, nil
+}
+
+func (a *ASRockRack) remoteSyslogServers(ctx context.Context) ([]*remoteSyslogServer, error) {
+	resp, statusCode, err := a.getWithRetry(ctx, syslogServersEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(ErrSyslogConfig, err.Error())
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, wrapStatusCodeError(ErrSyslogUnsupported, statusCode)
+	default:
+		return nil, wrapStatusCodeError(ErrSyslogConfig, statusCode)
+	}
+
+	slots := []*remoteSyslogServer{}
+	if err := json.Unmarshal(resp, &slots); err != nil {
+		return nil, errors.Wrap(ErrSyslogConfig, err.Error())
+	}
+
+	return slots, nil
+}
+
+func (a *ASRockRack) setRemoteSyslogServer(ctx context.Context, slot *remoteSyslogServer) error {
+	payload, err := json.Marshal(slot)
+	if err != nil {
+		return errors.Wrap(ErrSyslogConfig, err.Error())
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	endpoint := syslogServersEndpoint + "/" + strconv.Itoa(slot.ID)
+
+	_, statusCode, err := a.queryHTTPS(ctx, endpoint, "PUT", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return errors.Wrap(ErrSyslogConfig, err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		return wrapStatusCodeError(ErrSyslogConfig, statusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSyslogBMC returns a BMC serving the remote syslog server slots, PUT requests update the slots
+func mockSyslogBMC(slots []*remoteSyslogServer) *httptest.Server {
+	var mu sync.Mutex
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/settings/log/remote-servers", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(slots)
+	})
+
+	handler.HandleFunc("/api/settings/log/remote-servers/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/settings/log/remote-servers/"))
+		if err != nil || id < 1 || id > len(slots) || r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(slots[id-1]); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_SyslogConfig(t *testing.T) {
+	slots := []*remoteSyslogServer{
+		{ID: 1, Enable: 1, ServerAddress: "10.0.0.1", Port: 514, Protocol: "udp"},
+		{ID: 2, Port: 514, Protocol: "udp"},
+	}
+
+	server := mockSyslogBMC(slots)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	servers, err := client.GetSyslogConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []SyslogServer{{Host: "10.0.0.1", Port: 514, Protocol: "udp"}}, servers)
+
+	// two destinations, the port and protocol defaults are applied
+	err = client.SetSyslogConfig(context.TODO(), []SyslogServer{
+		{Host: "syslog1.example.com", Port: 6514, Protocol: "TCP"},
+		{Host: "192.168.1.20"},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []*remoteSyslogServer{
+		{ID: 1, Enable: 1, ServerAddress: "syslog1.example.com", Port: 6514, Protocol: "tcp"},
+		{ID: 2, Enable: 1, ServerAddress: "192.168.1.20", Port: 514, Protocol: "udp"},
+	}, slots)
+
+	servers, err = client.GetSyslogConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, []SyslogServer{
+		{Host: "syslog1.example.com", Port: 6514, Protocol: "tcp"},
+		{Host: "192.168.1.20", Port: 514, Protocol: "udp"},
+	}, servers)
+
+	// no servers disables the forwarding
+	err = client.SetSyslogConfig(context.TODO(), nil)
+	assert.Nil(t, err)
+
+	servers, err = client.GetSyslogConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Empty(t, servers)
+}
+
+func Test_SetSyslogConfigInvalid(t *testing.T) {
+	slots := []*remoteSyslogServer{{ID: 1, Port: 514, Protocol: "udp"}, {ID: 2, Port: 514, Protocol: "udp"}}
+
+	server := mockSyslogBMC(slots)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	// an error is returned for each invalid server
+	err := client.SetSyslogConfig(context.TODO(), []SyslogServer{
+		{Host: "syslog_1.example.com"},
+		{Host: "192.168.1.20", Port: 70000},
+		{Host: "192.168.1.21", Protocol: "tls"},
+	})
+	assert.ErrorIs(t, err, ErrSyslogServerInvalid)
+
+	merr, ok := err.(*multierror.Error)
+	if assert.True(t, ok) {
+		assert.Len(t, merr.Errors, 3)
+	}
+
+	// more servers than the BMC supports
+	err = client.SetSyslogConfig(context.TODO(), []SyslogServer{{Host: "10.0.0.1"}, {Host: "10.0.0.2"}, {Host: "10.0.0.3"}})
+	assert.ErrorIs(t, err, ErrSyslogServerInvalid)
+
+	// no servers were configured
+	for _, slot := range slots {
+		assert.Equal(t, 0, slot.Enable)
+	}
+}
+
+func Test_SyslogConfigUnsupported(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	_, err := client.GetSyslogConfig(context.TODO())
+	assert.ErrorIs(t, err, ErrSyslogUnsupported)
+}
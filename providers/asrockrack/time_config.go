@@ -0,0 +1,159 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const dateTimeEndpoint = "api/settings/date-time"
+
+// maxNTPServers is the number of NTP servers the BMC supports - a primary and a secondary server
+const maxNTPServers = 2
+
+// dateTimeSettings is the BMC date and time configuration
+// { "timestamp": 1665744000, "utc_minutes": 0, "timezone": "Etc/UTC", "auto_date": 1, "primary_ntp": "pool.ntp.org", "secondary_ntp": "" }
+//
+// The timestamp sets the BMC clock when submitted, it is omitted to leave the clock unchanged.
+type dateTimeSettings struct {
+	Timestamp    int64  `json:"timestamp,omitempty"`
+	UTCMinutes   int    `json:"utc_minutes"`
+	Timezone     string `json:"timezone"`
+	AutoDate     int    `json:"auto_date"`
+	PrimaryNTP   string `json:"primary_ntp"`
+	SecondaryNTP string `json:"secondary_ntp"`
+}
+
+// BMCTimeConfig is the BMC clock and NTP configuration
+type BMCTimeConfig struct {
+	// Time is the BMC clock time when the configuration was read
+	Time       time.Time `json:"time"`
+	Timezone   string    `json:"timezone"`
+	NTPEnabled bool      `json:"ntp_enabled"`
+	NTPServers []string  `json:"ntp_servers"`
+}
+
+// GetBMCTimeConfig returns the BMC clock time, timezone and the NTP servers the BMC clock is synchronized with
+func (a *ASRockRack) GetBMCTimeConfig(ctx context.Context) (*BMCTimeConfig, error) {
+	settings, err := a.dateTimeSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &BMCTimeConfig{
+		Time:       time.Unix(settings.Timestamp, 0).UTC(),
+		Timezone:   settings.Timezone,
+		NTPEnabled: settings.AutoDate == 1,
+		NTPServers: []string{},
+	}
+
+	for _, server := range []string{settings.PrimaryNTP, settings.SecondaryNTP} {
+		if server = strings.TrimSpace(server); server != "" {
+			config.NTPServers = append(config.NTPServers, server)
+		}
+	}
+
+	return config, nil
+}
+
+// SetBMCTimeConfig configures the BMC clock to be synchronized with the NTP servers, in the given timezone.
+//
+// Up to two NTP servers are supported, each an IP address or host name, ErrNTPServerInvalid is returned for
+// any other value. No NTP servers disables the NTP synchronization, an empty timezone leaves the timezone unchanged.
+// The timezone is an IANA time zone name - "Europe/Berlin", ErrTimezoneInvalid is returned for any other value.
+//
+// The BMC clock is left unchanged, the clock time read with the configuration is not submitted.
+func (a *ASRockRack) SetBMCTimeConfig(ctx context.Context, servers []string, tz string) error {
+	if len(servers) > maxNTPServers {
+		return errors.Wrap(ErrNTPServerInvalid, fmt.Sprintf("%d servers given, up to %d are supported", len(servers), maxNTPServers))
+	}
+
+	ntpServers := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if !validHost(strings.TrimSpace(server)) {
+			return errors.Wrap(ErrNTPServerInvalid, fmt.Sprintf("%q is not an IP address or host name", server))
+		}
+
+		ntpServers = append(ntpServers, strings.TrimSpace(server))
+	}
+
+	var location *time.Location
+
+	// Local is the host timezone, not a timezone the BMC can be configured with
+	if tz = strings.TrimSpace(tz); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil || tz == "Local" {
+			return errors.Wrap(ErrTimezoneInvalid, fmt.Sprintf("%q is not an IANA time zone name", tz))
+		}
+
+		location = loc
+	}
+
+	settings, err := a.dateTimeSettings(ctx)
+	if err != nil {
+		return err
+	}
+
+	// a stale timestamp sets the BMC clock back by the time since it was read
+	settings.Timestamp = 0
+
+	settings.AutoDate, settings.PrimaryNTP, settings.SecondaryNTP = 0, "", ""
+
+	if len(ntpServers) > 0 {
+		settings.AutoDate = 1
+		settings.PrimaryNTP = ntpServers[0]
+	}
+
+	if len(ntpServers) > 1 {
+		settings.SecondaryNTP = ntpServers[1]
+	}
+
+	// the UTC offset is updated along with the timezone, the offset currently in effect
+	if location != nil {
+		_, offset := time.Now().In(location).Zone()
+		settings.Timezone, settings.UTCMinutes = tz, offset/60
+	}
+
+	payload, err := json.Marshal(settings)
+	if err != nil {
+		return errors.Wrap(ErrTimeConfigWrite, err.Error())
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	_, statusCode, err := a.queryHTTPS(ctx, dateTimeEndpoint, "PUT", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return errors.Wrap(ErrTimeConfigWrite, err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		return wrapStatusCodeError(ErrTimeConfigWrite, statusCode)
+	}
+
+	return nil
+}
+
+// dateTimeSettings returns the BMC date and time configuration
+func (a *ASRockRack) dateTimeSettings(ctx context.Context) (*dateTimeSettings, error) {
+	resp, statusCode, err := a.getWithRetry(ctx, dateTimeEndpoint)
+	if err != nil {
+		return nil, errors.Wrap(ErrTimeConfigRead, err.Error())
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, wrapStatusCodeError(ErrTimeConfigRead, statusCode)
+	}
+
+	settings := &dateTimeSettings{}
+	if err := json.Unmarshal(resp, settings); err != nil {
+		return nil, errors.Wrap(ErrTimeConfigRead, err.Error())
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,128 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockDateTimeBMC returns a BMC serving the date and time settings, PUT requests update the settings
+func mockDateTimeBMC(settings *dateTimeSettings) (*httptest.Server, func() []*dateTimeSettings) {
+	var mu sync.Mutex
+	var requests []*dateTimeSettings
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/settings/date-time", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(settings)
+		case http.MethodPut:
+			request := &dateTimeSettings{}
+			if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			requests = append(requests, request)
+			*settings = *request
+		}
+	})
+
+	submitted := func() []*dateTimeSettings {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return requests
+	}
+
+	return httptest.NewTLSServer(handler), submitted
+}
+
+func Test_BMCTimeConfig(t *testing.T) {
+	server, submitted := mockDateTimeBMC(&dateTimeSettings{Timestamp: 1665744000, Timezone: "Etc/UTC"})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	config, err := client.GetBMCTimeConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, &BMCTimeConfig{Time: time.Unix(1665744000, 0).UTC(), Timezone: "Etc/UTC", NTPServers: []string{}}, config)
+
+	err = client.SetBMCTimeConfig(context.TODO(), []string{"ntp1.example.com", " 10.0.0.123"}, "Asia/Kolkata")
+	assert.Nil(t, err)
+
+	// the clock time is not submitted, the UTC offset is updated with the timezone
+	assert.Equal(t, []*dateTimeSettings{
+		{
+			UTCMinutes:   330,
+			Timezone:     "Asia/Kolkata",
+			AutoDate:     1,
+			PrimaryNTP:   "ntp1.example.com",
+			SecondaryNTP: "10.0.0.123",
+		},
+	}, submitted())
+
+	config, err = client.GetBMCTimeConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.True(t, config.NTPEnabled)
+	assert.Equal(t, "Asia/Kolkata", config.Timezone)
+	assert.Equal(t, []string{"ntp1.example.com", "10.0.0.123"}, config.NTPServers)
+
+	// no servers disables NTP, keeping the timezone
+	err = client.SetBMCTimeConfig(context.TODO(), nil, "")
+	assert.Nil(t, err)
+
+	config, err = client.GetBMCTimeConfig(context.TODO())
+	assert.Nil(t, err)
+	assert.False(t, config.NTPEnabled)
+	assert.Equal(t, "Asia/Kolkata", config.Timezone)
+	assert.Empty(t, config.NTPServers)
+	assert.Equal(t, 330, submitted()[1].UTCMinutes)
+}
+
+func Test_SetBMCTimeConfigInvalidServer(t *testing.T) {
+	server, submitted := mockDateTimeBMC(&dateTimeSettings{Timezone: "Etc/UTC"})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	for _, servers := range [][]string{
+		{"ntp_1.example.com"},
+		{"-ntp.example.com"},
+		{"ntp.example.com", ""},
+		{"ntp1.example.com", "ntp2.example.com", "ntp3.example.com"},
+	} {
+		err := client.SetBMCTimeConfig(context.TODO(), servers, "")
+		assert.ErrorIs(t, err, ErrNTPServerInvalid)
+	}
+
+	assert.Empty(t, submitted())
+}
+
+func Test_SetBMCTimeConfigInvalidTimezone(t *testing.T) {
+	server, submitted := mockDateTimeBMC(&dateTimeSettings{Timezone: "Etc/UTC"})
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	for _, tz := range []string{"Europe/Atlantis", "CEST+2", "Local", "../../etc/passwd"} {
+		err := client.SetBMCTimeConfig(context.TODO(), []string{"pool.ntp.org"}, tz)
+		assert.ErrorIs(t, err, ErrTimezoneInvalid)
+	}
+
+	assert.Empty(t, submitted())
+}
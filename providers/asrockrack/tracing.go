@@ -0,0 +1,61 @@
+package asrockrack
+
+import (
+	"context"
+	"strings"
+)
+
+// Tracer is implemented by tracing libraries to create a span for each BMC request made by the provider,
+// an OpenTelemetry trace.Tracer can be adapted by wrapping Start and the returned span.
+type Tracer interface {
+	// Start creates a span named after the operation - the request method and endpoint "GET api/sensors",
+	// as a child of any span in the given context. The returned context carries the span.
+	//
+	// The operation is named as observed by the MetricsRecorder, the http.endpoint attribute holds the requested endpoint.
+	Start(ctx context.Context, op string) (context.Context, Span)
+}
+
+// Span is a span created by the Tracer for a BMC request
+type Span interface {
+	// SetAttribute annotates the span - bmc.host, http.method, http.endpoint and http.status_code
+	SetAttribute(key string, value interface{})
+	// RecordError records the request error on the span
+	RecordError(err error)
+	// End completes the span
+	End()
+}
+
+// WithTracer sets the tracer to create a span for each BMC request made by the provider,
+// no spans are created unless a tracer is set.
+func WithTracer(tracer Tracer) ASRockOption {
+	return func(ar *ASRockRack) {
+		ar.tracer = tracer
+	}
+}
+
+// startSpan starts a span for the BMC request when a tracer is set,
+// the returned func ends the span with the response status code and request error.
+func (a *ASRockRack) startSpan(ctx context.Context, method, endpoint string) (context.Context, func(statusCode int, err error)) {
+	if a.tracer == nil {
+		return ctx, func(int, error) {}
+	}
+
+	endpoint = strings.TrimPrefix(endpoint, "/")
+
+	ctx, span := a.tracer.Start(ctx, requestOp(method, endpoint))
+	span.SetAttribute("bmc.host", a.ip)
+	span.SetAttribute("http.method", method)
+	span.SetAttribute("http.endpoint", endpoint)
+
+	return ctx, func(statusCode int, err error) {
+		if statusCode != 0 {
+			span.SetAttribute("http.status_code", statusCode)
+		}
+
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		span.End()
+	}
+}
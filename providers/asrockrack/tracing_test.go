@@ -0,0 +1,108 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+type spanParentKey struct{}
+
+type recordedSpan struct {
+	name       string
+	parent     string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *recordedSpan) RecordError(err error)                      { s.err = err }
+func (s *recordedSpan) End()                                       { s.ended = true }
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, op string) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parent, _ := ctx.Value(spanParentKey{}).(string)
+	span := &recordedSpan{name: op, parent: parent, attributes: map[string]interface{}{}}
+	r.spans = append(r.spans, span)
+
+	return context.WithValue(ctx, spanParentKey{}, op), span
+}
+
+func Test_WithTracer(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/chassis-status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{ "power_status": 1, "led_status": 0 }`))
+	})
+
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	tracer := &recordingTracer{}
+	client := NewWithOptions(
+		serverURL.Host,
+		"foo",
+		"bar",
+		logr.Discard(),
+		WithInsecureSkipVerify(),
+		WithTracer(tracer),
+		WithRetryPolicy(RetryPolicy{}),
+	)
+
+	ctx := context.WithValue(context.TODO(), spanParentKey{}, "provision")
+
+	_, err := client.PowerStateGet(ctx)
+	assert.Nil(t, err)
+
+	// one span per call, created from the incoming context and ended
+	assert.Equal(t, 1, len(tracer.spans))
+
+	span := tracer.spans[0]
+	assert.Equal(t, "GET api/chassis-status", span.name)
+	assert.Equal(t, "provision", span.parent)
+	assert.True(t, span.ended)
+	assert.Nil(t, span.err)
+	assert.Equal(t, map[string]interface{}{
+		"bmc.host":         serverURL.Host,
+		"http.method":      "GET",
+		"http.endpoint":    "api/chassis-status",
+		"http.status_code": http.StatusOK,
+	}, span.attributes)
+
+	// unsupported endpoint
+	_, err = client.firmwareInfo(ctx)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 2, len(tracer.spans))
+
+	span = tracer.spans[1]
+	assert.Equal(t, "GET api/asrr/fw-info", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, http.StatusNotFound, span.attributes["http.status_code"])
+}
+
+func Test_WithoutTracer(t *testing.T) {
+	client := NewWithOptions("127.0.0.1", "foo", "bar", logr.Discard())
+
+	ctx := context.TODO()
+	spanCtx, end := client.startSpan(ctx, "GET", "api/sensors")
+
+	// the context is returned as is when no tracer is set
+	assert.Equal(t, ctx, spanCtx)
+	end(http.StatusOK, nil)
+}
@@ -0,0 +1,62 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bmc-toolbox/common"
+	"github.com/pkg/errors"
+)
+
+const uptimeEndpoint = "api/status/uptime"
+
+// bmcUptime is the BMC uptime payload, the uptime is in seconds since the BMC booted
+// { "uptime": 86400 }
+type bmcUptime struct {
+	Uptime int64 `json:"uptime"`
+}
+
+// GetBMCUptime returns the time since the BMC booted, the BMC last boot is the current time less the uptime.
+//
+// A recently restarted BMC may explain transient request failures, e.g after a firmware install or a watchdog reset.
+// ErrBMCUptimeUnsupported is returned when the BMC firmware does not report the uptime.
+func (a *ASRockRack) GetBMCUptime(ctx context.Context) (time.Duration, error) {
+	resp, statusCode, err := a.getWithRetry(ctx, uptimeEndpoint)
+	if err != nil {
+		return 0, errors.Wrap(ErrBMCUptimeRead, err.Error())
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return 0, wrapStatusCodeError(ErrBMCUptimeUnsupported, statusCode)
+	default:
+		return 0, wrapStatusCodeError(ErrBMCUptimeRead, statusCode)
+	}
+
+	uptime := &bmcUptime{}
+	if err := unmarshalTolerant(resp, uptime); err != nil {
+		return 0, errors.Wrap(ErrBMCUptimeRead, err.Error())
+	}
+
+	if uptime.Uptime < 0 {
+		return 0, errors.Wrap(ErrBMCUptimeRead, "negative uptime: "+strconv.FormatInt(uptime.Uptime, 10))
+	}
+
+	return time.Duration(uptime.Uptime) * time.Second, nil
+}
+
+// setBMCUptimeMetadata sets the bmc.uptime metadata in seconds and the bmc.last_boot time,
+// errors are logged and ignored since the uptime is not reported by all firmware revisions.
+func (a *ASRockRack) setBMCUptimeMetadata(ctx context.Context, device *common.Device) {
+	uptime, err := a.GetBMCUptime(ctx)
+	if err != nil {
+		a.log.V(2).Error(err, "unable to collect BMC uptime, skipped")
+		return
+	}
+
+	device.Metadata["bmc.uptime"] = strconv.FormatInt(int64(uptime/time.Second), 10)
+	device.Metadata["bmc.last_boot"] = time.Now().Add(-uptime).UTC().Truncate(time.Second).Format(time.RFC3339)
+}
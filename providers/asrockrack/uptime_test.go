@@ -0,0 +1,110 @@
+package asrockrack
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bmc-toolbox/bmclib/v2/providers/asrockrack/asrockracktest"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GetBMCUptime(t *testing.T) {
+	testCases := []struct {
+		name     string
+		options  []asrockracktest.Option
+		expected time.Duration
+		err      error
+	}{
+		{
+			"uptime",
+			[]asrockracktest.Option{
+				asrockracktest.WithResponse(uptimeEndpoint, http.StatusOK, []byte(`{ "uptime": 93784 }`)),
+			},
+			26*time.Hour + 3*time.Minute + 4*time.Second,
+			nil,
+		},
+		{
+			"negative uptime",
+			[]asrockracktest.Option{
+				asrockracktest.WithResponse(uptimeEndpoint, http.StatusOK, []byte(`{ "uptime": -1 }`)),
+			},
+			0,
+			ErrBMCUptimeRead,
+		},
+		{
+			"server error",
+			[]asrockracktest.Option{
+				asrockracktest.WithResponse(uptimeEndpoint, http.StatusInternalServerError, []byte(`{}`)),
+			},
+			0,
+			ErrBMCUptimeRead,
+		},
+		{
+			// the uptime endpoint is not available
+			"unsupported",
+			nil,
+			0,
+			ErrBMCUptimeUnsupported,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := asrockracktest.NewServer(tc.options...)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+			uptime, err := client.GetBMCUptime(context.TODO())
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, uptime)
+		})
+	}
+}
+
+func Test_InventoryBMCUptime(t *testing.T) {
+	server := asrockracktest.NewServer(
+		asrockracktest.WithResponse(uptimeEndpoint, http.StatusOK, []byte(`{ "uptime": 3600 }`)),
+	)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	// the uptime is not included by default
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	device, err := client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotContains(t, device.Metadata, "bmc.uptime")
+	assert.NotContains(t, device.Metadata, "bmc.last_boot")
+
+	client = NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify(), WithBMCUptimeMetadata())
+
+	device, err = client.Inventory(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "3600", device.Metadata["bmc.uptime"])
+
+	lastBoot, err := time.Parse(time.RFC3339, device.Metadata["bmc.last_boot"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), lastBoot, time.Minute)
+}
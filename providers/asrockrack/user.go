@@ -4,18 +4,47 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"unicode"
 
 	"github.com/pkg/errors"
 
 	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
-	"github.com/bmc-toolbox/bmclib/v2/internal"
 )
 
 var (
 	// TODO: standardize these across Redfish, IPMI, Vendor GUI
-	validRoles = []string{"Administrator", "Operator", "User"}
+	//
+	// userRoles maps the lowercased role, and its aliases to the BMC account privilege
+	userRoles = map[string]userRole{
+		"administrator": {privilege: "administrator", remoteConsole: true},
+		"admin":         {privilege: "administrator", remoteConsole: true},
+		"operator":      {privilege: "operator", remoteConsole: true},
+		"user":          {privilege: "user", remoteConsole: true},
+		// the BMC has no read only privilege, a read only account is a user without KVM, virtual media access
+		"readonly": {privilege: "user", remoteConsole: false},
+	}
+
+	// characters permitted in a username, apart from alphanumerics
+	userNameSpecialChars = "-_.@"
+)
+
+const (
+	// BMC password policy
+	passwordMinLength     = 8
+	passwordMaxLength     = 20
+	passwordMaxLength16   = 16 // passwords longer than this require the 20 byte password size
+	userNameMaxLength     = 16
+	passwordSize16        = "bytes_16"
+	passwordSize20        = "bytes_20"
+	reservedAccountSlotID = 1 // ASRR BMCs have a reserved slot 1 for a disabled Anonymous, no idea why.
 )
 
+// userRole is the BMC account privilege and access for a user role
+type userRole struct {
+	privilege     string
+	remoteConsole bool // KVM and virtual media access
+}
+
 // UserAccount is a ASRR BMC user account struct
 type UserAccount struct {
 	ID                           int    `json:"id"`
@@ -72,14 +101,40 @@ func (a *ASRockRack) UserRead(ctx context.Context) (users []map[string]string, e
 	return users, nil
 }
 
+// UserList returns the configured user accounts, the account passwords are not included.
+func (a *ASRockRack) UserList(ctx context.Context) ([]*UserAccount, error) {
+	accounts, err := a.listUsers(ctx)
+	if err != nil {
+		return nil, errors.Wrap(bmclibErrs.ErrRetrievingUserAccounts, err.Error())
+	}
+
+	configured := []*UserAccount{}
+	for _, account := range accounts {
+		if account.Name == "" {
+			continue
+		}
+
+		account.Password = ""
+		account.ConfirmPassword = ""
+		configured = append(configured, account)
+	}
+
+	return configured, nil
+}
+
 // UserCreate adds a new user account
+//
+// The role is one of Administrator (Admin), Operator, User, ReadOnly - matched case insensitively,
+// the username and password are validated against the BMC policy before the account is created.
+// ErrInsufficientPrivilege is returned when the session user is not an administrator.
 func (a *ASRockRack) UserCreate(ctx context.Context, user, pass, role string) (ok bool, err error) {
-	if !internal.StringInSlice(role, validRoles) {
-		return false, bmclibErrs.ErrInvalidUserRole
+	accountRole, err := validateUserParams(user, pass, role)
+	if err != nil {
+		return false, err
 	}
 
-	if user == "" || pass == "" || role == "" {
-		return false, bmclibErrs.ErrUserParamsRequired
+	if err := a.requirePrivilege("user create", privilegeAdministrator); err != nil {
+		return false, err
 	}
 
 	// fetch current list of accounts
@@ -90,8 +145,7 @@ func (a *ASRockRack) UserCreate(ctx context.Context, user, pass, role string) (o
 
 	// identify account slot not in use
 	for _, account := range accounts {
-		// ASRR BMCs have a reserved slot 1 for a disabled Anonymous, no idea why.
-		if account.ID == 1 {
+		if account.ID == reservedAccountSlotID {
 			continue
 		}
 
@@ -101,7 +155,7 @@ func (a *ASRockRack) UserCreate(ctx context.Context, user, pass, role string) (o
 		}
 
 		if account.Access == 0 && account.Name == "" {
-			newAccount := newUserAccount(account.ID, user, pass, strings.ToLower(role))
+			newAccount := newUserAccount(account.ID, user, pass, accountRole)
 			err := a.createUpdateUser(ctx, newAccount)
 			if err != nil {
 				return false, err
@@ -113,16 +167,15 @@ func (a *ASRockRack) UserCreate(ctx context.Context, user, pass, role string) (o
 	return false, bmclibErrs.ErrNoUserSlotsAvailable
 }
 
-//
-
 // UserUpdate updates a user password and role
 func (a *ASRockRack) UserUpdate(ctx context.Context, user, pass, role string) (ok bool, err error) {
-	if !internal.StringInSlice(role, validRoles) {
-		return false, bmclibErrs.ErrInvalidUserRole
+	accountRole, err := validateUserParams(user, pass, role)
+	if err != nil {
+		return false, err
 	}
 
-	if user == "" || pass == "" || role == "" {
-		return false, bmclibErrs.ErrUserParamsRequired
+	if err := a.requirePrivilege("user update", privilegeAdministrator); err != nil {
+		return false, err
 	}
 
 	accounts, err := a.listUsers(ctx)
@@ -130,19 +183,17 @@ func (a *ASRockRack) UserUpdate(ctx context.Context, user, pass, role string) (o
 		return false, errors.Wrap(bmclibErrs.ErrRetrievingUserAccounts, err.Error())
 	}
 
-	role = strings.ToLower(role)
-
 	// identify account slot not in use
 	for _, account := range accounts {
 		account := account
 		if account.Name == user {
-			user := newUserAccount(account.ID, user, pass, role)
+			user := newUserAccount(account.ID, user, pass, accountRole)
 
 			user.AccessByChannel = account.AccessByChannel
 			user.PrivilegeByChannel = account.PrivilegeByChannel
-			user.Privilege = role
+			user.Privilege = accountRole.privilege
 
-			if role == "administrator" {
+			if accountRole.privilege == "administrator" {
 				user.PrivilegeLimitSerial = "none"
 				user.UserOperation = 1
 				user.CreationTime = 6000 // doesn't mean anything.
@@ -160,27 +211,132 @@ func (a *ASRockRack) UserUpdate(ctx context.Context, user, pass, role string) (o
 	return ok, errors.Wrap(bmclibErrs.ErrUserAccountNotFound, user)
 }
 
+// UserDelete removes the user account
+func (a *ASRockRack) UserDelete(ctx context.Context, user string) (ok bool, err error) {
+	if user == "" {
+		return false, bmclibErrs.ErrUserParamsRequired
+	}
+
+	if err := a.requirePrivilege("user delete", privilegeAdministrator); err != nil {
+		return false, err
+	}
+
+	accounts, err := a.listUsers(ctx)
+	if err != nil {
+		return false, errors.Wrap(bmclibErrs.ErrRetrievingUserAccounts, err.Error())
+	}
+
+	for _, account := range accounts {
+		if account.ID == reservedAccountSlotID || account.Name != user {
+			continue
+		}
+
+		if err := a.deleteUser(ctx, account.ID); err != nil {
+			return false, errors.Wrap(ErrUserAccountDelete, err.Error())
+		}
+
+		return true, nil
+	}
+
+	return false, errors.Wrap(bmclibErrs.ErrUserAccountNotFound, user)
+}
+
+// validateUserParams validates the user account parameters against the BMC policy
+// and returns the account role for the given role name.
+func validateUserParams(user, pass, role string) (userRole, error) {
+	accountRole, exists := userRoles[strings.ToLower(role)]
+	if !exists {
+		return userRole{}, bmclibErrs.ErrInvalidUserRole
+	}
+
+	if user == "" || pass == "" || role == "" {
+		return userRole{}, bmclibErrs.ErrUserParamsRequired
+	}
+
+	if err := validateUserName(user); err != nil {
+		return userRole{}, err
+	}
+
+	if err := validatePassword(user, pass); err != nil {
+		return userRole{}, err
+	}
+
+	return accountRole, nil
+}
+
+// validateUserName validates the username against the BMC policy,
+// a username begins with a letter, is at most 16 characters long and contains only alphanumerics and -_.@
+func validateUserName(user string) error {
+	if len(user) > userNameMaxLength {
+		return errors.Wrap(ErrUserNamePolicy, fmt.Sprintf("username must be at most %d characters", userNameMaxLength))
+	}
+
+	if !unicode.IsLetter(rune(user[0])) {
+		return errors.Wrap(ErrUserNamePolicy, "username must begin with a letter")
+	}
+
+	for _, c := range user {
+		if c > unicode.MaxASCII || !(unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune(userNameSpecialChars, c)) {
+			return errors.Wrap(ErrUserNamePolicy, "username may only contain alphanumerics and the characters "+userNameSpecialChars)
+		}
+	}
+
+	return nil
+}
+
+// validatePassword validates the password against the BMC policy,
+// a password is 8 to 20 characters long, contains no whitespace and differs from the username.
+func validatePassword(user, pass string) error {
+	if len(pass) < passwordMinLength || len(pass) > passwordMaxLength {
+		return errors.Wrap(
+			ErrPasswordPolicy,
+			fmt.Sprintf("password must be %d to %d characters long", passwordMinLength, passwordMaxLength),
+		)
+	}
+
+	if strings.IndexFunc(pass, unicode.IsSpace) != -1 {
+		return errors.Wrap(ErrPasswordPolicy, "password must not contain whitespace")
+	}
+
+	if pass == user {
+		return errors.Wrap(ErrPasswordPolicy, "password must differ from the username")
+	}
+
+	return nil
+}
+
 // newUserAccount returns a user account object populated with the given attributes and certain defaults
 //
 // note: the role parameter must be validated before being passed to this constructor
-func newUserAccount(id int, user, pass, role string) *UserAccount {
-	return &UserAccount{
+func newUserAccount(id int, user, pass string, role userRole) *UserAccount {
+	account := &UserAccount{
 		ID:                           id,
 		Name:                         user,
 		Access:                       1, // Access enabled
 		Kvm:                          1,
 		Vmedia:                       1,
-		NetworkPrivilege:             role,
+		NetworkPrivilege:             role.privilege,
 		FixedUserCount:               2, // No idea what this is about
 		OEMProprietaryLevelPrivilege: 1,
-		PrivilegeLimitSerial:         role,
+		PrivilegeLimitSerial:         role.privilege,
 		SSHKey:                       "Not Available",
 		CreationTime:                 0,
 		Changepassword:               1,
 		UserOperation:                0,
 		Password:                     pass,
 		ConfirmPassword:              pass,
-		PasswordSize:                 "bytes_16", // bytes_20 for larger passwords
+		PasswordSize:                 passwordSize16,
 		EmailFormat:                  "AMI-Format",
 	}
+
+	if len(pass) > passwordMaxLength16 {
+		account.PasswordSize = passwordSize20
+	}
+
+	if !role.remoteConsole {
+		account.Kvm = 0
+		account.Vmedia = 0
+	}
+
+	return account
 }
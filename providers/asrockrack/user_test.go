@@ -2,11 +2,17 @@ package asrockrack
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
 
 	bmclibErrs "github.com/bmc-toolbox/bmclib/v2/errors"
@@ -92,7 +98,7 @@ func Test_UserCreate(t *testing.T) {
 	tests = append(tests,
 		[]testCase{{
 			"root",
-			"calvin",
+			"calvin123",
 			"Administrator",
 			true,
 			nil,
@@ -100,7 +106,7 @@ func Test_UserCreate(t *testing.T) {
 		},
 			{
 				"admin",
-				"foo",
+				"foobar123",
 				"Administrator",
 				false,
 				bmclibErrs.ErrUserAccountExists,
@@ -127,7 +133,7 @@ func Test_UserUpdate(t *testing.T) {
 		[]testCase{
 			{
 				"admin",
-				"calvin",
+				"calvin123",
 				"Administrator",
 				true,
 				nil,
@@ -135,7 +141,7 @@ func Test_UserUpdate(t *testing.T) {
 			},
 			{
 				"badmin",
-				"calvin",
+				"calvin123",
 				"Administrator",
 				false,
 				bmclibErrs.ErrUserAccountNotFound,
@@ -233,3 +239,144 @@ func Test_userAccounts(t *testing.T) {
 	assert.Equal(t, 10, len(accounts))
 	assert.Equal(t, account0, accounts[0])
 }
+
+func Test_UserDelete(t *testing.T) {
+	err := aClient.httpsLogin(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+
+	ok, err := aClient.UserDelete(context.TODO(), "foo")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, err = aClient.UserDelete(context.TODO(), "badmin")
+	assert.ErrorIs(t, err, bmclibErrs.ErrUserAccountNotFound)
+
+	// the reserved anonymous account is not removed
+	_, err = aClient.UserDelete(context.TODO(), "anonymous")
+	assert.ErrorIs(t, err, bmclibErrs.ErrUserAccountNotFound)
+}
+
+func Test_UserList(t *testing.T) {
+	err := aClient.httpsLogin(context.TODO())
+	if err != nil {
+		t.Error(err)
+	}
+
+	accounts, err := aClient.UserList(context.TODO())
+	assert.Nil(t, err)
+
+	names := []string{}
+	for _, account := range accounts {
+		names = append(names, account.Name)
+	}
+
+	assert.Equal(t, []string{"anonymous", "admin", "foo"}, names)
+}
+
+func Test_validateUserParams(t *testing.T) {
+	testCases := []struct {
+		name     string
+		user     string
+		pass     string
+		role     string
+		expected userRole
+		err      error
+	}{
+		{"admin alias", "root", "calvin123", "admin", userRole{privilege: "administrator", remoteConsole: true}, nil},
+		{"operator", "root", "calvin123", "Operator", userRole{privilege: "operator", remoteConsole: true}, nil},
+		{"read only", "root", "calvin123", "ReadOnly", userRole{privilege: "user", remoteConsole: false}, nil},
+		{"unknown role", "root", "calvin123", "callback", userRole{}, bmclibErrs.ErrInvalidUserRole},
+		{"username too long", "averyveryverylongname", "calvin123", "User", userRole{}, ErrUserNamePolicy},
+		{"username begins with a digit", "1root", "calvin123", "User", userRole{}, ErrUserNamePolicy},
+		{"username invalid characters", "ro ot", "calvin123", "User", userRole{}, ErrUserNamePolicy},
+		{"password too short", "root", "calvin", "User", userRole{}, ErrPasswordPolicy},
+		{"password too long", "root", strings.Repeat("a", 21), "User", userRole{}, ErrPasswordPolicy},
+		{"password whitespace", "root", "calvin 123", "User", userRole{}, ErrPasswordPolicy},
+		{"password matches username", "rootroot", "rootroot", "User", userRole{}, ErrPasswordPolicy},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			role, err := validateUserParams(tc.user, tc.pass, tc.role)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, role)
+		})
+	}
+}
+
+// mockUserAccountsBMC returns a BMC mock with the given number of account slots, of which the first used are configured.
+func mockUserAccountsBMC(slots, used int) (*httptest.Server, map[int]*UserAccount) {
+	accounts := map[int]*UserAccount{}
+	for id := 1; id <= slots; id++ {
+		accounts[id] = &UserAccount{ID: id}
+		if id <= used {
+			accounts[id].Name = fmt.Sprintf("user%d", id)
+			accounts[id].Access = 1
+		}
+	}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/settings/users", func(w http.ResponseWriter, r *http.Request) {
+		list := []*UserAccount{}
+		for id := 1; id <= slots; id++ {
+			list = append(list, accounts[id])
+		}
+
+		b, _ := json.Marshal(list)
+		_, _ = w.Write(b)
+	})
+
+	handler.HandleFunc("/api/settings/users/", func(w http.ResponseWriter, r *http.Request) {
+		account := &UserAccount{}
+		if err := json.NewDecoder(r.Body).Decode(account); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.URL.Path != fmt.Sprintf("/api/settings/users/%d", account.ID) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		accounts[account.ID] = account
+	})
+
+	return httptest.NewTLSServer(handler), accounts
+}
+
+func Test_UserCreateSlots(t *testing.T) {
+	// a free slot is available
+	server, accounts := mockUserAccountsBMC(4, 2)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := NewWithOptions(serverURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	ok, err := client.UserCreate(context.TODO(), "viewer", "a-long-password-1", "ReadOnly")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	assert.Equal(t, "viewer", accounts[3].Name)
+	assert.Equal(t, "user", accounts[3].NetworkPrivilege)
+	assert.Equal(t, 0, accounts[3].Kvm)
+	assert.Equal(t, 0, accounts[3].Vmedia)
+	assert.Equal(t, passwordSize20, accounts[3].PasswordSize)
+
+	// all slots are in use
+	fullServer, _ := mockUserAccountsBMC(4, 4)
+	defer fullServer.Close()
+
+	fullServerURL, _ := url.Parse(fullServer.URL)
+	client = NewWithOptions(fullServerURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	ok, err = client.UserCreate(context.TODO(), "viewer", "calvin123", "ReadOnly")
+	assert.ErrorIs(t, err, bmclibErrs.ErrNoUserSlotsAvailable)
+	assert.False(t, ok)
+}
@@ -0,0 +1,251 @@
+package asrockrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// remoteMediaConfiguration is the payload for the remote media configuration endpoint,
+// the attributes are specific to the image type - cd, fd, hd.
+type remoteMediaConfiguration map[string]interface{}
+
+// remoteMediaImage is part of the payload returned by the remote media images endpoint
+type remoteMediaImage struct {
+	ID                int    `json:"id"`
+	ImageName         string `json:"image_name"`
+	ImageType         string `json:"image_type"`
+	ImageIndex        int    `json:"image_index"`
+	RedirectionStatus int    `json:"redirection_status"` // 1 indicates the image is mounted
+}
+
+// remoteMediaAction is the payload for the remote media start/stop endpoints
+type remoteMediaAction struct {
+	ImageName  string `json:"image_name"`
+	ImageType  string `json:"image_type"`
+	ImageIndex int    `json:"image_index"`
+}
+
+// SetVirtualMedia mounts the given media URL as the media kind, an empty mediaURL unmounts the media kind.
+//
+// kind is one of CD, DVD, Floppy, USBStick.
+func (a *ASRockRack) SetVirtualMedia(ctx context.Context, kind string, mediaURL string) (ok bool, err error) {
+	err = a.UnmountVirtualMedia(ctx, kind)
+	if err != nil {
+		return false, err
+	}
+
+	if mediaURL == "" {
+		return true, nil
+	}
+
+	err = a.MountVirtualMedia(ctx, kind, mediaURL)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// MountVirtualMedia mounts the image at the given URL as the media type over the BMC remote media.
+//
+// mediaType is one of CD, DVD, Floppy, USBStick, the URL scheme is one of nfs, cifs, smb, http, https.
+func (a *ASRockRack) MountVirtualMedia(ctx context.Context, mediaType, mediaURL string) error {
+	imageType, err := remoteMediaImageType(mediaType)
+	if err != nil {
+		return err
+	}
+
+	imageURL, err := url.Parse(mediaURL)
+	if err != nil {
+		return errors.Wrap(ErrVirtualMedia, err.Error())
+	}
+
+	shareType, err := remoteMediaShareType(imageURL.Scheme)
+	if err != nil {
+		return err
+	}
+
+	images, err := a.remoteMediaImages(ctx)
+	if err != nil {
+		return errors.Wrap(ErrVirtualMedia, err.Error())
+	}
+
+	for _, image := range images {
+		if image.ImageType == imageType && image.RedirectionStatus == 1 {
+			return errors.Wrap(ErrVirtualMediaSlotOccupied, image.ImageName)
+		}
+	}
+
+	imageName := path.Base(imageURL.Path)
+	prefix := strings.ToLower(imageType)
+
+	config := remoteMediaConfiguration{
+		"remote_media_support":            1,
+		"mount_" + prefix:                 1,
+		prefix + "_remote_server_address": imageURL.Host,
+		prefix + "_remote_source_path":    path.Dir(imageURL.Path),
+		prefix + "_remote_share_type":     shareType,
+		prefix + "_remote_domain_name":    "",
+		prefix + "_remote_user_name":      imageURL.User.Username(),
+		prefix + "_remote_password":       "",
+	}
+
+	if password, set := imageURL.User.Password(); set {
+		config[prefix+"_remote_password"] = password
+	}
+
+	err = a.remoteMediaConfigure(ctx, config)
+	if err != nil {
+		return errors.Wrap(ErrVirtualMedia, err.Error())
+	}
+
+	action := &remoteMediaAction{ImageName: imageName, ImageType: imageType}
+
+	err = a.remoteMediaAction(ctx, "api/settings/media/remote/start-media", action)
+	if err != nil {
+		return errors.Wrap(ErrVirtualMediaUnreachable, err.Error())
+	}
+
+	if a.dryRun {
+		return nil
+	}
+
+	// the BMC returns the image as mounted only when it was able to reach it
+	images, err = a.remoteMediaImages(ctx)
+	if err != nil {
+		return errors.Wrap(ErrVirtualMedia, err.Error())
+	}
+
+	for _, image := range images {
+		if image.ImageType == imageType && image.ImageName == imageName && image.RedirectionStatus == 1 {
+			return nil
+		}
+	}
+
+	return errors.Wrap(ErrVirtualMediaUnreachable, mediaURL)
+}
+
+// UnmountVirtualMedia unmounts the image mounted as the media type, if any.
+//
+// mediaType is one of CD, DVD, Floppy, USBStick.
+func (a *ASRockRack) UnmountVirtualMedia(ctx context.Context, mediaType string) error {
+	imageType, err := remoteMediaImageType(mediaType)
+	if err != nil {
+		return err
+	}
+
+	images, err := a.remoteMediaImages(ctx)
+	if err != nil {
+		return errors.Wrap(ErrVirtualMedia, err.Error())
+	}
+
+	for _, image := range images {
+		if image.ImageType != imageType || image.RedirectionStatus != 1 {
+			continue
+		}
+
+		action := &remoteMediaAction{ImageName: image.ImageName, ImageType: image.ImageType, ImageIndex: image.ImageIndex}
+
+		err = a.remoteMediaAction(ctx, "api/settings/media/remote/stop-media", action)
+		if err != nil {
+			return errors.Wrap(ErrVirtualMedia, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// remoteMediaImageType returns the BMC remote media image type for the media type
+func remoteMediaImageType(mediaType string) (string, error) {
+	switch mediaType {
+	case "CD", "DVD":
+		return "CD", nil
+	case "Floppy":
+		return "FD", nil
+	case "USBStick":
+		return "HD", nil
+	default:
+		return "", errors.Wrap(ErrVirtualMedia, "media type unsupported: "+mediaType)
+	}
+}
+
+// remoteMediaShareType returns the BMC remote media share type for the URL scheme
+func remoteMediaShareType(scheme string) (string, error) {
+	switch strings.ToLower(scheme) {
+	case "nfs":
+		return "nfs", nil
+	case "cifs", "smb":
+		return "cifs", nil
+	case "http", "https":
+		return strings.ToLower(scheme), nil
+	default:
+		return "", errors.Wrap(ErrVirtualMedia, "media URL scheme unsupported: "+scheme)
+	}
+}
+
+// Query the remote media images endpoint
+func (a *ASRockRack) remoteMediaImages(ctx context.Context) ([]*remoteMediaImage, error) {
+	resp, statusCode, err := a.queryHTTPS(ctx, "api/settings/media/remote/images", "GET", nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, statusCodeError(statusCode)
+	}
+
+	images := []*remoteMediaImage{}
+	err = json.Unmarshal(resp, &images)
+	if err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+// Set the remote media configuration
+func (a *ASRockRack) remoteMediaConfigure(ctx context.Context, config remoteMediaConfiguration) error {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	_, statusCode, err := a.queryHTTPS(ctx, "api/settings/media/remote/configurations", "PUT", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return statusCodeError(statusCode)
+	}
+
+	return nil
+}
+
+// Start or stop the remote media redirection for an image
+func (a *ASRockRack) remoteMediaAction(ctx context.Context, endpoint string, action *remoteMediaAction) error {
+	payload, err := json.Marshal(action)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	resp, statusCode, err := a.queryHTTPS(ctx, endpoint, "POST", bytes.NewReader(payload), headers, 0)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return wrapStatusCodeError(errors.New(string(resp)), statusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package asrockrack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	remoteMediaImagesResponse = []byte(`[ { "id": 1, "image_name": "", "image_type": "CD", "image_index": 0, "redirection_status": 0, "session_index": 0 }, { "id": 2, "image_name": "", "image_type": "FD", "image_index": 0, "redirection_status": 0, "session_index": 0 }, { "id": 3, "image_name": "", "image_type": "HD", "image_index": 0, "redirection_status": 0, "session_index": 0 } ]`)
+)
+
+// mockRemoteMediaBMC returns a BMC mock which mounts images from the configured
+// server address, unless the address is unreachable.example.org
+func mockRemoteMediaBMC(t *testing.T) *httptest.Server {
+	images := []*remoteMediaImage{}
+	if err := json.Unmarshal(remoteMediaImagesResponse, &images); err != nil {
+		t.Fatal(err)
+	}
+
+	config := remoteMediaConfiguration{}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/api/settings/media/remote/images", func(w http.ResponseWriter, r *http.Request) {
+		b, _ := json.Marshal(images)
+		_, _ = w.Write(b)
+	})
+
+	handler.HandleFunc("/api/settings/media/remote/configurations", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	handleAction := func(mount bool) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			action := &remoteMediaAction{}
+			if err := json.NewDecoder(r.Body).Decode(action); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			for _, image := range images {
+				if image.ImageType != action.ImageType {
+					continue
+				}
+
+				if !mount {
+					image.ImageName = ""
+					image.RedirectionStatus = 0
+					return
+				}
+
+				if config["cd_remote_server_address"] == "unreachable.example.org" {
+					return
+				}
+
+				image.ImageName = action.ImageName
+				image.RedirectionStatus = 1
+			}
+		}
+	}
+
+	handler.HandleFunc("/api/settings/media/remote/start-media", handleAction(true))
+	handler.HandleFunc("/api/settings/media/remote/stop-media", handleAction(false))
+
+	return httptest.NewTLSServer(handler)
+}
+
+func Test_VirtualMedia(t *testing.T) {
+	mediaServer := mockRemoteMediaBMC(t)
+	defer mediaServer.Close()
+
+	mediaURL, _ := url.Parse(mediaServer.URL)
+	client := NewWithOptions(mediaURL.Host, "foo", "bar", logr.Discard(), WithInsecureSkipVerify())
+
+	ctx := context.TODO()
+
+	// unsupported media type
+	err := client.MountVirtualMedia(ctx, "Tape", "nfs://10.0.0.1/images/ubuntu.iso")
+	assert.ErrorIs(t, err, ErrVirtualMedia)
+
+	// unreachable image
+	err = client.MountVirtualMedia(ctx, "CD", "nfs://unreachable.example.org/images/ubuntu.iso")
+	assert.ErrorIs(t, err, ErrVirtualMediaUnreachable)
+
+	// mount
+	err = client.MountVirtualMedia(ctx, "CD", "nfs://10.0.0.1/images/ubuntu.iso")
+	assert.Nil(t, err)
+
+	images, err := client.remoteMediaImages(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "ubuntu.iso", images[0].ImageName)
+	assert.Equal(t, 1, images[0].RedirectionStatus)
+
+	// slot occupied
+	err = client.MountVirtualMedia(ctx, "DVD", "nfs://10.0.0.1/images/debian.iso")
+	assert.ErrorIs(t, err, ErrVirtualMediaSlotOccupied)
+
+	// a different slot is available
+	err = client.MountVirtualMedia(ctx, "USBStick", "http://10.0.0.1/images/disk.img")
+	assert.Nil(t, err)
+
+	// SetVirtualMedia replaces the mounted image
+	ok, err := client.SetVirtualMedia(ctx, "CD", "nfs://10.0.0.1/images/debian.iso")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	images, err = client.remoteMediaImages(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "debian.iso", images[0].ImageName)
+
+	// unmount
+	err = client.UnmountVirtualMedia(ctx, "CD")
+	assert.Nil(t, err)
+
+	images, err = client.remoteMediaImages(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, images[0].RedirectionStatus)
+	assert.Equal(t, 1, images[2].RedirectionStatus)
+}